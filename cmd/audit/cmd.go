@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit log",
+		Long:  "List the changes of clusters and configuration entries recorded in the audit log (latest changes first).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	cmd.Flags().StringVar(&o.Actor, "actor", "", "Show only changes of this actor")
+	cmd.Flags().StringVar(&o.Action, "action", "", "Show only changes of this action, e.g. cluster.delete")
+	cmd.Flags().StringVar(&o.Entity, "entity", "", "Show only changes of this entity type, e.g. cluster, key, value or bucket")
+	cmd.Flags().StringVar(&o.EntityID, "entity-id", "", "Show only changes of the entity with this identifier")
+	cmd.Flags().DurationVar(&o.Since, "since", 0, "Show only changes of this time period, e.g. 24h")
+	cmd.Flags().Int64Var(&o.BeforeID, "before", 0, "Show only changes which are older than the audit entry with this ID")
+	cmd.Flags().IntVar(&o.Limit, "limit", o.Limit, "Maximal amount of shown changes")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output-format", "o", "table",
+		fmt.Sprintf("Define output formatting. Supported options are '%s'.", strings.Join(cli.SupportedOutputFormats, "', '")))
+	return cmd
+}
+
+func Run(o *Options) error {
+	entries, err := o.Registry.AuditRepository().Entries(o.Filter())
+	if err != nil {
+		return err
+	}
+	return renderEntries(o, entries)
+}
+
+func renderEntries(o *Options, entries []*model.AuditEntity) error {
+	formatter, err := cli.NewOutputFormatter(o.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	if err := formatter.Header("ID", "Created at (UTC)", "Actor", "Action", "Entity", "Entity ID",
+		"Before", "After"); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := formatter.AddRow(entry.ID, entry.Created.Format(time.RFC822Z), entry.Actor, entry.Action,
+			entry.Entity, entry.EntityID, entry.BeforeSummary, entry.AfterSummary); err != nil {
+			return err
+		}
+	}
+	return formatter.Output(os.Stdout)
+}
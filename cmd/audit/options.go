@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+)
+
+type Options struct {
+	*cli.Options
+	Actor    string
+	Action   string
+	Entity   string
+	EntityID string
+	Since    time.Duration
+	BeforeID int64
+	Limit    int
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", "", "", "", 0, 0, 50}
+}
+
+func (o *Options) Validate() error {
+	if o.Since < 0 {
+		return fmt.Errorf("Time period of audit entries cannot be negative")
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("Limit of audit entries has to be > 0")
+	}
+	return nil
+}
+
+func (o *Options) Filter() *audit.Filter {
+	filter := &audit.Filter{
+		Actor:    o.Actor,
+		Action:   audit.Action(o.Action),
+		Entity:   o.Entity,
+		EntityID: o.EntityID,
+		BeforeID: o.BeforeID,
+		Limit:    o.Limit,
+	}
+	if o.Since > 0 {
+		filter.Since = time.Now().Add(-o.Since)
+	}
+	return filter
+}
@@ -4,10 +4,14 @@ import (
 	createCmd "github.com/kyma-incubator/reconciler/cmd/config/create"
 	createKeyCmd "github.com/kyma-incubator/reconciler/cmd/config/create/key"
 	createValueCmd "github.com/kyma-incubator/reconciler/cmd/config/create/value"
+	exportCmd "github.com/kyma-incubator/reconciler/cmd/config/export"
 	getCmd "github.com/kyma-incubator/reconciler/cmd/config/get"
 	getBucketCmd "github.com/kyma-incubator/reconciler/cmd/config/get/bucket"
 	getKeyCmd "github.com/kyma-incubator/reconciler/cmd/config/get/key"
 	getValueCmd "github.com/kyma-incubator/reconciler/cmd/config/get/value"
+	importCmd "github.com/kyma-incubator/reconciler/cmd/config/import"
+	restoreCmd "github.com/kyma-incubator/reconciler/cmd/config/restore"
+	restoreValueCmd "github.com/kyma-incubator/reconciler/cmd/config/restore/value"
 	"github.com/kyma-incubator/reconciler/internal/cli"
 	"github.com/spf13/cobra"
 )
@@ -32,5 +36,14 @@ func NewCmd(o *cli.Options) *cobra.Command {
 	createCommand.AddCommand(createKeyCmd.NewCmd(createKeyCmd.NewOptions(o)))
 	createCommand.AddCommand(createValueCmd.NewCmd(createValueCmd.NewOptions(o)))
 
+	//register restore commands
+	restoreCommand := restoreCmd.NewCmd(o)
+	cmd.AddCommand(restoreCommand)
+	restoreCommand.AddCommand(restoreValueCmd.NewCmd(restoreValueCmd.NewOptions(o)))
+
+	//register export and import commands
+	cmd.AddCommand(exportCmd.NewCmd(exportCmd.NewOptions(o)))
+	cmd.AddCommand(importCmd.NewCmd(importCmd.NewOptions(o)))
+
 	return cmd
 }
@@ -21,11 +21,13 @@ func NewCmd(o *Options) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&o.DataType, "data-type", "string", fmt.Sprintf("Define data-type of the key (supported types are %s, %s, %s)",
-		model.String, model.Integer, model.Boolean))
+	cmd.Flags().StringVar(&o.DataType, "data-type", "string", fmt.Sprintf("Define data-type of the key (supported types are %s, %s, %s, %s, %s, %s, %s)",
+		model.String, model.Integer, model.Boolean, model.List, model.Map, model.JSON, model.YAML))
 	cmd.Flags().BoolVar(&o.Encrypted, "encrypted", true, "Key values have to be encrypted")
 	cmd.Flags().StringVar(&o.Validator, "validator", "", "Validator logic executed when setting a new value")
 	cmd.Flags().StringVar(&o.Trigger, "trigger", "", "Trigger function executed when a value was added/changed")
+	cmd.Flags().StringVar(&o.TriggerPhase, "trigger-phase", string(model.TriggerOnChange), fmt.Sprintf("Define when the trigger function is executed (supported phases are %s, %s, %s)",
+		model.TriggerOnChange, model.TriggerPreReconciliation, model.TriggerPostReconciliation))
 
 	if err := cobra.MarkFlagRequired(cmd.Flags(), "data-type"); err != nil {
 		panic(err) //would be an obvious bug and has to lead to a panic
@@ -50,12 +52,17 @@ func createKey(o *Options, key string) (*model.KeyEntity, error) {
 	if err != nil {
 		return nil, err
 	}
+	triggerPhase, err := model.NewTriggerPhase(o.TriggerPhase)
+	if err != nil {
+		return nil, err
+	}
 	return o.Registry.KVRepository().CreateKey(&model.KeyEntity{
-		Key:       key,
-		DataType:  dt,
-		Encrypted: o.Encrypted,
-		Validator: o.Validator,
-		Trigger:   o.Trigger,
-		Username:  "!TODO!", //FIXME
+		Key:          key,
+		DataType:     dt,
+		Encrypted:    o.Encrypted,
+		Validator:    o.Validator,
+		Trigger:      o.Trigger,
+		TriggerPhase: triggerPhase,
+		Username:     "!TODO!", //FIXME
 	})
 }
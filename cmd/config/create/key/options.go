@@ -8,14 +8,15 @@ import (
 
 type Options struct {
 	*cli.Options
-	DataType  string
-	Encrypted bool
-	Validator string
-	Trigger   string
+	DataType     string
+	Encrypted    bool
+	Validator    string
+	Trigger      string
+	TriggerPhase string
 }
 
 func NewOptions(o *cli.Options) *Options {
-	return &Options{o, "", false, "", ""}
+	return &Options{o, "", false, "", "", ""}
 }
 
 func (o *Options) Validate() error {
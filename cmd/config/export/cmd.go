@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export configuration keys and values.",
+		Long:  `Export the latest configuration keys and the values of buckets (all buckets if no bucket is provided) as YAML or JSON document.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return Run(o, args)
+		},
+	}
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "File the document is written to (default is stdout)")
+	cmd.Flags().StringVar(&o.Format, "format", "yaml", "Format of the document: 'yaml' or 'json'")
+	return cmd
+}
+
+func Run(o *Options, buckets []string) error {
+	doc, err := o.Registry.KVRepository().Export(buckets...)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if o.Format == "json" {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		data, err = doc.YAML()
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.File == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(o.File, data, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d keys and %d buckets to '%s'\n", len(doc.Keys), len(doc.Buckets), o.File)
+	return nil
+}
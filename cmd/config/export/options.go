@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+	File   string
+	Format string
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", "yaml"}
+}
+
+func (o *Options) Validate() error {
+	if o.Format != "yaml" && o.Format != "json" {
+		return fmt.Errorf("Export format '%s' not supported - choose between 'yaml' and 'json'", o.Format)
+	}
+	return nil
+}
@@ -54,12 +54,12 @@ func renderKeys(o *Options, keys []*model.KeyEntity) error {
 	}
 
 	if err := formatter.Header("Key", "Data Type", "Encrypted", "Created by",
-		"Created at (UTC)", "Validation", "Trigger", "Version"); err != nil {
+		"Created at (UTC)", "Validation", "Trigger", "Trigger Phase", "Version"); err != nil {
 		return err
 	}
 	for _, key := range keys {
 		if err := formatter.AddRow(key.Key, key.DataType, key.Encrypted, key.Username,
-			key.Created.Format(time.RFC822Z), key.Validator, key.Trigger, key.Version); err != nil {
+			key.Created.Format(time.RFC822Z), key.Validator, key.Trigger, key.TriggerPhase, key.Version); err != nil {
 			return err
 		}
 	}
@@ -73,7 +73,7 @@ func renderKeysWithValues(o *Options, keys []*model.KeyEntity) error {
 	}
 
 	if err := formatter.Header("Key", "Data Type", "Encrypted", "Created by",
-		"Created at (UTC)", "Validation", "Trigger", "Version", "Values"); err != nil {
+		"Created at (UTC)", "Validation", "Trigger", "Trigger Phase", "Version", "Values"); err != nil {
 		return err
 	}
 	for _, key := range keys {
@@ -89,7 +89,7 @@ func renderKeysWithValues(o *Options, keys []*model.KeyEntity) error {
 			kvPairs[value.Bucket] = append(kvPairs[value.Bucket], value.Value)
 		}
 		if err := formatter.AddRow(key.Key, key.DataType, key.Encrypted, key.Username,
-			key.Created.Format(time.RFC822Z), key.Validator, key.Trigger, key.Version, kvPairs); err != nil {
+			key.Created.Format(time.RFC822Z), key.Validator, key.Trigger, key.TriggerPhase, key.Version, kvPairs); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/kv"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import configuration keys and values.",
+		Long: `Import configuration keys and bucket values from a YAML or JSON document (as created by the export command).
+Changed keys and values are created as new versions. All values are validated before anything is written.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if len(args) != 1 {
+				return fmt.Errorf("Exactly one file has to be provided")
+			}
+			return Run(o, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&o.User, "user", "", "User who imports the configuration")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Show which keys and values would be created without importing them")
+	return cmd
+}
+
+func Run(o *Options, file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	doc, err := kv.NewDocument(data)
+	if err != nil {
+		return err
+	}
+
+	result, err := o.Registry.KVRepository().Import(doc, o.User, o.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		fmt.Println("Dry-run: nothing was imported")
+	}
+	return renderResult(o, result)
+}
+
+func renderResult(o *Options, result *kv.ImportResult) error {
+	formatter, err := cli.NewOutputFormatter(o.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	if err := formatter.Header("Type", "Key", "Bucket", "Value", "Action"); err != nil {
+		return err
+	}
+	for _, key := range result.Keys {
+		if err := formatter.AddRow("key", key.Key.Key, "", "", key.Action); err != nil {
+			return err
+		}
+	}
+	for _, value := range result.Values {
+		if err := formatter.AddRow("value", value.Value.Key, value.Value.Bucket, value.Value.Value, value.Action); err != nil {
+			return err
+		}
+	}
+	return formatter.Output(os.Stdout)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+	User   string
+	DryRun bool
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", false}
+}
+
+func (o *Options) Validate() error {
+	if o.User == "" {
+		return fmt.Errorf("User who imports the configuration has to be specified")
+	}
+	return o.Options.Validate()
+}
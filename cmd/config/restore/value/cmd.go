@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "value",
+		Aliases: []string{"values", "va"},
+		Short:   "Restore a configuration value.",
+		Long: `Restore an earlier version of a configuration value by creating a new version with its content.
+The restored value has to pass the validation of the latest key version.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Bucket, "bucket", "", "Bucket of the value")
+	cmd.Flags().StringVar(&o.Key, "key", "", "Key of the value")
+	cmd.Flags().Int64Var(&o.Version, "version", 0, "Version of the value which will be restored")
+	cmd.Flags().StringVar(&o.User, "user", "", "User who restores the value")
+
+	return cmd
+}
+
+func Run(o *Options) error {
+	value, err := o.Registry.KVRepository().RestoreValue(o.Bucket, o.Key, o.Version, o.User)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Value '%s' restored (bucket: %s / key: %s - version %d)\n", value.Value, value.Bucket, value.Key, value.Version)
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+	Key     string
+	Bucket  string
+	Version int64
+	User    string
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, "", "", 0, ""}
+}
+
+func (o *Options) Validate() error {
+	if o.Bucket == "" {
+		return fmt.Errorf("Bucket has to be specified")
+	}
+	if o.Key == "" {
+		return fmt.Errorf("Key has to be specified")
+	}
+	if o.Version <= 0 {
+		return fmt.Errorf("Version of the value which has to be restored has to be specified")
+	}
+	if o.User == "" {
+		return fmt.Errorf("User who restores the value has to be specified")
+	}
+	return nil
+}
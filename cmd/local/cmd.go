@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/metrics"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 
 	//Register all reconcilers
@@ -54,8 +56,12 @@ func RunLocal(o *Options) error {
 	if err != nil {
 		return err
 	}
+	//keep inventory and operations in memory for the lifetime of the local run
+	inventory, err := newInMemoryInventory(o.Verbose)
+	if err != nil {
+		return err
+	}
 	workerFactory, _ := scheduler.NewLocalWorkerFactory(
-		&cluster.MockInventory{},
 		scheduler.NewDefaultOperationsRegistry(),
 		func(component string, status reconciler.Status) {
 			l.Infof("Component %s has status %s", component, status)
@@ -67,6 +73,21 @@ func RunLocal(o *Options) error {
 		KymaConfig: keb.KymaConfig{
 			Version:    o.version,
 			Profile:    o.profile,
-			Components: o.Components()}}, workerFactory, true)
+			Components: o.Components()}}, inventory, workerFactory, true)
 	return ls.Run(context.Background())
 }
+
+func newInMemoryInventory(debug bool) (cluster.Inventory, error) {
+	encKey, err := db.NewEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	connFact := &db.MemoryConnectionFactory{
+		Debug:         debug,
+		EncryptionKey: encKey,
+	}
+	if err := connFact.Init(); err != nil {
+		return nil, err
+	}
+	return cluster.NewInventory(connFact, debug, metrics.NewReconciliationStatusCollector())
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	auditCmd "github.com/kyma-incubator/reconciler/cmd/audit"
 	cfgCmd "github.com/kyma-incubator/reconciler/cmd/config"
 	localCmd "github.com/kyma-incubator/reconciler/cmd/local"
 	msCmd "github.com/kyma-incubator/reconciler/cmd/mothership"
@@ -31,6 +32,7 @@ func main() {
 		"Command line tool to administrate the Kyma reconciler system")
 
 	cmd.AddCommand(cfgCmd.NewCmd(o))
+	cmd.AddCommand(auditCmd.NewCmd(auditCmd.NewOptions(o)))
 	cmd.AddCommand(msCmd.NewCmd(o))
 	cmd.AddCommand(rclCmd.NewCmd(o))
 	cmd.AddCommand(localCmd.NewCmd(localCmd.NewOptions(o)))
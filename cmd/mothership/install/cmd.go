@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	migrateCmd "github.com/kyma-incubator/reconciler/cmd/mothership/migrate"
 	"github.com/kyma-incubator/reconciler/internal/cli"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +19,7 @@ func NewCmd(o *Options) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVar(&o.Backup, "backup", true, "Create a backup of the current encryption key file")
+	cmd.Flags().BoolVar(&o.Migrate, "migrate", true, "Apply pending migrations on the database schema")
 	return cmd
 }
 
@@ -27,6 +29,11 @@ func Run(o *Options) error {
 		o.Logger().Infof("New encryption key file created")
 	} else {
 		o.Logger().Warnf("Failed to create encryption key file")
+		return err
 	}
-	return err
+
+	if o.Migrate {
+		return migrateCmd.Run(migrateCmd.NewOptions(o.Options))
+	}
+	return nil
 }
@@ -6,9 +6,10 @@ import (
 
 type Options struct {
 	*cli.Options
-	Backup bool
+	Backup  bool
+	Migrate bool
 }
 
 func NewOptions(o *cli.Options) *Options {
-	return &Options{o, true}
+	return &Options{o, true, true}
 }
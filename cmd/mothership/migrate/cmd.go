@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the database schema of the mothership reconciler",
+		Long: "Applies all pending schema migrations on the configured database. " +
+			"Use the 'down' flag to revert the latest migrations.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	cmd.Flags().IntVar(&o.Down, "down", 0, "Number of migrations to revert (starting with the latest applied migration)")
+	cmd.Flags().BoolVar(&o.Status, "status", false, "Show the applied and pending migrations without migrating the database")
+	return cmd
+}
+
+func Run(o *Options) error {
+	connFact, err := db.NewConnectionFactory(viper.ConfigFileUsed(), o.Verbose)
+	if err != nil {
+		return err
+	}
+	conn, err := connFact.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			o.Logger().Warnf("Failed to close database connection: %s", err)
+		}
+	}()
+
+	migrator, err := db.NewMigrator(conn, o.Logger())
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case o.Status:
+		return renderStatus(o, migrator)
+	case o.Down > 0:
+		reverted, err := migrator.Down(o.Down)
+		if err != nil {
+			return err
+		}
+		o.Logger().Infof("Database schema migrated down: %d migrations reverted", reverted)
+	default:
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		o.Logger().Infof("Database schema migrated up: %d migrations applied", applied)
+	}
+	return nil
+}
+
+func renderStatus(o *Options, migrator *db.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	formatter, err := cli.NewOutputFormatter(o.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if err := formatter.Header("Version", "Name", "Checksum", "Status"); err != nil {
+		return err
+	}
+	for _, migration := range status {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		if err := formatter.AddRow(migration.Version, migration.Name, migration.Checksum(), state); err != nil {
+			return err
+		}
+	}
+	return formatter.Output(os.Stdout)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+	Down   int
+	Status bool
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o, 0, false}
+}
+
+func (o *Options) Validate() error {
+	if o.Down < 0 {
+		return fmt.Errorf("Number of migrations to revert cannot be negative")
+	}
+	if o.Down > 0 && o.Status {
+		return fmt.Errorf("Flags 'down' and 'status' cannot be combined")
+	}
+	return o.Options.Validate()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const progressInterval = 100
+
+func NewCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Rotate the encryption key of the mothership reconciler",
+		Long: "Creates a new encryption key and re-encrypts all encrypted values in the database. " +
+			"The previous key is kept as backup file. Running mothership reconcilers have to be restarted afterwards. " +
+			"The backup file is not added to the configuration automatically: add it to 'db.encryption.previousKeyFiles' " +
+			"before restarting the mothership reconcilers, otherwise values which were encrypted by reconcilers " +
+			"still running with the previous key cannot be decrypted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return Run(o)
+		},
+	}
+	return cmd
+}
+
+func Run(o *Options) error {
+	keyFileBackup, err := cli.RotateEncryptionKey()
+	if err != nil {
+		o.Logger().Warnf("Failed to create new encryption key file")
+		return err
+	}
+	o.Logger().Infof("New encryption key file created (previous key stored in '%s')", keyFileBackup)
+
+	reEncrypted, err := reEncrypt(o, keyFileBackup)
+	if err != nil {
+		o.Logger().Warnf("Re-encryption of database values failed: restoring previous encryption key")
+		if restoreErr := cli.RestoreEncryptionKey(keyFileBackup); restoreErr != nil {
+			err = errors.Wrap(err, restoreErr.Error())
+		}
+		return err
+	}
+
+	o.Logger().Infof("Encryption key rotated: %d values re-encrypted", reEncrypted)
+	o.Logger().Warnf("Add the previous key file '%s' to 'db.encryption.previousKeyFiles' of the configuration "+
+		"file before restarting the mothership reconcilers", keyFileBackup)
+	return nil
+}
+
+func reEncrypt(o *Options, keyFileBackup string) (int, error) {
+	//add previous key to the key-ring to be able to decrypt existing values (only for this process:
+	//the configuration file is not changed)
+	viper.Set("db.encryption.previousKeyFiles",
+		append(viper.GetStringSlice("db.encryption.previousKeyFiles"), keyFileBackup))
+
+	connFact, err := db.NewConnectionFactory(viper.ConfigFileUsed(), o.Verbose)
+	if err != nil {
+		return 0, err
+	}
+	conn, err := connFact.NewConnection()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			o.Logger().Warnf("Failed to close database connection: %s", err)
+		}
+	}()
+
+	progress := func(field *db.EncryptedField, processed, total int) {
+		if processed%progressInterval == 0 || processed == total {
+			o.Logger().Infof("Re-encryption of '%s': %d/%d values processed", field, processed, total)
+		}
+	}
+	return db.RotateEncryption(conn, model.EncryptedFields(), progress, o.Logger())
+}
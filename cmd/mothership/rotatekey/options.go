@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"github.com/kyma-incubator/reconciler/internal/cli"
+)
+
+type Options struct {
+	*cli.Options
+}
+
+func NewOptions(o *cli.Options) *Options {
+	return &Options{o}
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/auth"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/scheduler"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -13,9 +16,26 @@ const (
 	paramContractVersion = "contractVersion"
 	paramCluster         = "cluster"
 	paramConfigVersion   = "configVersion"
+	paramFromVersion     = "fromVersion"
+	paramToVersion       = "toVersion"
 	paramOffset          = "offset"
 	paramSchedulingID    = "schedulingID"
 	paramCorrelationID   = "correlationID"
+	paramKey             = "key"
+	paramBucket          = "bucket"
+)
+
+var (
+	//readRoles are allowed to retrieve clusters and configuration entries
+	readRoles = []auth.Role{auth.RoleReadOnly, auth.RoleOperator, auth.RoleKEB}
+	//clusterWriteRoles are allowed to create, update and delete clusters
+	clusterWriteRoles = []auth.Role{auth.RoleKEB, auth.RoleOperator}
+	//operatorRoles are allowed to rollback cluster configurations and to change configuration entries
+	operatorRoles = []auth.Role{auth.RoleOperator}
+	//auditRoles are allowed to read the audit log
+	auditRoles = []auth.Role{auth.RoleReadOnly, auth.RoleOperator}
+	//callbackRoles are allowed to report the progress of operations
+	callbackRoles = []auth.Role{auth.RoleReconciler}
 )
 
 func NewCmd(o *Options) *cobra.Command {
@@ -54,14 +74,58 @@ func Run(o *Options) error {
 		}
 	}
 
+	//viper isn't safe for concurrent use: the configuration is read before the components are started
+	cfg, err := parseConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+
 	ctx := cli.NewContext()
 
 	go func(ctx context.Context, o *Options) {
-		err := startScheduler(ctx, o, viper.ConfigFileUsed())
+		err := startScheduler(ctx, o, cfg)
 		if err != nil {
 			panic(err)
 		}
 	}(ctx, o)
 
-	return startWebserver(ctx, o)
+	go func(ctx context.Context, o *Options) {
+		err := startRetentionJob(ctx, o, cfg.retention)
+		if err != nil {
+			panic(err)
+		}
+	}(ctx, o)
+
+	return startWebserver(ctx, o, cfg.auth)
+}
+
+//mothershipConfig contains the settings of the mothership components read from the configuration file
+type mothershipConfig struct {
+	reconciler         reconciler.MothershipReconcilerConfig
+	maintenanceWindows *scheduler.MaintenanceWindows
+	retention          *scheduler.RetentionConfig
+	auth               *auth.Config
+	landscape          string
+}
+
+func parseConfig(configFile string) (*mothershipConfig, error) {
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	maintenanceWindows, err := parseMaintenanceWindowsConfig()
+	if err != nil {
+		return nil, err
+	}
+	authCfg, err := parseAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &mothershipConfig{
+		reconciler:         parseMothershipReconcilerConfig(),
+		maintenanceWindows: maintenanceWindows,
+		retention:          parseRetentionConfig(),
+		auth:               authCfg,
+		landscape:          viper.GetString("mothership.configuration.landscape"),
+	}, nil
 }
@@ -9,49 +9,138 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/kyma-incubator/reconciler/pkg/auth"
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/metrics"
+	"github.com/kyma-incubator/reconciler/pkg/openapi"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/repository"
 	"github.com/kyma-incubator/reconciler/pkg/server"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
 )
 
-func startWebserver(ctx context.Context, o *Options) error {
+func startWebserver(ctx context.Context, o *Options, authCfg *auth.Config) error {
+	secured, err := auth.NewMiddleware(authCfg, o.Logger())
+	if err != nil {
+		return errors.Wrap(err, "Failed to initialize authentication")
+	}
+	validator, err := openapi.NewValidator(o.Logger())
+	if err != nil {
+		return errors.Wrap(err, "Failed to initialize request validation")
+	}
+	//route returns the handler of an API operation (request is authorized first, then its body is validated)
+	route := func(operationID string, handler func(o *Options, w http.ResponseWriter, r *http.Request), roles ...auth.Role) http.HandlerFunc {
+		return secured.Secure(validator.Validate(operationID, callHandler(o, handler)), roles...)
+	}
+
 	//routing
 	router := mux.NewRouter()
+	//request bodies of the cluster model are validated against the schema of their contract version
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s:1}/clusters", paramContractVersion),
+		route("createOrUpdateCluster", createOrUpdateCluster, clusterWriteRoles...)).
+		Methods("PUT", "POST")
 	router.HandleFunc(
-		fmt.Sprintf("/v{%s}/clusters", paramContractVersion),
-		callHandler(o, createOrUpdateCluster)).
+		fmt.Sprintf("/v{%s:2}/clusters", paramContractVersion),
+		route("createOrUpdateClusterV2", createOrUpdateCluster, clusterWriteRoles...)).
 		Methods("PUT", "POST")
 
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/clusters/{%s}", paramContractVersion, paramCluster),
-		callHandler(o, deleteCluster)).
+		route("deleteCluster", deleteCluster, clusterWriteRoles...)).
 		Methods("DELETE")
 
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/clusters/{%s}/configs/{%s}/status", paramContractVersion, paramCluster, paramConfigVersion),
-		callHandler(o, getCluster)).
+		route("getCluster", getCluster, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/clusters/{%s}/configs/{%s}/diff/{%s}", paramContractVersion, paramCluster, paramFromVersion, paramToVersion),
+		route("configDiff", configDiff, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/clusters/{%s}/configs/{%s}/rollback", paramContractVersion, paramCluster, paramConfigVersion),
+		route("rollbackConfig", rollbackConfig, operatorRoles...)).
+		Methods("POST")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/clusters/{%s}/rollbacks", paramContractVersion, paramCluster),
+		route("getRollbacks", getRollbacks, readRoles...)).
 		Methods("GET")
 
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/clusters/{%s}/status", paramContractVersion, paramCluster),
-		callHandler(o, getLatestCluster)).
+		route("getLatestCluster", getLatestCluster, readRoles...)).
 		Methods("GET")
 
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/clusters/{%s}/statusChanges/{%s}", paramContractVersion, paramCluster, paramOffset),
-		callHandler(o, statusChanges)).
+		route("statusChanges", statusChanges, readRoles...)).
 		Methods("GET")
 
 	router.HandleFunc(
 		fmt.Sprintf("/v{%s}/operations/{%s}/callback/{%s}", paramContractVersion, paramSchedulingID, paramCorrelationID),
-		callHandler(o, operationCallback)).
+		route("operationCallback", operationCallback, callbackRoles...)).
 		Methods("POST")
 
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/keys", paramContractVersion),
+		route("createKey", createKey, operatorRoles...)).
+		Methods("PUT", "POST")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/keys", paramContractVersion),
+		route("getKeys", getKeys, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/keys/{%s}", paramContractVersion, paramKey),
+		route("getKey", getKey, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets", paramContractVersion),
+		route("getBuckets", getBuckets, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets/{%s}", paramContractVersion, paramBucket),
+		route("deleteBucket", deleteBucket, operatorRoles...)).
+		Methods("DELETE")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets/{%s}/values", paramContractVersion, paramBucket),
+		route("getValues", getValues, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets/{%s}/values/{%s}", paramContractVersion, paramBucket, paramKey),
+		route("createValue", createValue, operatorRoles...)).
+		Methods("PUT", "POST")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets/{%s}/values/{%s}", paramContractVersion, paramBucket, paramKey),
+		route("getValue", getValue, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/config/buckets/{%s}/values/{%s}/history", paramContractVersion, paramBucket, paramKey),
+		route("getValueHistory", getValueHistory, readRoles...)).
+		Methods("GET")
+
+	router.HandleFunc(
+		fmt.Sprintf("/v{%s}/audit", paramContractVersion),
+		route("getAuditLog", getAuditLog, auditRoles...)).
+		Methods("GET")
+
+	//API specification
+	router.HandleFunc("/openapi.json", getOpenAPISpec).Methods("GET")
+
 	//metrics endpoint
 	metrics.RegisterAll(o.Registry.Inventory(), o.Logger())
 	router.Handle("/metrics", promhttp.Handler())
@@ -64,15 +153,39 @@ func startWebserver(ctx context.Context, o *Options) error {
 		SSLKeyFile: o.SSLKey,
 		Router:     router,
 	}
+	if authCfg.Enabled {
+		srv.ClientCAFile = authCfg.ClientCertificates.CAFile
+	}
 	return srv.Start(ctx) //blocking call
 }
 
+//parseAuthConfig reads the authentication settings of the mothership API (authentication is disabled by default)
+func parseAuthConfig() (*auth.Config, error) {
+	authCfg := &auth.Config{}
+	if err := viper.UnmarshalKey("mothership.auth", authCfg); err != nil {
+		return nil, fmt.Errorf("error while parsing authentication configuration: %s", err)
+	}
+	return authCfg, nil
+}
+
 func callHandler(o *Options, handler func(o *Options, w http.ResponseWriter, r *http.Request)) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		handler(o, w, r)
 	}
 }
 
+func getOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := openapi.JSON()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to render OpenAPI specification"))
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(spec); err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to send OpenAPI specification"))
+	}
+}
+
 func createOrUpdateCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 	params := server.NewParams(r)
 	contractV, err := params.Int64(paramContractVersion)
@@ -87,10 +200,10 @@ func createOrUpdateCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 	}
 	clusterModel, err := keb.NewModelFactory(contractV).Cluster(reqBody)
 	if err != nil {
-		sendError(w, http.StatusBadRequest, errors.Wrap(err, "Failed to unmarshal JSON payload"))
+		sendError(w, http.StatusBadRequest, errors.Wrap(err, "Invalid cluster model"))
 		return
 	}
-	clusterState, err := o.Registry.Inventory().CreateOrUpdate(contractV, clusterModel)
+	clusterState, err := o.Registry.Inventory().WithActor(requestActor(r)).CreateOrUpdate(contractV, clusterModel)
 	if err != nil {
 		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to create or update cluster entity"))
 		return
@@ -136,6 +249,119 @@ func getLatestCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 	sendResponse(w, responsePayload(clusterState))
 }
 
+func configDiff(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	clusterName, err := params.String(paramCluster)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	fromVersion, err := params.Int64(paramFromVersion)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	toVersion, err := params.Int64(paramToVersion)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	diff, err := o.Registry.Inventory().Diff(clusterName, fromVersion, toVersion)
+	if err != nil {
+		httpCode := http.StatusInternalServerError
+		if repository.IsNotFoundError(err) {
+			httpCode = http.StatusNotFound
+		}
+		sendError(w, httpCode, errors.Wrap(err, "Could not calculate configuration diff"))
+		return
+	}
+	//respond
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to encode configuration diff response"))
+		return
+	}
+}
+
+type rollbackRequest struct {
+	User string `json:"user"` //ignored if authentication is enabled
+}
+
+func rollbackConfig(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	contractV, err := params.Int64(paramContractVersion)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, errors.Wrap(err, "Contract version undefined"))
+		return
+	}
+	clusterName, err := params.String(paramCluster)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	configVersion, err := params.Int64(paramConfigVersion)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to read received JSON payload"))
+		return
+	}
+	var body rollbackRequest
+	if err := json.Unmarshal(reqBody, &body); err != nil {
+		sendError(w, http.StatusBadRequest, errors.Wrap(err, "Failed to unmarshal JSON payload"))
+		return
+	}
+	user := requestUser(r, body.User)
+	if user == "" {
+		sendError(w, http.StatusBadRequest, fmt.Errorf("User who triggers the rollback is undefined"))
+		return
+	}
+	clusterState, err := o.Registry.Inventory().WithActor(requestActor(r)).Rollback(clusterName, configVersion, user)
+	if err != nil {
+		httpCode := http.StatusInternalServerError
+		if repository.IsNotFoundError(err) {
+			httpCode = http.StatusNotFound
+		}
+		sendError(w, httpCode, errors.Wrap(err, fmt.Sprintf("Failed to rollback configuration of cluster '%s'", clusterName)))
+		return
+	}
+	//respond status URL
+	payload := responsePayload(clusterState)
+	payload["statusUrl"] = fmt.Sprintf("%s/v%d/clusters/%s/configs/%d/status",
+		r.Host, contractV, clusterState.Cluster.Cluster, clusterState.Configuration.Version)
+	sendResponse(w, payload)
+}
+
+func getRollbacks(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	clusterName, err := params.String(paramCluster)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	rollbacks, err := o.Registry.Inventory().Rollbacks(clusterName)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Could not retrieve configuration rollbacks"))
+		return
+	}
+	result := make([]map[string]interface{}, 0, len(rollbacks))
+	for _, rollback := range rollbacks {
+		result = append(result, map[string]interface{}{
+			"configurationVersion":       rollback.ConfigVersion,
+			"sourceConfigurationVersion": rollback.SourceConfigVersion,
+			"user":                       rollback.Username,
+			"created":                    rollback.Created,
+		})
+	}
+	sendResponse(w, map[string]interface{}{
+		"cluster":   clusterName,
+		"rollbacks": result,
+	})
+}
+
 func statusChanges(o *Options, w http.ResponseWriter, r *http.Request) {
 	params := server.NewParams(r)
 	clusterName, err := params.String(paramCluster)
@@ -177,7 +403,7 @@ func deleteCluster(o *Options, w http.ResponseWriter, r *http.Request) {
 		sendError(w, http.StatusNotFound, errors.Wrap(err, fmt.Sprintf("Deletion impossible: Cluster '%s' not found", clusterName)))
 		return
 	}
-	if err := o.Registry.Inventory().Delete(clusterName); err != nil {
+	if err := o.Registry.Inventory().WithActor(requestActor(r)).Delete(clusterName); err != nil {
 		sendError(w, http.StatusInternalServerError, errors.Wrap(err, fmt.Sprintf("Failed to delete cluster '%s'", clusterName)))
 		return
 	}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/auth"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+//requestActor returns the identity of the client which sent the request (recorded in the audit log)
+func requestActor(r *http.Request) string {
+	if principal := auth.PrincipalFromRequest(r); principal != nil {
+		return principal.Name
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return fmt.Sprintf("client@%s", host)
+}
+
+//requestUser returns the user who is recorded as author of a change: the authenticated principal or, if
+//authentication is disabled, the user provided by the client
+func requestUser(r *http.Request, user string) string {
+	if principal := auth.PrincipalFromRequest(r); principal != nil {
+		return principal.Name
+	}
+	return user
+}
+
+func getAuditLog(o *Options, w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilter(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	entries, err := o.Registry.AuditRepository().Entries(filter)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Could not retrieve audit log"))
+		return
+	}
+	result := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, map[string]interface{}{
+			"id":       entry.ID,
+			"actor":    entry.Actor,
+			"action":   entry.Action,
+			"entity":   entry.Entity,
+			"entityId": entry.EntityID,
+			"before":   entry.BeforeSummary,
+			"after":    entry.AfterSummary,
+			"created":  entry.Created,
+		})
+	}
+	payload := map[string]interface{}{
+		"entries": result,
+	}
+	if len(entries) == filter.Limit { //further entries can exist: return cursor of next page
+		payload["next"] = entries[len(entries)-1].ID
+	}
+	sendResponse(w, payload)
+}
+
+func auditFilter(r *http.Request) (*audit.Filter, error) {
+	query := r.URL.Query()
+	filter := &audit.Filter{
+		Actor:    query.Get("actor"),
+		Action:   audit.Action(query.Get("action")),
+		Entity:   query.Get("entity"),
+		EntityID: query.Get("entityId"),
+		Limit:    defaultAuditLimit,
+	}
+	var err error
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return nil, errors.Wrap(err, "Parameter 'since' is not a RFC3339 timestamp")
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return nil, errors.Wrap(err, "Parameter 'until' is not a RFC3339 timestamp")
+		}
+	}
+	if before := query.Get("before"); before != "" {
+		if filter.BeforeID, err = strconv.ParseInt(before, 10, 64); err != nil {
+			return nil, errors.Wrap(err, "Parameter 'before' is not an audit entry ID")
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, errors.Wrap(err, "Parameter 'limit' is not a number")
+		}
+		if filter.Limit <= 0 || filter.Limit > maxAuditLimit {
+			return nil, fmt.Errorf("Parameter 'limit' has to be between 1 and %d", maxAuditLimit)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kyma-incubator/reconciler/pkg/auth"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/kv"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/repository"
+	"github.com/kyma-incubator/reconciler/pkg/server"
+	"github.com/pkg/errors"
+)
+
+//maskedValue replaces values of encrypted keys in responses to clients which are not allowed to see them
+const maskedValue = "***"
+
+type createKeyRequest struct {
+	Key          string `json:"key"`
+	DataType     string `json:"dataType"`
+	Encrypted    bool   `json:"encrypted"`
+	Validator    string `json:"validator"`
+	Trigger      string `json:"trigger"`
+	TriggerPhase string `json:"triggerPhase"`
+	User         string `json:"user"` //ignored if authentication is enabled
+}
+
+type createValueRequest struct {
+	Value      string `json:"value"`
+	KeyVersion int64  `json:"keyVersion"` //latest key version is used if undefined
+	User       string `json:"user"`       //ignored if authentication is enabled
+}
+
+func createKey(o *Options, w http.ResponseWriter, r *http.Request) {
+	var body createKeyRequest
+	if err := readJSONPayload(r, &body); err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Key == "" {
+		sendError(w, http.StatusBadRequest, fmt.Errorf("Key is undefined"))
+		return
+	}
+	user := requestUser(r, body.User)
+	if user == "" {
+		sendError(w, http.StatusBadRequest, fmt.Errorf("User who creates the key is undefined"))
+		return
+	}
+	dataType, err := model.NewDataType(body.DataType)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	triggerPhase, err := model.NewTriggerPhase(body.TriggerPhase)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	key, err := o.Registry.KVRepository().WithActor(requestActor(r)).CreateKey(&model.KeyEntity{
+		Key:          body.Key,
+		DataType:     dataType,
+		Encrypted:    body.Encrypted,
+		Validator:    body.Validator,
+		Trigger:      body.Trigger,
+		TriggerPhase: triggerPhase,
+		Username:     user,
+	})
+	if err != nil {
+		if invalidKeyErr, ok := err.(*kv.InvalidKeyError); ok {
+			sendValidationError(w, map[string]interface{}{
+				"error":     invalidKeyErr.Error(),
+				"key":       invalidKeyErr.Key.Key,
+				"validator": invalidKeyErr.Key.Validator,
+				"trigger":   invalidKeyErr.Key.Trigger,
+			})
+			return
+		}
+		httpCode := http.StatusInternalServerError
+		if db.IsInvalidEntityError(err) {
+			httpCode = http.StatusBadRequest
+		}
+		sendError(w, httpCode, errors.Wrap(err, fmt.Sprintf("Failed to create key '%s'", body.Key)))
+		return
+	}
+	sendResponse(w, keyPayload(key))
+}
+
+func getKeys(o *Options, w http.ResponseWriter, r *http.Request) {
+	keys, err := o.Registry.KVRepository().Keys()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Could not retrieve keys"))
+		return
+	}
+	result := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, keyPayload(key))
+	}
+	sendResponse(w, map[string]interface{}{
+		"keys": result,
+	})
+}
+
+func getKey(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	keyName, err := params.String(paramKey)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	key, err := o.Registry.KVRepository().LatestKey(keyName)
+	if err != nil {
+		sendError(w, notFoundOrInternalError(err), errors.Wrap(err, fmt.Sprintf("Could not retrieve key '%s'", keyName)))
+		return
+	}
+	sendResponse(w, keyPayload(key))
+}
+
+func getBuckets(o *Options, w http.ResponseWriter, r *http.Request) {
+	buckets, err := o.Registry.KVRepository().Buckets()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Could not retrieve buckets"))
+		return
+	}
+	result := make([]map[string]interface{}, 0, len(buckets))
+	for _, bucket := range buckets {
+		result = append(result, map[string]interface{}{
+			"bucket":  bucket.Bucket,
+			"user":    bucket.Username,
+			"created": bucket.Created,
+		})
+	}
+	sendResponse(w, map[string]interface{}{
+		"buckets": result,
+	})
+}
+
+func deleteBucket(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	bucket, err := params.String(paramBucket)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := o.Registry.KVRepository().WithActor(requestActor(r)).DeleteBucket(bucket); err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, fmt.Sprintf("Failed to delete bucket '%s'", bucket)))
+		return
+	}
+}
+
+func getValues(o *Options, w http.ResponseWriter, r *http.Request) {
+	params := server.NewParams(r)
+	bucket, err := params.String(paramBucket)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	values, err := o.Registry.KVRepository().ValuesByBucket(bucket)
+	if err == nil {
+		values, err = maskEncryptedValues(o, r, values)
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, fmt.Sprintf("Could not retrieve values of bucket '%s'", bucket)))
+		return
+	}
+	sendResponse(w, map[string]interface{}{
+		"bucket": bucket,
+		"values": valuesPayload(values),
+	})
+}
+
+func getValue(o *Options, w http.ResponseWriter, r *http.Request) {
+	bucket, keyName, err := bucketAndKeyParams(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	value, err := o.Registry.KVRepository().LatestValue(bucket, keyName)
+	if err != nil {
+		sendError(w, notFoundOrInternalError(err),
+			errors.Wrap(err, fmt.Sprintf("Could not retrieve value of key '%s' in bucket '%s'", keyName, bucket)))
+		return
+	}
+	values, err := maskEncryptedValues(o, r, []*model.ValueEntity{value})
+	if err != nil {
+		sendError(w, http.StatusInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Could not retrieve value of key '%s' in bucket '%s'", keyName, bucket)))
+		return
+	}
+	sendResponse(w, valuePayload(values[0]))
+}
+
+func getValueHistory(o *Options, w http.ResponseWriter, r *http.Request) {
+	bucket, keyName, err := bucketAndKeyParams(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	values, err := o.Registry.KVRepository().ValueHistory(bucket, keyName)
+	if err == nil {
+		values, err = maskEncryptedValues(o, r, values)
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Could not retrieve value history of key '%s' in bucket '%s'", keyName, bucket)))
+		return
+	}
+	sendResponse(w, map[string]interface{}{
+		"bucket": bucket,
+		"key":    keyName,
+		"values": valuesPayload(values),
+	})
+}
+
+func createValue(o *Options, w http.ResponseWriter, r *http.Request) {
+	bucket, keyName, err := bucketAndKeyParams(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := model.ValidateBucketName(bucket); err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	var body createValueRequest
+	if err := readJSONPayload(r, &body); err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	user := requestUser(r, body.User)
+	if user == "" {
+		sendError(w, http.StatusBadRequest, fmt.Errorf("User who creates the value is undefined"))
+		return
+	}
+
+	//resolve the key the value belongs to
+	var key *model.KeyEntity
+	if body.KeyVersion > 0 {
+		key, err = o.Registry.KVRepository().Key(keyName, body.KeyVersion)
+	} else {
+		key, err = o.Registry.KVRepository().LatestKey(keyName)
+	}
+	if err != nil {
+		sendError(w, notFoundOrInternalError(err), errors.Wrap(err, fmt.Sprintf("Could not retrieve key '%s'", keyName)))
+		return
+	}
+	if _, err := key.DataType.Get(body.Value); err != nil {
+		sendValidationError(w, map[string]interface{}{
+			"error":    err.Error(),
+			"key":      key.Key,
+			"value":    body.Value,
+			"dataType": key.DataType,
+		})
+		return
+	}
+
+	value, err := o.Registry.KVRepository().WithActor(requestActor(r)).CreateValue(&model.ValueEntity{
+		Bucket:     bucket,
+		Key:        key.Key,
+		KeyVersion: key.Version,
+		DataType:   key.DataType,
+		Value:      body.Value,
+		Username:   user,
+	})
+	if err != nil {
+		if invalidValueErr, ok := err.(*model.InvalidValueError); ok {
+			sendValidationError(w, map[string]interface{}{
+				"error":     invalidValueErr.Error(),
+				"key":       invalidValueErr.Key,
+				"value":     invalidValueErr.Value,
+				"validator": invalidValueErr.Validator,
+				"result":    invalidValueErr.Result,
+			})
+			return
+		}
+		if invalidDataTypeErr, ok := err.(*kv.InvalidDataTypeError); ok {
+			sendValidationError(w, map[string]interface{}{
+				"error":    invalidDataTypeErr.Error(),
+				"key":      invalidDataTypeErr.Key.Key,
+				"value":    body.Value,
+				"dataType": invalidDataTypeErr.InvalidDataType,
+			})
+			return
+		}
+		sendError(w, http.StatusInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Failed to create value of key '%s' in bucket '%s'", keyName, bucket)))
+		return
+	}
+	sendResponse(w, valuePayload(value))
+}
+
+func bucketAndKeyParams(r *http.Request) (string, string, error) {
+	params := server.NewParams(r)
+	bucket, err := params.String(paramBucket)
+	if err != nil {
+		return "", "", err
+	}
+	key, err := params.String(paramKey)
+	if err != nil {
+		return "", "", err
+	}
+	return bucket, key, nil
+}
+
+func readJSONPayload(r *http.Request, body interface{}) error {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read received JSON payload")
+	}
+	if err := json.Unmarshal(reqBody, body); err != nil {
+		return errors.Wrap(err, "Failed to unmarshal JSON payload")
+	}
+	return nil
+}
+
+func notFoundOrInternalError(err error) int {
+	if repository.IsNotFoundError(err) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func keyPayload(key *model.KeyEntity) map[string]interface{} {
+	return map[string]interface{}{
+		"key":          key.Key,
+		"version":      key.Version,
+		"dataType":     key.DataType,
+		"encrypted":    key.Encrypted,
+		"validator":    key.Validator,
+		"trigger":      key.Trigger,
+		"triggerPhase": key.TriggerPhase,
+		"user":         key.Username,
+		"created":      key.Created,
+	}
+}
+
+func valuePayload(value *model.ValueEntity) map[string]interface{} {
+	return map[string]interface{}{
+		"bucket":     value.Bucket,
+		"key":        value.Key,
+		"keyVersion": value.KeyVersion,
+		"value":      value.Value,
+		"dataType":   value.DataType,
+		"version":    value.Version,
+		"user":       value.Username,
+		"created":    value.Created,
+	}
+}
+
+//maskEncryptedValues replaces the values of encrypted keys by a placeholder unless the client is allowed
+//to change configuration entries
+func maskEncryptedValues(o *Options, r *http.Request, values []*model.ValueEntity) ([]*model.ValueEntity, error) {
+	if principal := auth.PrincipalFromRequest(r); principal == nil || principal.HasRole(operatorRoles...) {
+		return values, nil
+	}
+	encrypted := make(map[string]bool) //cache the encryption flag per key version
+	result := make([]*model.ValueEntity, 0, len(values))
+	for _, value := range values {
+		keyID := fmt.Sprintf("%s@%d", value.Key, value.KeyVersion)
+		isEncrypted, ok := encrypted[keyID]
+		if !ok {
+			key, err := o.Registry.KVRepository().Key(value.Key, value.KeyVersion)
+			if err != nil {
+				return nil, err
+			}
+			isEncrypted = key.Encrypted
+			encrypted[keyID] = isEncrypted
+		}
+		if isEncrypted {
+			masked := *value
+			masked.Value = maskedValue
+			value = &masked
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func valuesPayload(values []*model.ValueEntity) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, valuePayload(value))
+	}
+	return result
+}
+
+//sendValidationError responds a rejected configuration value as structured JSON payload
+func sendValidationError(w http.ResponseWriter, payload map[string]interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		sendError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to encode validation error to JSON"))
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-incubator/reconciler/internal/cli"
+	"github.com/kyma-incubator/reconciler/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerFunc func(o *Options, w http.ResponseWriter, r *http.Request)
+
+var (
+	testOptions     *Options
+	testOptionsErr  error
+	testOptionsInit sync.Once
+)
+
+//newTestOptions returns options with an application registry which is shared by all tests
+//(the registry can be created only once because it registers metrics collectors)
+func newTestOptions(t *testing.T) *Options {
+	testOptionsInit.Do(func() {
+		var cliOptions *cli.Options
+		cliOptions, testOptionsErr = cli.NewTestOptions()
+		if testOptionsErr == nil {
+			testOptions = NewOptions(cliOptions)
+		}
+	})
+	require.NoError(t, testOptionsErr)
+	return testOptions
+}
+
+//serveTestRequest calls the handler with the given path parameters and JSON body on behalf of the principal
+//(authentication is disabled if the principal is nil)
+func serveTestRequest(o *Options, handler handlerFunc, principal *auth.Principal, vars map[string]string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if principal != nil {
+		r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+	}
+	r = mux.SetURLVars(r, vars)
+	w := httptest.NewRecorder()
+	handler(o, w, r)
+	return w
+}
+
+func responseBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	result := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func TestConfigHandlers(t *testing.T) {
+	o := newTestOptions(t)
+	operator := &auth.Principal{Name: "operator", Roles: []auth.Role{auth.RoleOperator}}
+	reader := &auth.Principal{Name: "reader", Roles: []auth.Role{auth.RoleReadOnly}}
+	valueVars := map[string]string{paramBucket: "default", paramKey: "handlertest.password"}
+
+	w := serveTestRequest(o, createKey, operator, nil, `{"key":"handlertest.password","dataType":"string","encrypted":true,"user":"someone"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "operator", responseBody(t, w)["user"]) //authenticated principal is the author
+
+	w = serveTestRequest(o, createValue, operator, valueVars, `{"value":"secret"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "operator", responseBody(t, w)["user"])
+
+	t.Run("Require user if authentication is disabled", func(t *testing.T) {
+		w := serveTestRequest(o, createValue, nil, valueVars, `{"value":"secret"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serveTestRequest(o, createValue, nil, valueVars, `{"value":"secret2","user":"someone"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, "someone", responseBody(t, w)["user"])
+	})
+
+	t.Run("Mask values of encrypted keys", func(t *testing.T) {
+		w := serveTestRequest(o, getValue, reader, valueVars, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, maskedValue, responseBody(t, w)["value"])
+
+		w = serveTestRequest(o, getValueHistory, reader, valueVars, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NotContains(t, w.Body.String(), "secret")
+
+		w = serveTestRequest(o, getValues, reader, valueVars, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NotContains(t, w.Body.String(), "secret")
+
+		//operators are allowed to see the value
+		w = serveTestRequest(o, getValue, operator, valueVars, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, "secret2", responseBody(t, w)["value"])
+	})
+}
+
+func TestConfigHandlerErrors(t *testing.T) {
+	o := newTestOptions(t)
+	operator := &auth.Principal{Name: "operator", Roles: []auth.Role{auth.RoleOperator}}
+
+	w := serveTestRequest(o, createKey, operator, nil,
+		`{"key":"handlertest.replicas","dataType":"integer","validator":"it > 0 && it < 10"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("Reject key with blocked import", func(t *testing.T) {
+		w := serveTestRequest(o, createKey, operator, nil,
+			`{"key":"handlertest.invalid","dataType":"string","validator":"import \"os\"; os.Exit(1)"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		require.Equal(t, "handlertest.invalid", responseBody(t, w)["key"])
+
+		w = serveTestRequest(o, getKey, operator, map[string]string{paramKey: "handlertest.invalid"}, "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Reject key with unsupported data type", func(t *testing.T) {
+		w := serveTestRequest(o, createKey, operator, nil, `{"key":"handlertest.invalid","dataType":"float"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Reject value of wrong data type", func(t *testing.T) {
+		w := serveTestRequest(o, createValue, operator,
+			map[string]string{paramBucket: "default", paramKey: "handlertest.replicas"}, `{"value":"many"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		require.Equal(t, "integer", responseBody(t, w)["dataType"])
+	})
+
+	t.Run("Reject value which fails the validator", func(t *testing.T) {
+		w := serveTestRequest(o, createValue, operator,
+			map[string]string{paramBucket: "default", paramKey: "handlertest.replicas"}, `{"value":"20"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		require.Equal(t, "it > 0 && it < 10", responseBody(t, w)["validator"])
+	})
+
+	t.Run("Reject value in invalid bucket", func(t *testing.T) {
+		w := serveTestRequest(o, createValue, operator,
+			map[string]string{paramBucket: "not a bucket", paramKey: "handlertest.replicas"}, `{"value":"2"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown key or value", func(t *testing.T) {
+		unknownVars := map[string]string{paramBucket: "default", paramKey: "handlertest.unknown"}
+
+		w := serveTestRequest(o, getKey, operator, unknownVars, "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serveTestRequest(o, createValue, operator, unknownVars, `{"value":"1"}`)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serveTestRequest(o, getValue, operator, unknownVars, "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serveTestRequest(o, getValue, operator,
+			map[string]string{paramBucket: "default", paramKey: "handlertest.replicas"}, "")
+		require.Equal(t, http.StatusNotFound, w.Code) //key exists but has no value
+	})
+
+	t.Run("Create and retrieve value", func(t *testing.T) {
+		vars := map[string]string{paramBucket: "default", paramKey: "handlertest.replicas"}
+		w := serveTestRequest(o, createValue, operator, vars, `{"value":"3"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = serveTestRequest(o, getValue, operator, vars, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, "3", responseBody(t, w)["value"])
+	})
+}
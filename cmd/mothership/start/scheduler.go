@@ -6,17 +6,15 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/metrics"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/scheduler"
 	"github.com/spf13/viper"
 )
 
-func startScheduler(ctx context.Context, o *Options, configFile string) error {
-	mothershipCfg, err := parseMothershipReconcilerConfig(configFile)
-	if err != nil {
-		return err
-	}
-
+func startScheduler(ctx context.Context, o *Options, cfg *mothershipConfig) error {
 	reconcilersCfg, err := parseComponentReconcilersConfig(o.ReconcilersCfgPath)
 	if err != nil {
 		return err
@@ -28,6 +26,7 @@ func startScheduler(ctx context.Context, o *Options, configFile string) error {
 		&scheduler.InventoryWatchConfig{
 			WatchInterval:            o.WatchInterval,
 			ClusterReconcileInterval: o.ClusterReconcileInterval,
+			MaintenanceWindows:       cfg.maintenanceWindows,
 		},
 	)
 	if err != nil {
@@ -35,9 +34,8 @@ func startScheduler(ctx context.Context, o *Options, configFile string) error {
 	}
 
 	workerFactory, err := scheduler.NewRemoteWorkerFactory(
-		o.Registry.Inventory(),
 		reconcilersCfg,
-		mothershipCfg,
+		cfg.reconciler,
 		o.Registry.OperationsRegistry(),
 		o.Verbose,
 	)
@@ -45,10 +43,23 @@ func startScheduler(ctx context.Context, o *Options, configFile string) error {
 		return err
 	}
 
+	bucketConfig, err := cluster.NewBucketConfiguration(
+		o.Registry.KVRepository(),
+		o.Registry.CacheRepository(),
+		cfg.landscape,
+		o.Verbose,
+	)
+	if err != nil {
+		return err
+	}
+
 	remoteScheduler, err := scheduler.NewRemoteScheduler(
 		inventoryWatch,
 		workerFactory,
-		mothershipCfg,
+		o.Registry.Inventory(),
+		o.Registry.KVRepository(),
+		bucketConfig,
+		cfg.reconciler,
 		o.Workers,
 		o.Verbose,
 	)
@@ -59,20 +70,55 @@ func startScheduler(ctx context.Context, o *Options, configFile string) error {
 	return remoteScheduler.Run(ctx)
 }
 
-func parseMothershipReconcilerConfig(configFile string) (reconciler.MothershipReconcilerConfig, error) {
-	viper.SetConfigFile(configFile)
-	if err := viper.ReadInConfig(); err != nil {
-		return reconciler.MothershipReconcilerConfig{}, err
-	}
+func parseMothershipReconcilerConfig() reconciler.MothershipReconcilerConfig {
+	mothershipScheme := viper.GetString("mothership.scheme")
 	mothershipHost := viper.GetString("mothership.host")
 	mothershipPort := viper.GetInt("mothership.port")
 	crdComponents := viper.GetStringSlice("crdComponents")
 	preComponents := viper.GetStringSlice("preComponents")
+	callbackToken := viper.GetString("mothership.auth.callbackToken")
 	return reconciler.MothershipReconcilerConfig{
+		Scheme:        mothershipScheme,
 		Host:          mothershipHost,
 		Port:          mothershipPort,
 		CrdComponents: crdComponents,
-		PreComponents: preComponents}, nil
+		PreComponents: preComponents,
+		CallbackToken: callbackToken}
+}
+
+func parseMaintenanceWindowsConfig() (*scheduler.MaintenanceWindows, error) {
+	var globalAccounts map[string][]keb.MaintenanceWindow
+	if err := viper.UnmarshalKey("mothership.maintenanceWindows.globalAccounts", &globalAccounts); err != nil {
+		return nil, fmt.Errorf("error while parsing maintenance windows configuration: %s", err)
+	}
+	return &scheduler.MaintenanceWindows{
+		GlobalAccounts: globalAccounts,
+	}, nil
+}
+
+func startRetentionJob(ctx context.Context, o *Options, retentionCfg *scheduler.RetentionConfig) error {
+	retentionJob, err := scheduler.NewRetentionJob(
+		o.Registry.Inventory(),
+		metrics.NewInventoryPurgeCollector(),
+		o.Verbose,
+		retentionCfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	return retentionJob.Run(ctx)
+}
+
+func parseRetentionConfig() *scheduler.RetentionConfig {
+	return &scheduler.RetentionConfig{
+		Interval: viper.GetDuration("mothership.retention.interval"),
+		Policy: &cluster.RetentionPolicy{
+			ConfigVersions:          viper.GetInt("mothership.retention.configVersions"),
+			StatusRetention:         viper.GetDuration("mothership.retention.statusRetention"),
+			DeletedClusterRetention: viper.GetDuration("mothership.retention.deletedClusterRetention"),
+		},
+	}
 }
 
 func parseComponentReconcilersConfig(path string) (reconciler.ComponentReconcilersConfig, error) {
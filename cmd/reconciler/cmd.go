@@ -56,6 +56,10 @@ func NewCmd(o *cli.Options) *cobra.Command {
 		"Interval to verify the installation progress of a deployed Kubernetes resource")
 	reconcilerOpts.ProgressTrackerConfig.Timeout = reconcilerOpts.WorkerConfig.Timeout //coupled to reconcile-timeout
 
+	//secret configuration entries
+	cmd.PersistentFlags().BoolVar(&reconcilerOpts.KubernetesSecrets, "kubernetes-secrets", false,
+		"Deploy secret configuration entries as Kubernetes Secret '<component>-secrets' instead of passing them as Helm values")
+
 	//file cache for Kyma sources
 	cmd.PersistentFlags().StringVar(&reconcilerOpts.Workspace, "workspace", ".",
 		"Workspace directory used to cache Kyma sources")
@@ -26,6 +26,7 @@ require (
 	github.com/spf13/viper v1.8.1
 	github.com/stretchr/testify v1.7.0
 	github.com/traefik/yaegi v0.9.17
+	github.com/xeipuuv/gojsonschema v1.2.0
 	go.uber.org/zap v1.17.0
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 	helm.sh/helm/v3 v3.6.3
@@ -12,25 +12,61 @@ import (
 )
 
 func NewEncryptionKey(backup bool) error {
-	keyFile := viper.GetString("db.encryption.keyFile")
-	if keyFile == "" {
-		return fmt.Errorf("encryption key file not configured")
+	_, err := newEncryptionKey(backup)
+	return err
+}
+
+//RotateEncryptionKey creates a new encryption key file and returns the path of the backup file
+//which contains the previous encryption key
+func RotateEncryptionKey() (string, error) {
+	keyFileBackup, err := newEncryptionKey(true)
+	if err != nil {
+		return "", err
 	}
-	if !filepath.IsAbs(keyFile) { //ensure key file path is absolute (if not, use config-file location as parent-dir)
-		keyFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), keyFile)
+	if keyFileBackup == "" {
+		return "", fmt.Errorf("encryption key file to rotate does not exist")
 	}
+	return keyFileBackup, nil
+}
 
-	encKey, err := db.NewEncryptionKey()
+//RestoreEncryptionKey replaces the current encryption key file with the given backup file
+func RestoreEncryptionKey(keyFileBackup string) error {
+	keyFile, err := encryptionKeyFile()
 	if err != nil {
 		return err
 	}
+	return os.Rename(keyFileBackup, keyFile)
+}
+
+func newEncryptionKey(backup bool) (string, error) {
+	keyFile, err := encryptionKeyFile()
+	if err != nil {
+		return "", err
+	}
 
+	encKey, err := db.NewEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	var keyFileBackup string
 	if file.Exists(keyFile) && backup {
-		keyFileBackup := fmt.Sprintf("%s.%d.bak", keyFile, time.Now().Unix())
+		keyFileBackup = fmt.Sprintf("%s.%d.bak", keyFile, time.Now().Unix())
 		if err := os.Rename(keyFile, keyFileBackup); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return ioutil.WriteFile(keyFile, []byte(encKey), 0600)
+	return keyFileBackup, ioutil.WriteFile(keyFile, []byte(encKey), 0600)
+}
+
+func encryptionKeyFile() (string, error) {
+	keyFile := viper.GetString("db.encryption.keyFile")
+	if keyFile == "" {
+		return "", fmt.Errorf("encryption key file not configured")
+	}
+	if !filepath.IsAbs(keyFile) { //ensure key file path is absolute (if not, use config-file location as parent-dir)
+		keyFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), keyFile)
+	}
+	return keyFile, nil
 }
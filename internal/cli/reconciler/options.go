@@ -12,6 +12,7 @@ type Options struct {
 	RetryConfig           *RetryConfig
 	StatusUpdaterConfig   *RecurringTaskConfig
 	ProgressTrackerConfig *RecurringTaskConfig
+	KubernetesSecrets     bool //deploy secret configuration entries as Kubernetes Secret instead of Helm values
 }
 
 func NewOptions(o *cli.Options) *Options {
@@ -23,6 +24,7 @@ func NewOptions(o *cli.Options) *Options {
 		&RetryConfig{},
 		&RecurringTaskConfig{},
 		&RecurringTaskConfig{},
+		false,
 	}
 }
 
@@ -26,7 +26,9 @@ func NewComponentReconciler(o *Options, reconcilerName string) (*service.Compone
 		//configure status updates send to mothership reconciler
 		WithStatusUpdaterConfig(o.StatusUpdaterConfig.Interval, o.StatusUpdaterConfig.Timeout).
 		//configure reconciliation progress-checks applied on target K8s cluster
-		WithProgressTrackerConfig(o.ProgressTrackerConfig.Interval, o.ProgressTrackerConfig.Timeout)
+		WithProgressTrackerConfig(o.ProgressTrackerConfig.Interval, o.ProgressTrackerConfig.Timeout).
+		//configure how secret configuration entries are passed to the component
+		WithKubernetesSecrets(o.KubernetesSecrets)
 
 	return recon, nil
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/cache"
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/kv"
@@ -16,6 +18,8 @@ type ApplicationRegistry struct {
 	connectionFactory db.ConnectionFactory
 	inventory         cluster.Inventory
 	kvRepository      *kv.Repository
+	cacheRepository   *cache.Repository
+	auditRepository   *audit.Repository
 	operations        scheduler.OperationsRegistry
 	initialized       bool
 }
@@ -45,6 +49,12 @@ func (or *ApplicationRegistry) init() error {
 	if or.kvRepository, err = or.initRepository(); err != nil {
 		return err
 	}
+	if or.cacheRepository, err = or.initCacheRepository(); err != nil {
+		return err
+	}
+	if or.auditRepository, err = or.initAuditRepository(); err != nil {
+		return err
+	}
 	or.initOperationsRegistry()
 	or.initialized = true
 	return nil
@@ -57,6 +67,12 @@ func (or *ApplicationRegistry) Close() error {
 	if err := or.kvRepository.Close(); err != nil {
 		return err
 	}
+	if err := or.cacheRepository.Close(); err != nil {
+		return err
+	}
+	if err := or.auditRepository.Close(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -72,6 +88,14 @@ func (or *ApplicationRegistry) KVRepository() *kv.Repository {
 	return or.kvRepository
 }
 
+func (or *ApplicationRegistry) CacheRepository() *cache.Repository {
+	return or.cacheRepository
+}
+
+func (or *ApplicationRegistry) AuditRepository() *audit.Repository {
+	return or.auditRepository
+}
+
 func (or *ApplicationRegistry) OperationsRegistry() scheduler.OperationsRegistry {
 	return or.operations
 }
@@ -89,6 +113,31 @@ func (or *ApplicationRegistry) initRepository() (*kv.Repository, error) {
 		return nil, err
 	}
 
+	//configuration changes lead to a reconciliation of the clusters which were using them
+	return repository.WithReconciliationScheduler(or.inventory), nil
+}
+
+func (or *ApplicationRegistry) initCacheRepository() (*cache.Repository, error) {
+	if or.connectionFactory == nil {
+		or.logger.Fatal("Failed to create cache repository because connection factory is undefined")
+	}
+	repository, err := cache.NewRepository(or.connectionFactory, or.debug)
+	if err != nil {
+		or.logger.Errorf("Failed to create cache repository: %s", err)
+		return nil, err
+	}
+	return repository, nil
+}
+
+func (or *ApplicationRegistry) initAuditRepository() (*audit.Repository, error) {
+	if or.connectionFactory == nil {
+		or.logger.Fatal("Failed to create audit repository because connection factory is undefined")
+	}
+	repository, err := audit.NewRepository(or.connectionFactory, or.debug)
+	if err != nil {
+		or.logger.Errorf("Failed to create audit repository: %s", err)
+		return nil, err
+	}
 	return repository, nil
 }
 
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+//SystemActor is recorded for changes which were not triggered by a known user or client
+const SystemActor = "system"
+
+type Action string
+
+const (
+	ClusterCreate    Action = "cluster.create"
+	ClusterUpdate    Action = "cluster.update"
+	ClusterDelete    Action = "cluster.delete"
+	ClusterRollback  Action = "cluster.rollback"
+	ClusterReconcile Action = "cluster.reconcile"
+	InventoryPurge   Action = "inventory.purge"
+	KeyCreate        Action = "config.key.create"
+	KeyDelete        Action = "config.key.delete"
+	ValueCreate      Action = "config.value.create"
+	ValueRestore     Action = "config.value.restore"
+	ValueDelete      Action = "config.value.delete"
+	BucketDelete     Action = "config.bucket.delete"
+)
+
+//Types of audited entities
+const (
+	EntityCluster   = "cluster"
+	EntityInventory = "inventory"
+	EntityKey       = "key"
+	EntityValue     = "value"
+	EntityBucket    = "bucket"
+)
+
+//Entry describes a change of an entity. Before and After are summaries of the entity state: strings are
+//stored as they are, other values are stored as JSON and nil is stored as empty summary.
+type Entry struct {
+	Actor    string
+	Action   Action
+	Entity   string
+	EntityID string
+	Before   interface{}
+	After    interface{}
+}
+
+func (e *Entry) String() string {
+	return fmt.Sprintf("AuditEntry [Actor=%s,Action=%s,Entity=%s,EntityID=%s]", e.Actor, e.Action, e.Entity, e.EntityID)
+}
+
+//Log stores the audit entry. Pass the transaction which applies the change to ensure the entry
+//is only persisted if the change gets committed.
+func Log(conn db.Connection, entry *Entry) error {
+	before, err := summary(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := summary(entry.After)
+	if err != nil {
+		return err
+	}
+	q, err := db.NewQuery(conn, &model.AuditEntity{
+		Actor:         Actor(entry.Actor),
+		Action:        string(entry.Action),
+		Entity:        entry.Entity,
+		EntityID:      entry.EntityID,
+		BeforeSummary: before,
+		AfterSummary:  after,
+	})
+	if err != nil {
+		return err
+	}
+	return q.Insert().Exec()
+}
+
+//Actor returns the first non-empty candidate or the SystemActor if no candidate is defined
+func Actor(candidates ...string) string {
+	for _, candidate := range candidates {
+		if candidate != "" {
+			return candidate
+		}
+	}
+	return SystemActor
+}
+
+func summary(state interface{}) (string, error) {
+	switch typedState := state.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typedState, nil
+	case fmt.Stringer:
+		return typedState.String(), nil
+	default:
+		result, err := json.Marshal(typedState)
+		if err != nil {
+			return "", err
+		}
+		return string(result), nil
+	}
+}
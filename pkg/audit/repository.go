@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/repository"
+)
+
+//timestampLayout is the format of the timestamps used by Postgres and SQLite (creation times are stored in UTC)
+const timestampLayout = "2006-01-02 15:04:05"
+
+//Filter restricts the returned audit entries. Empty fields are ignored.
+type Filter struct {
+	Actor    string
+	Action   Action
+	Entity   string
+	EntityID string
+	Since    time.Time //only entries created at or after this time
+	Until    time.Time //only entries created before this time
+	BeforeID int64     //cursor: only entries older than the entry with this ID
+	Limit    int
+}
+
+func (f *Filter) conditions() []db.Condition {
+	var conds []db.Condition
+	if f.Actor != "" {
+		conds = append(conds, db.Eq("Actor", f.Actor))
+	}
+	if f.Action != "" {
+		conds = append(conds, db.Eq("Action", string(f.Action)))
+	}
+	if f.Entity != "" {
+		conds = append(conds, db.Eq("Entity", f.Entity))
+	}
+	if f.EntityID != "" {
+		conds = append(conds, db.Eq("EntityID", f.EntityID))
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, db.Gte("Created", f.Since.UTC().Format(timestampLayout)))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, db.Lt("Created", f.Until.UTC().Format(timestampLayout)))
+	}
+	return conds
+}
+
+type Repository struct {
+	*repository.Repository
+}
+
+func NewRepository(dbFac db.ConnectionFactory, debug bool) (*Repository, error) {
+	repo, err := repository.NewRepository(dbFac, debug)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{repo}, nil
+}
+
+//Entries returns the audit entries matching the filter ordered by their creation (latest entry first)
+func (r *Repository) Entries(filter *Filter) ([]*model.AuditEntity, error) {
+	if filter == nil {
+		filter = &Filter{}
+	}
+	q, err := db.NewQuery(r.Conn, &model.AuditEntity{})
+	if err != nil {
+		return nil, err
+	}
+	selectQ := q.Select().WhereCondition(filter.conditions()...)
+	if filter.BeforeID > 0 {
+		selectQ.Before("ID", filter.BeforeID)
+	} else {
+		selectQ.OrderBy(map[string]string{"ID": "DESC"})
+	}
+	if filter.Limit > 0 {
+		selectQ.Limit(filter.Limit)
+	}
+	entities, err := selectQ.GetMany()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.AuditEntity, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, entity.(*model.AuditEntity))
+	}
+	return result, nil
+}
+
+func (r *Repository) Close() error {
+	return r.Conn.Close()
+}
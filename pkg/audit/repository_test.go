@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository(t *testing.T) {
+	connFact, err := db.NewTestConnectionFactory()
+	require.NoError(t, err)
+	repo, err := NewRepository(connFact, true)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	entries := []*Entry{
+		{Actor: "alice", Action: ClusterCreate, Entity: EntityCluster, EntityID: "cluster1", After: map[string]interface{}{"configVersion": 1}},
+		{Actor: "bob", Action: ClusterUpdate, Entity: EntityCluster, EntityID: "cluster1", Before: "version 1", After: "version 2"},
+		{Action: ClusterDelete, Entity: EntityCluster, EntityID: "cluster2"},
+		{Actor: "alice", Action: KeyCreate, Entity: EntityKey, EntityID: "key1"},
+	}
+	for _, entry := range entries {
+		require.NoError(t, Log(repo.Conn, entry))
+	}
+
+	t.Run("Get all entries", func(t *testing.T) {
+		result, err := repo.Entries(nil)
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+		//latest entry first
+		require.Equal(t, "key1", result[0].EntityID)
+		require.Equal(t, "cluster1", result[3].EntityID)
+		require.Equal(t, `{"configVersion":1}`, result[3].AfterSummary)
+		require.Empty(t, result[3].BeforeSummary)
+		//actor falls back to system actor
+		require.Equal(t, SystemActor, result[1].Actor)
+	})
+
+	t.Run("Filter entries", func(t *testing.T) {
+		result, err := repo.Entries(&Filter{Actor: "alice"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1", "cluster1"}, entityIDs(result))
+
+		result, err = repo.Entries(&Filter{Entity: EntityCluster, EntityID: "cluster1"})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, string(ClusterUpdate), result[0].Action)
+		require.Equal(t, "version 1", result[0].BeforeSummary)
+
+		result, err = repo.Entries(&Filter{Action: ClusterDelete})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cluster2"}, entityIDs(result))
+	})
+
+	t.Run("Filter entries by creation time", func(t *testing.T) {
+		result, err := repo.Entries(&Filter{Since: time.Now().Add(-1 * time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+
+		result, err = repo.Entries(&Filter{Until: time.Now().Add(-1 * time.Hour)})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("Paginate entries", func(t *testing.T) {
+		page1, err := repo.Entries(&Filter{Limit: 3})
+		require.NoError(t, err)
+		require.Len(t, page1, 3)
+
+		page2, err := repo.Entries(&Filter{Limit: 3, BeforeID: page1[2].ID})
+		require.NoError(t, err)
+		require.Len(t, page2, 1)
+		require.Equal(t, "cluster1", page2[0].EntityID)
+		require.Equal(t, string(ClusterCreate), page2[0].Action)
+	})
+}
+
+func entityIDs(entries []*model.AuditEntity) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.EntityID)
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Role string
+
+const (
+	//RoleReadOnly can retrieve clusters, configuration entries and the audit log
+	RoleReadOnly Role = "read-only"
+	//RoleOperator can additionally change configuration entries and rollback cluster configurations
+	RoleOperator Role = "operator"
+	//RoleKEB can create, update and delete clusters
+	RoleKEB Role = "keb"
+	//RoleReconciler can report the progress of operations (used by component reconcilers for callbacks)
+	RoleReconciler Role = "reconciler"
+)
+
+var roles = []Role{RoleReadOnly, RoleOperator, RoleKEB, RoleReconciler}
+
+func NewRole(role string) (Role, error) {
+	for _, knownRole := range roles {
+		if string(knownRole) == strings.ToLower(role) {
+			return knownRole, nil
+		}
+	}
+	return "", fmt.Errorf("role '%s' is not supported", role)
+}
+
+func newRoles(names []string) ([]Role, error) {
+	result := make([]Role, 0, len(names))
+	for _, name := range names {
+		role, err := NewRole(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+//Principal is an authenticated client
+type Principal struct {
+	Name   string
+	Roles  []Role
+	Method string //authentication method which identified the principal
+}
+
+func (p *Principal) String() string {
+	return fmt.Sprintf("Principal [Name=%s,Roles=%v,Method=%s]", p.Name, p.Roles, p.Method)
+}
+
+//HasRole returns true if the principal has at least one of the given roles
+func (p *Principal) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		for _, principalRole := range p.Roles {
+			if role == principalRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//Authenticator identifies the principal of a request. If the request contains no credentials which are
+//handled by the authenticator, nil is returned without error.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+//PrincipalFromRequest returns the authenticated principal of a request or nil if authentication is disabled
+func PrincipalFromRequest(r *http.Request) *Principal {
+	principal, ok := r.Context().Value(principalKey{}).(*Principal)
+	if !ok {
+		return nil
+	}
+	return principal
+}
+
+//bearerToken returns the token of a bearer authorization header or an empty string
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || strings.ToLower(header[:len(prefix)]) != prefix {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+type AuthenticationError struct {
+	Method string
+	Reason string
+}
+
+func (e *AuthenticationError) Error() string {
+	return fmt.Sprintf("%s authentication failed: %s", e.Method, e.Reason)
+}
+
+func IsAuthenticationError(err error) bool {
+	_, ok := err.(*AuthenticationError)
+	return ok
+}
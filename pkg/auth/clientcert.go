@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const methodClientCert = "client-certificate"
+
+//ClientCertConfig maps the common names of client certificates to roles. The certificates are verified
+//by the webserver against the certificates in CAFile (requires the webserver to run with TLS).
+type ClientCertConfig struct {
+	CAFile   string
+	Subjects []SubjectConfig
+}
+
+//SubjectConfig defines the roles granted to the holder of a client certificate
+type SubjectConfig struct {
+	CommonName string
+	Roles      []string
+}
+
+//ClientCertAuthenticator identifies clients by the common name of their verified TLS client certificate
+type ClientCertAuthenticator struct {
+	subjects map[string][]Role
+}
+
+func NewClientCertAuthenticator(subjects []SubjectConfig) (*ClientCertAuthenticator, error) {
+	ca := &ClientCertAuthenticator{
+		subjects: make(map[string][]Role, len(subjects)),
+	}
+	for _, subject := range subjects {
+		if subject.CommonName == "" {
+			return nil, fmt.Errorf("common name of client certificate subject is undefined")
+		}
+		if _, ok := ca.subjects[subject.CommonName]; ok {
+			return nil, fmt.Errorf("client certificate subject '%s' is not unique", subject.CommonName)
+		}
+		roles, err := newRoles(subject.Roles)
+		if err != nil {
+			return nil, fmt.Errorf("roles of client certificate subject '%s' are invalid: %s", subject.CommonName, err)
+		}
+		ca.subjects[subject.CommonName] = roles
+	}
+	return ca, nil
+}
+
+func (ca *ClientCertAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if commonName == "" {
+		return nil, &AuthenticationError{Method: methodClientCert, Reason: "certificate has no common name"}
+	}
+	//unknown subjects are authenticated without roles: their requests are rejected by the authorization
+	return &Principal{Name: commonName, Roles: ca.subjects[commonName], Method: methodClientCert}, nil
+}
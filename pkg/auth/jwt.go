@@ -0,0 +1,383 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	methodOIDC           = "oidc"
+	defaultUsernameClaim = "sub"
+	defaultRolesClaim    = "groups"
+	clockSkew            = time.Minute //tolerated clock difference between token issuer and mothership
+)
+
+//OIDCConfig defines how JWTs issued by an OIDC provider are validated. The signing keys of the provider
+//are read from a local JWKS file. RoleMapping maps values of the roles claim to roles: without mapping,
+//claim values which are equal to a role name are used.
+type OIDCConfig struct {
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+	UsernameClaim string
+	RolesClaim    string
+	RoleMapping   []RoleMappingConfig
+}
+
+//RoleMappingConfig grants a role to principals whose roles claim contains the claim value
+type RoleMappingConfig struct {
+	ClaimValue string
+	Role       string
+}
+
+//JWTAuthenticator identifies clients by signed JWTs (RS*, PS* and ES* signatures are supported)
+type JWTAuthenticator struct {
+	keys          map[string]crypto.PublicKey
+	issuer        string
+	audience      string
+	usernameClaim string
+	rolesClaim    string
+	roleMapping   map[string][]Role
+	now           func() time.Time
+}
+
+func NewJWTAuthenticator(cfg OIDCConfig) (*JWTAuthenticator, error) {
+	if cfg.JWKSFile == "" {
+		return nil, fmt.Errorf("JWKS file is undefined")
+	}
+	data, err := ioutil.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("JWKS file '%s' is invalid: %s", cfg.JWKSFile, err)
+	}
+	ja := &JWTAuthenticator{
+		keys:          keys,
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		usernameClaim: cfg.UsernameClaim,
+		rolesClaim:    cfg.RolesClaim,
+		roleMapping:   make(map[string][]Role, len(cfg.RoleMapping)),
+		now:           time.Now,
+	}
+	if ja.usernameClaim == "" {
+		ja.usernameClaim = defaultUsernameClaim
+	}
+	if ja.rolesClaim == "" {
+		ja.rolesClaim = defaultRolesClaim
+	}
+	for _, mapping := range cfg.RoleMapping {
+		role, err := NewRole(mapping.Role)
+		if err != nil {
+			return nil, fmt.Errorf("role mapping of claim value '%s' is invalid: %s", mapping.ClaimValue, err)
+		}
+		ja.roleMapping[mapping.ClaimValue] = append(ja.roleMapping[mapping.ClaimValue], role)
+	}
+	return ja, nil
+}
+
+func (ja *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil //no JWT
+	}
+	claims, err := ja.verify(parts)
+	if err != nil {
+		return nil, &AuthenticationError{Method: methodOIDC, Reason: err.Error()}
+	}
+	if err := ja.validateClaims(claims); err != nil {
+		return nil, &AuthenticationError{Method: methodOIDC, Reason: err.Error()}
+	}
+	username, ok := claims[ja.usernameClaim].(string)
+	if !ok || username == "" {
+		return nil, &AuthenticationError{Method: methodOIDC, Reason: fmt.Sprintf("claim '%s' is missing", ja.usernameClaim)}
+	}
+	return &Principal{Name: username, Roles: ja.roles(claims[ja.rolesClaim]), Method: methodOIDC}, nil
+}
+
+//verify checks the signature of the JWT and returns its claims
+func (ja *JWTAuthenticator) verify(parts []string) (map[string]interface{}, error) {
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("header is invalid: %s", err)
+	}
+	key, ok := ja.keys[header.Kid]
+	if !ok && header.Kid == "" && len(ja.keys) == 1 {
+		for _, singleKey := range ja.keys {
+			key, ok = singleKey, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("signing key '%s' is unknown", header.Kid)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("signature is not base64url encoded: %s", err)
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("claims are invalid: %s", err)
+	}
+	return claims, nil
+}
+
+func (ja *JWTAuthenticator) validateClaims(claims map[string]interface{}) error {
+	now := ja.now()
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("expiration time is missing")
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return fmt.Errorf("token expired at %s", exp)
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(clockSkew).Before(nbf) {
+		return fmt.Errorf("token is not valid before %s", nbf)
+	}
+	if ja.issuer != "" && claims["iss"] != ja.issuer {
+		return fmt.Errorf("issuer '%v' is not accepted", claims["iss"])
+	}
+	if ja.audience != "" && !containsString(claims["aud"], ja.audience) {
+		return fmt.Errorf("token is not issued for audience '%s'", ja.audience)
+	}
+	return nil
+}
+
+func (ja *JWTAuthenticator) roles(claim interface{}) []Role {
+	var result []Role
+	for _, value := range stringValues(claim) {
+		if len(ja.roleMapping) > 0 {
+			result = append(result, ja.roleMapping[value]...)
+			continue
+		}
+		if role, err := NewRole(value); err == nil {
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("signature algorithm '%s' is not supported", alg)
+	}
+	var hashFct func() hash.Hash
+	var hashType crypto.Hash
+	switch alg[len(alg)-3:] {
+	case "256":
+		hashFct, hashType = sha256.New, crypto.SHA256
+	case "384":
+		hashFct, hashType = sha512.New384, crypto.SHA384
+	case "512":
+		hashFct, hashType = sha512.New, crypto.SHA512
+	default:
+		return fmt.Errorf("signature algorithm '%s' is not supported", alg)
+	}
+	hasher := hashFct()
+	hasher.Write(signingInput)
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key is not suitable for algorithm '%s'", alg)
+		}
+		var err error
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(rsaKey, hashType, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hashType, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return fmt.Errorf("signature is invalid")
+		}
+		return nil
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().BitSize != ecdsaBitSize(alg) {
+			return fmt.Errorf("signing key is not suitable for algorithm '%s'", alg)
+		}
+		keyBytes := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*keyBytes {
+			return fmt.Errorf("signature is invalid")
+		}
+		r := new(big.Int).SetBytes(signature[:keyBytes])
+		s := new(big.Int).SetBytes(signature[keyBytes:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("signature is invalid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("signature algorithm '%s' is not supported", alg)
+	}
+}
+
+func ecdsaBitSize(alg string) int {
+	switch alg {
+	case "ES256":
+		return 256
+	case "ES384":
+		return 384
+	case "ES512":
+		return 521
+	}
+	return 0
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+//parseJWKS returns the public signing keys of a JSON Web Key Set (RFC 7517) by their key ID
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %s", key.Kid, err)
+		}
+		keys[key.Kid] = publicKey
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found")
+	}
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("curve '%s' is not supported", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve '%s'", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("key type '%s' is not supported", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("value is empty")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+func numericClaim(claims map[string]interface{}, name string) (time.Time, bool) {
+	number, ok := claims[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func stringValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, entry := range value {
+			if str, ok := entry.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func containsString(claim interface{}, expected string) bool {
+	for _, value := range stringValues(claim) {
+		if value == expected {
+			return true
+		}
+	}
+	return false
+}
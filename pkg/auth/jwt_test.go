@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksFile := writeJWKS(t, map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y)},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": encodeBigInt(otherKey.N), "e": "AQAB"},
+		},
+	})
+
+	ja, err := NewJWTAuthenticator(OIDCConfig{
+		JWKSFile: jwksFile,
+		Issuer:   "https://issuer.local",
+		Audience: "reconciler",
+		RoleMapping: []RoleMappingConfig{
+			{ClaimValue: "admins", Role: "operator"},
+			{ClaimValue: "admins", Role: "read-only"},
+			{ClaimValue: "brokers", Role: "keb"},
+		},
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	ja.now = func() time.Time { return now }
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub":    "alice",
+			"iss":    "https://issuer.local",
+			"aud":    []string{"reconciler", "other"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"groups": []string{"admins", "unknown"},
+		}
+	}
+
+	t.Run("Valid RS256 token", func(t *testing.T) {
+		principal, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", validClaims())))
+		require.NoError(t, err)
+		require.Equal(t, "alice", principal.Name)
+		require.Equal(t, []Role{RoleOperator, RoleReadOnly}, principal.Roles)
+		require.Equal(t, methodOIDC, principal.Method)
+	})
+
+	t.Run("Valid ES256 token", func(t *testing.T) {
+		claims := validClaims()
+		claims["groups"] = "brokers"
+		principal, err := ja.Authenticate(bearerRequest(signEC(t, ecKey, "ec", claims)))
+		require.NoError(t, err)
+		require.Equal(t, []Role{RoleKEB}, principal.Roles)
+	})
+
+	t.Run("Request without JWT is ignored", func(t *testing.T) {
+		principal, err := ja.Authenticate(bearerRequest("static-token"))
+		require.NoError(t, err)
+		require.Nil(t, principal)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = now.Add(-2 * time.Minute).Unix()
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.Error(t, err)
+		require.True(t, IsAuthenticationError(err))
+		require.Contains(t, err.Error(), "expired")
+	})
+
+	t.Run("Expiration within clock skew", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = now.Add(-30 * time.Second).Unix()
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.NoError(t, err)
+	})
+
+	t.Run("Token not yet valid", func(t *testing.T) {
+		claims := validClaims()
+		claims["nbf"] = now.Add(5 * time.Minute).Unix()
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.Error(t, err)
+	})
+
+	t.Run("Wrong audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "other"
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "audience")
+	})
+
+	t.Run("Wrong issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "https://evil.local"
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.Error(t, err)
+	})
+
+	t.Run("Signature of unknown key", func(t *testing.T) {
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, otherKey, "rsa", validClaims())))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "signature is invalid")
+	})
+
+	t.Run("Key not used for signatures", func(t *testing.T) {
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, otherKey, "enc", validClaims())))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown")
+	})
+
+	t.Run("Algorithm not matching the key", func(t *testing.T) {
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "ec", validClaims())))
+		require.Error(t, err)
+	})
+
+	t.Run("Unsigned token", func(t *testing.T) {
+		token := encodeSegment(t, map[string]string{"alg": "none", "kid": "rsa"}) + "." + encodeSegment(t, validClaims()) + "."
+		_, err := ja.Authenticate(bearerRequest(token))
+		require.Error(t, err)
+	})
+
+	t.Run("Missing username claim", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "sub")
+		_, err := ja.Authenticate(bearerRequest(signRSA(t, rsaKey, "rsa", claims)))
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		_, err := NewJWTAuthenticator(OIDCConfig{JWKSFile: jwksFile, RoleMapping: []RoleMappingConfig{{ClaimValue: "x", Role: "admin"}}})
+		require.Error(t, err)
+		_, err = NewJWTAuthenticator(OIDCConfig{JWKSFile: writeJWKS(t, map[string]interface{}{"keys": []interface{}{}})})
+		require.Error(t, err)
+	})
+}
+
+func writeJWKS(t *testing.T, jwks interface{}) string {
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, ioutil.WriteFile(file, data, 0600))
+	return file
+}
+
+func signRSA(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signEC(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": "ES256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/v1/clusters/test/status", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
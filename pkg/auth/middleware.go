@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+const realm = "reconciler"
+
+//Config defines the authentication methods of the mothership API. If authentication is enabled,
+//at least one method and the callback token have to be configured.
+type Config struct {
+	Enabled            bool
+	Tokens             []TokenConfig
+	ClientCertificates ClientCertConfig
+	OIDC               OIDCConfig
+	//CallbackToken is passed to the component reconcilers which use it to authenticate their
+	//callbacks (the token is granted the reconciler role)
+	CallbackToken string
+}
+
+//Middleware authenticates requests and authorizes them by the roles of the principal
+type Middleware struct {
+	enabled        bool
+	authenticators []Authenticator
+	logger         *zap.SugaredLogger
+}
+
+func NewMiddleware(cfg *Config, logger *zap.SugaredLogger) (*Middleware, error) {
+	m := &Middleware{
+		enabled: cfg.Enabled,
+		logger:  logger,
+	}
+	if !m.enabled {
+		return m, nil
+	}
+	if len(cfg.Tokens) == 0 && cfg.OIDC.JWKSFile == "" && cfg.ClientCertificates.CAFile == "" {
+		return nil, fmt.Errorf("authentication is enabled but no authentication method is configured")
+	}
+	if cfg.CallbackToken == "" {
+		return nil, fmt.Errorf("authentication is enabled but no callback token is configured: " +
+			"callbacks of component reconcilers would be rejected")
+	}
+
+	tokens := append(append([]TokenConfig{}, cfg.Tokens...), TokenConfig{
+		Name:  "component-reconciler",
+		Token: cfg.CallbackToken,
+		Roles: []string{string(RoleReconciler)},
+	})
+	tokenAuth, err := NewTokenAuthenticator(tokens)
+	if err != nil {
+		return nil, err
+	}
+	m.authenticators = append(m.authenticators, tokenAuth)
+	if cfg.OIDC.JWKSFile != "" {
+		jwtAuth, err := NewJWTAuthenticator(cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, jwtAuth)
+	}
+	if cfg.ClientCertificates.CAFile != "" {
+		certAuth, err := NewClientCertAuthenticator(cfg.ClientCertificates.Subjects)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, certAuth)
+	}
+	return m, nil
+}
+
+//Secure returns a handler which only calls the given handler for principals with at least one of the given roles.
+//The principal is added to the request context (see PrincipalFromRequest).
+func (m *Middleware) Secure(handler http.HandlerFunc, roles ...Role) http.HandlerFunc {
+	if !m.enabled {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := m.authenticate(r)
+		if err != nil {
+			m.logger.Infof("Rejecting request %s %s from %s: %s", r.Method, r.URL.Path, r.RemoteAddr, err)
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s"`, realm))
+			sendError(w, http.StatusUnauthorized, err)
+			return
+		}
+		if !principal.HasRole(roles...) {
+			m.logger.Infof("Rejecting request %s %s of '%s': one of the roles %v is required",
+				r.Method, r.URL.Path, principal.Name, roles)
+			sendError(w, http.StatusForbidden, fmt.Errorf("Principal '%s' is not authorized for this operation", principal.Name))
+			return
+		}
+		handler(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	}
+}
+
+//authenticate returns the principal identified by the first authenticator which handled the request credentials
+func (m *Middleware) authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range m.authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	if bearerToken(r) != "" {
+		return nil, &AuthenticationError{Method: methodToken, Reason: "bearer token is invalid"}
+	}
+	return nil, &AuthenticationError{Method: "request", Reason: "no credentials provided"}
+}
+
+func sendError(w http.ResponseWriter, httpCode int, err error) {
+	http.Error(w, fmt.Sprintf("%s\n\n%s", http.StatusText(httpCode), err.Error()), httpCode)
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMiddleware(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	var lastPrincipal *Principal
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		lastPrincipal = PrincipalFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	}
+	call := func(m *Middleware, r *http.Request, roles ...Role) *httptest.ResponseRecorder {
+		lastPrincipal = nil
+		w := httptest.NewRecorder()
+		m.Secure(handler, roles...)(w, r)
+		return w
+	}
+
+	t.Run("Disabled authentication", func(t *testing.T) {
+		m, err := NewMiddleware(&Config{}, logger)
+		require.NoError(t, err)
+		w := call(m, httptest.NewRequest(http.MethodGet, "/", nil), RoleOperator)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Nil(t, lastPrincipal)
+	})
+
+	t.Run("Enabled authentication without method", func(t *testing.T) {
+		_, err := NewMiddleware(&Config{Enabled: true, CallbackToken: "callback-token"}, logger)
+		require.Error(t, err)
+	})
+
+	t.Run("Enabled authentication without callback token", func(t *testing.T) {
+		_, err := NewMiddleware(&Config{
+			Enabled: true,
+			Tokens:  []TokenConfig{{Name: "keb", Token: "keb-token", Roles: []string{"KEB"}}},
+		}, logger)
+		require.Error(t, err)
+	})
+
+	m, err := NewMiddleware(&Config{
+		Enabled: true,
+		Tokens: []TokenConfig{
+			{Name: "keb", Token: "keb-token", Roles: []string{"KEB"}},
+			{Name: "viewer", Token: "viewer-token", Roles: []string{"read-only"}},
+		},
+		ClientCertificates: ClientCertConfig{
+			CAFile: "ca.pem", //loaded by the webserver
+			Subjects: []SubjectConfig{
+				{CommonName: "operator", Roles: []string{"operator"}},
+			},
+		},
+		CallbackToken: "callback-token",
+	}, logger)
+	require.NoError(t, err)
+
+	t.Run("Static token with required role", func(t *testing.T) {
+		w := call(m, bearerRequest("keb-token"), RoleKEB, RoleOperator)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "keb", lastPrincipal.Name)
+		require.Equal(t, methodToken, lastPrincipal.Method)
+	})
+
+	t.Run("Static token without required role", func(t *testing.T) {
+		w := call(m, bearerRequest("viewer-token"), RoleOperator)
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Nil(t, lastPrincipal)
+	})
+
+	t.Run("Callback token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, call(m, bearerRequest("callback-token"), RoleReconciler).Code)
+		require.Equal(t, http.StatusForbidden, call(m, bearerRequest("callback-token"), RoleReadOnly).Code)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		w := call(m, bearerRequest("unknown-token"), RoleReadOnly)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, `Bearer realm="reconciler"`, w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Missing credentials", func(t *testing.T) {
+		w := call(m, httptest.NewRequest(http.MethodGet, "/", nil), RoleReadOnly)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Client certificate", func(t *testing.T) {
+		w := call(m, certRequest("operator"), RoleOperator)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "operator", lastPrincipal.Name)
+		require.Equal(t, methodClientCert, lastPrincipal.Method)
+	})
+
+	t.Run("Client certificate of unknown subject", func(t *testing.T) {
+		w := call(m, certRequest("stranger"), RoleReadOnly)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		_, err := NewMiddleware(&Config{Enabled: true, CallbackToken: "cb",
+			Tokens: []TokenConfig{{Name: "a", Token: "x", Roles: []string{"admin"}}}}, logger)
+		require.Error(t, err)
+		_, err = NewMiddleware(&Config{Enabled: true, CallbackToken: "cb",
+			Tokens: []TokenConfig{{Name: "a", Token: "x"}, {Name: "b", Token: "x"}}}, logger)
+		require.Error(t, err)
+		_, err = NewMiddleware(&Config{Enabled: true, CallbackToken: "cb",
+			Tokens: []TokenConfig{{Name: "a"}}}, logger)
+		require.Error(t, err)
+	})
+}
+
+func certRequest(commonName string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{
+			{{Subject: pkix.Name{CommonName: commonName}}},
+		},
+	}
+	return r
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+)
+
+const methodToken = "token"
+
+//TokenConfig defines a static bearer token and the roles granted to its holder
+type TokenConfig struct {
+	Name  string
+	Token string
+	Roles []string
+}
+
+//TokenAuthenticator identifies clients by static bearer tokens
+type TokenAuthenticator struct {
+	tokens map[[sha256.Size]byte]*Principal
+}
+
+func NewTokenAuthenticator(tokens []TokenConfig) (*TokenAuthenticator, error) {
+	ta := &TokenAuthenticator{
+		tokens: make(map[[sha256.Size]byte]*Principal, len(tokens)),
+	}
+	for _, token := range tokens {
+		if err := ta.add(token); err != nil {
+			return nil, err
+		}
+	}
+	return ta, nil
+}
+
+func (ta *TokenAuthenticator) add(token TokenConfig) error {
+	if token.Name == "" {
+		return fmt.Errorf("name of static token is undefined")
+	}
+	if token.Token == "" {
+		return fmt.Errorf("static token '%s' is empty", token.Name)
+	}
+	roles, err := newRoles(token.Roles)
+	if err != nil {
+		return fmt.Errorf("static token '%s' is invalid: %s", token.Name, err)
+	}
+	hash := sha256.Sum256([]byte(token.Token))
+	if _, ok := ta.tokens[hash]; ok {
+		return fmt.Errorf("static token '%s' is not unique", token.Name)
+	}
+	ta.tokens[hash] = &Principal{Name: token.Name, Roles: roles, Method: methodToken}
+	return nil
+}
+
+func (ta *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return nil, nil
+	}
+	//compare hashes in constant time to avoid leaking the token content by the response time
+	hash := sha256.Sum256([]byte(token))
+	for knownHash, principal := range ta.tokens {
+		if subtle.ConstantTimeCompare(hash[:], knownHash[:]) == 1 {
+			return principal, nil
+		}
+	}
+	return nil, nil //might be a token of another authenticator
+}
@@ -85,15 +85,16 @@ func (cr *Repository) Add(cacheEntry *model.CacheEntryEntity, cacheDeps []*model
 	}
 
 	//create new cache entry and track its dependencies
-	dbOps := func() (interface{}, error) {
-		q, err := db.NewQuery(cr.Conn, cacheEntry)
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		txRepo := cr.WithConnection(tx)
+		q, err := db.NewQuery(txRepo.Conn, cacheEntry)
 		if err != nil {
 			return cacheEntry, err
 		}
 		if err := q.Insert().Exec(); err != nil {
 			return cacheEntry, err
 		}
-		if err := cr.CacheDep.Record(cacheEntry, cacheDeps).Exec(false); err != nil {
+		if err := txRepo.CacheDep.Record(cacheEntry, cacheDeps).Exec(false); err != nil {
 			return cacheEntry, err
 		}
 		return cacheEntry, err
@@ -108,15 +109,17 @@ func (cr *Repository) Add(cacheEntry *model.CacheEntryEntity, cacheDeps []*model
 }
 
 func (cr *Repository) Invalidate(label, cluster string) error {
-	dbOps := func() error {
+	dbOps := func(tx db.Connection) error {
+		txRepo := cr.WithConnection(tx)
+
 		//invalidate the cache entity and drop all tracked dependencies
-		if err := cr.CacheDep.Invalidate().WithLabel(label).WithCluster(cluster).Exec(false); err != nil {
+		if err := txRepo.CacheDep.Invalidate().WithLabel(label).WithCluster(cluster).Exec(false); err != nil {
 			return err
 		}
 
 		//as cache dependencies are optional we cannot rely that the previous
 		//invalidation dropped the cache entity: delete the entity also explicitly
-		q, err := db.NewQuery(cr.Conn, &model.CacheEntryEntity{})
+		q, err := db.NewQuery(txRepo.Conn, &model.CacheEntryEntity{})
 		if err != nil {
 			return err
 		}
@@ -130,15 +133,17 @@ func (cr *Repository) Invalidate(label, cluster string) error {
 }
 
 func (cr *Repository) InvalidateByID(id int64) error {
-	dbOps := func() error {
+	dbOps := func(tx db.Connection) error {
+		txRepo := cr.WithConnection(tx)
+
 		//invalidate the cache entity and drop all tracked dependencies
-		if err := cr.CacheDep.Invalidate().WithCacheID(id).Exec(false); err != nil {
+		if err := txRepo.CacheDep.Invalidate().WithCacheID(id).Exec(false); err != nil {
 			return err
 		}
 
 		//as cache dependencies are optional we cannot rely that the previous
 		//invalidation dropped the cache entity: delete the entity also explicitly
-		q, err := db.NewQuery(cr.Conn, &model.CacheEntryEntity{})
+		q, err := db.NewQuery(txRepo.Conn, &model.CacheEntryEntity{})
 		if err != nil {
 			return err
 		}
@@ -150,3 +155,7 @@ func (cr *Repository) InvalidateByID(id int64) error {
 	}
 	return cr.Transactional(dbOps)
 }
+
+func (cr *Repository) Close() error {
+	return cr.Conn.Close()
+}
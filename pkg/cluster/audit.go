@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+)
+
+//audit records a change of a cluster in the audit log
+func (i *DefaultInventory) audit(action audit.Action, cluster string, before, after *State) error {
+	entry := &audit.Entry{
+		Actor:    i.actor,
+		Action:   action,
+		Entity:   audit.EntityCluster,
+		EntityID: cluster,
+	}
+	if before != nil { //avoid typed nil values: they would be stored as JSON 'null'
+		entry.Before = stateSummary(before)
+	}
+	if after != nil {
+		entry.After = stateSummary(after)
+	}
+	return audit.Log(i.Conn, entry)
+}
+
+//stateSummary returns the versions and the status of a cluster state
+func stateSummary(state *State) map[string]interface{} {
+	summary := map[string]interface{}{}
+	if state.Cluster != nil {
+		summary["clusterVersion"] = state.Cluster.Version
+	}
+	if state.Configuration != nil {
+		summary["configVersion"] = state.Configuration.Version
+		summary["kymaVersion"] = state.Configuration.KymaVersion
+		summary["kymaProfile"] = state.Configuration.KymaProfile
+	}
+	if state.Status != nil {
+		summary["status"] = state.Status.Status
+	}
+	return summary
+}
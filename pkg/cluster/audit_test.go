@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryAudit(t *testing.T) {
+	connFact, err := db.NewTestConnectionFactory()
+	require.NoError(t, err)
+	inventory, err := NewInventory(connFact, true, fakeMetricsCollector{})
+	require.NoError(t, err)
+	auditRepo, err := audit.NewRepository(connFact, true)
+	require.NoError(t, err)
+
+	cluster := newCluster(t, 1, 1)
+	auditEntries := func() []*model.AuditEntity {
+		entries, err := auditRepo.Entries(&audit.Filter{Entity: audit.EntityCluster, EntityID: cluster.Cluster})
+		require.NoError(t, err)
+		return entries
+	}
+
+	t.Run("Create and update are recorded with actor", func(t *testing.T) {
+		stateV1, err := inventory.WithActor("keb").CreateOrUpdate(1, cluster)
+		require.NoError(t, err)
+		cluster.KymaConfig.Version = "kymaVersionNew"
+		stateV2, err := inventory.CreateOrUpdate(1, cluster)
+		require.NoError(t, err)
+
+		entries := auditEntries()
+		require.Len(t, entries, 2)
+		require.Equal(t, string(audit.ClusterUpdate), entries[0].Action)
+		require.Equal(t, audit.SystemActor, entries[0].Actor)
+		require.Contains(t, entries[0].BeforeSummary, `"kymaVersion":"kymaVersion1"`)
+		require.Contains(t, entries[0].AfterSummary, `"kymaVersion":"kymaVersionNew"`)
+		require.NotEqual(t, stateV1.Configuration.Version, stateV2.Configuration.Version)
+
+		require.Equal(t, string(audit.ClusterCreate), entries[1].Action)
+		require.Equal(t, "keb", entries[1].Actor)
+		require.Empty(t, entries[1].BeforeSummary)
+	})
+
+	t.Run("Rollback and manual reconciliation are recorded", func(t *testing.T) {
+		state, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		_, err = inventory.Rollback(cluster.Cluster, state.Configuration.Version-1, "jdoe")
+		require.NoError(t, err)
+		require.NoError(t, inventory.WithActor("operator").MarkReconcilePending(cluster.Cluster))
+
+		entries := auditEntries()
+		require.Len(t, entries, 4)
+		require.Equal(t, string(audit.ClusterReconcile), entries[0].Action)
+		require.Equal(t, "operator", entries[0].Actor)
+		require.Equal(t, string(audit.ClusterRollback), entries[1].Action)
+		require.Equal(t, "jdoe", entries[1].Actor) //user of the rollback is used if no actor is defined
+		require.Contains(t, entries[1].AfterSummary, `"user":"jdoe"`)
+	})
+
+	t.Run("Deletion is recorded", func(t *testing.T) {
+		require.NoError(t, inventory.WithActor("keb").Delete(cluster.Cluster))
+
+		entries := auditEntries()
+		require.Len(t, entries, 5)
+		require.Equal(t, string(audit.ClusterDelete), entries[0].Action)
+		require.Equal(t, "keb", entries[0].Actor)
+		require.NotEmpty(t, entries[0].BeforeSummary)
+		require.Empty(t, entries[0].AfterSummary)
+	})
+
+	t.Run("Failed changes are not recorded", func(t *testing.T) {
+		_, err := inventory.Rollback(cluster.Cluster, 1, "jdoe") //cluster was deleted
+		require.Error(t, err)
+		require.Len(t, auditEntries(), 5)
+	})
+}
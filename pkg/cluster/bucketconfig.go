@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/cache"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/kv"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"go.uber.org/zap"
+)
+
+const (
+	landscapeBucketPrefix     = "landscape"
+	globalAccountBucketPrefix = "globalaccount"
+	clusterBucketPrefix       = "cluster"
+)
+
+var invalidBucketChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+//BucketConfiguration merges the configuration values of all buckets assigned to a cluster
+//(default → landscape → global account → cluster) and caches the result per cluster
+type BucketConfiguration struct {
+	kvRepo    *kv.Repository
+	cacheRepo *cache.Repository
+	landscape string
+	logger    *zap.SugaredLogger
+}
+
+func NewBucketConfiguration(kvRepo *kv.Repository, cacheRepo *cache.Repository, landscape string, debug bool) (*BucketConfiguration, error) {
+	log, err := logger.NewLogger(debug)
+	if err != nil {
+		return nil, err
+	}
+	return &BucketConfiguration{
+		kvRepo:    kvRepo,
+		cacheRepo: cacheRepo,
+		landscape: landscape,
+		logger:    log,
+	}, nil
+}
+
+//Buckets returns the ordered list of buckets used for a cluster: values of a later bucket overwrite
+//values of a previous bucket
+func (bc *BucketConfiguration) Buckets(state *State) ([]string, error) {
+	metadata, err := state.Cluster.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+	buckets := []string{model.DefaultBucket}
+	if bc.landscape != "" {
+		buckets = append(buckets, bucketName(landscapeBucketPrefix, bc.landscape))
+	}
+	if metadata.GlobalAccountID != "" {
+		buckets = append(buckets, bucketName(globalAccountBucketPrefix, metadata.GlobalAccountID))
+	}
+	return append(buckets, bucketName(clusterBucketPrefix, state.Cluster.Cluster)), nil
+}
+
+//bucketName converts an identifier to a valid bucket name
+func bucketName(prefix, id string) string {
+	return fmt.Sprintf("%s-%s", prefix, strings.Trim(invalidBucketChars.ReplaceAllString(strings.ToLower(id), "-"), "-"))
+}
+
+//Configuration returns the merged configuration entries of the buckets assigned to the cluster.
+//The merge result is stored in the cache which tracks the used values: a change of such a value
+//invalidates the cache entry and schedules a new reconciliation of the cluster.
+func (bc *BucketConfiguration) Configuration(state *State) ([]keb.Configuration, error) {
+	buckets, err := bc.Buckets(state)
+	if err != nil {
+		return nil, err
+	}
+
+	//merge the buckets
+	merger := &bucketMerger{}
+	for _, bucket := range buckets {
+		values, err := bc.kvRepo.ValuesByBucket(bucket)
+		if err != nil {
+			return nil, err
+		}
+		if err := merger.Add(bucket, values); err != nil {
+			return nil, err
+		}
+	}
+	values := merger.ValuesList()
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+
+	configuration := make([]keb.Configuration, 0, len(values))
+	for _, value := range values {
+		key, err := bc.kvRepo.Key(value.Key, value.KeyVersion)
+		if err != nil {
+			return nil, err
+		}
+		configuration = append(configuration, keb.Configuration{
+			Key:      value.Key,
+			Value:    value.Value,
+			Secret:   key.Encrypted,
+			DataType: string(key.DataType),
+		})
+	}
+
+	//cache the merge result and track the used values
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+	label := strings.Join(buckets, ",")
+	if _, err := bc.cacheRepo.Add(&model.CacheEntryEntity{
+		Label:   label,
+		Cluster: state.Cluster.Cluster,
+		Data:    string(data),
+	}, values); err != nil {
+		return nil, err
+	}
+	bc.logger.Debugf("Merged %d configuration values of buckets '%s' for cluster '%s'", len(configuration), label, state.Cluster.Cluster)
+
+	return configuration, nil
+}
+
+//MergeConfiguration returns a copy of the component which contains the bucket configuration entries
+//(entries defined for the component have precedence)
+func MergeConfiguration(component *keb.Components, configuration []keb.Configuration) *keb.Components {
+	result := *component
+	result.Configuration = append([]keb.Configuration{}, component.Configuration...)
+	defined := make(map[string]interface{}, len(component.Configuration))
+	for _, cfg := range component.Configuration {
+		defined[cfg.Key] = nil
+	}
+	for _, cfg := range configuration {
+		if _, ok := defined[cfg.Key]; !ok {
+			result.Configuration = append(result.Configuration, cfg)
+		}
+	}
+	return &result
+}
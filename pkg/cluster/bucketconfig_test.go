@@ -0,0 +1,130 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cache"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/kv"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketConfiguration(t *testing.T) {
+	connFac, err := db.NewTestConnectionFactory()
+	require.NoError(t, err)
+	kvRepo, err := kv.NewRepository(connFac, true)
+	require.NoError(t, err)
+	cacheRepo, err := cache.NewRepository(connFac, true)
+	require.NoError(t, err)
+
+	bucketConfig, err := NewBucketConfiguration(kvRepo, cacheRepo, "Dev", true)
+	require.NoError(t, err)
+
+	ts := time.Now().UnixNano()
+	state := &State{
+		Cluster: &model.ClusterEntity{
+			Cluster:  fmt.Sprintf("Bucket_Cluster_%d", ts),
+			Contract: 1,
+			Metadata: fmt.Sprintf(`{"globalAccountID":"GA-%d"}`, ts),
+		},
+	}
+
+	t.Run("Buckets of a cluster", func(t *testing.T) {
+		buckets, err := bucketConfig.Buckets(state)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"default",
+			"landscape-dev",
+			fmt.Sprintf("globalaccount-ga-%d", ts),
+			fmt.Sprintf("cluster-bucket-cluster-%d", ts),
+		}, buckets)
+		for _, bucket := range buckets {
+			require.NoError(t, model.ValidateBucketName(bucket))
+		}
+	})
+
+	t.Run("Merge configuration of buckets", func(t *testing.T) {
+		buckets, err := bucketConfig.Buckets(state)
+		require.NoError(t, err)
+
+		keyPrefix := fmt.Sprintf("bucketConfigKey%d", ts)
+		keys := make(map[string]*model.KeyEntity)
+		for _, key := range []string{"a", "b", "c"} {
+			keys[key], err = kvRepo.CreateKey(&model.KeyEntity{
+				Key:       fmt.Sprintf("%s.%s", keyPrefix, key),
+				DataType:  model.String,
+				Encrypted: key == "c",
+				Username:  "testUsername",
+			})
+			require.NoError(t, err)
+		}
+		setValue := func(bucket, key, value string) {
+			_, err := kvRepo.CreateValue(&model.ValueEntity{
+				Key:        keys[key].Key,
+				KeyVersion: keys[key].Version,
+				Bucket:     bucket,
+				Value:      value,
+				Username:   "testUsername",
+			})
+			require.NoError(t, err)
+		}
+		setValue(buckets[1], "a", "landscape")
+		setValue(buckets[1], "b", "landscape")
+		setValue(buckets[2], "b", "globalaccount")
+		setValue(buckets[3], "c", "cluster")
+		defer func() {
+			for _, key := range keys {
+				require.NoError(t, kvRepo.DeleteKey(key.Key))
+			}
+		}()
+
+		configuration, err := bucketConfig.Configuration(state)
+		require.NoError(t, err)
+
+		//filter entries of this test (the default bucket can contain entries of other tests)
+		var result []keb.Configuration
+		for _, cfg := range configuration {
+			if strings.HasPrefix(cfg.Key, keyPrefix) {
+				result = append(result, cfg)
+			}
+		}
+		require.Equal(t, []keb.Configuration{
+			{Key: keys["a"].Key, Value: "landscape", DataType: "string"},
+			{Key: keys["b"].Key, Value: "globalaccount", DataType: "string"},
+			{Key: keys["c"].Key, Value: "cluster", Secret: true, DataType: "string"},
+		}, result)
+
+		//merge result is cached and its dependencies are tracked
+		cacheDeps, err := cacheRepo.CacheDep.Get().WithCluster(state.Cluster.Cluster).Exec()
+		require.NoError(t, err)
+		require.Len(t, cacheDeps, len(configuration))
+	})
+
+	t.Run("Merge configuration into component", func(t *testing.T) {
+		component := &keb.Components{
+			Component: "logging",
+			Namespace: "kyma-system",
+			Configuration: []keb.Configuration{
+				{Key: "a", Value: "component"},
+			},
+		}
+		result := MergeConfiguration(component, []keb.Configuration{
+			{Key: "a", Value: "bucket"},
+			{Key: "b", Value: "bucket", Secret: true},
+		})
+		require.Equal(t, &keb.Components{
+			Component: "logging",
+			Namespace: "kyma-system",
+			Configuration: []keb.Configuration{
+				{Key: "a", Value: "component"},
+				{Key: "b", Value: "bucket", Secret: true},
+			},
+		}, result)
+		require.Len(t, component.Configuration, 1) //original component is unchanged
+	})
+}
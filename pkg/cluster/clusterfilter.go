@@ -8,27 +8,30 @@ import (
 )
 
 type createdIntervalFilter struct {
-	cluster  string
 	interval time.Duration
 }
 
-func (rif *createdIntervalFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, error) {
-	clusterColName, err := statusColHdr.ColumnName("Cluster")
+func (rif *createdIntervalFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, []interface{}, error) {
+	createdColName, err := statusColHdr.ColumnName("Created")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	createdColName, err := statusColHdr.ColumnName("Created")
+	timeAgoSQL, timeAgoArg, err := timeAgo(dbType, "$1", rif.interval)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
+	return fmt.Sprintf("%s >= %s", createdColName, timeAgoSQL), []interface{}{timeAgoArg}, nil
+}
+
+//timeAgo returns an SQL expression which calculates the point in time the duration ago and the
+//argument which has to be passed for the placeholder
+func timeAgo(dbType db.Type, plcHdr string, duration time.Duration) (string, interface{}, error) {
 	switch dbType {
 	case db.Postgres:
-		return fmt.Sprintf(`%s = '%s' AND %s >= NOW() - INTERVAL '%.0f SECOND'`,
-			clusterColName, rif.cluster, createdColName, rif.interval.Seconds()), nil
+		return fmt.Sprintf("NOW() - CAST(%s AS INTERVAL)", plcHdr), fmt.Sprintf("%.0f SECONDS", duration.Seconds()), nil
 	case db.SQLite:
-		return fmt.Sprintf(`%s = '%s' AND %s >= DATETIME('now', '-%.0f SECONDS')`,
-			clusterColName, rif.cluster, createdColName, rif.interval.Seconds()), nil
+		return fmt.Sprintf("DATETIME('now', %s)", plcHdr), fmt.Sprintf("-%.0f SECONDS", duration.Seconds()), nil
 	default:
-		return "", fmt.Errorf("Database type '%s' is not supported by this filter", dbType)
+		return "", nil, fmt.Errorf("Database type '%s' is not supported by this filter", dbType)
 	}
 }
@@ -0,0 +1,215 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeUpdated ChangeType = "changed"
+
+	maskedValue = "***"
+)
+
+type ChangeType string
+
+//ConfigurationDiff describes the differences between two versions of a cluster configuration
+type ConfigurationDiff struct {
+	Cluster           string           `json:"cluster"`
+	FromVersion       int64            `json:"fromVersion"`
+	ToVersion         int64            `json:"toVersion"`
+	KymaVersion       *ValueChange     `json:"kymaVersion,omitempty"`
+	KymaProfile       *ValueChange     `json:"kymaProfile,omitempty"`
+	AddedComponents   []string         `json:"addedComponents"`
+	RemovedComponents []string         `json:"removedComponents"`
+	ChangedComponents []*ComponentDiff `json:"changedComponents"`
+}
+
+func (d *ConfigurationDiff) String() string {
+	return fmt.Sprintf("ConfigurationDiff [Cluster=%s,FromVersion=%d,ToVersion=%d]",
+		d.Cluster, d.FromVersion, d.ToVersion)
+}
+
+//Empty returns true if both configuration versions are equal
+func (d *ConfigurationDiff) Empty() bool {
+	return d.KymaVersion == nil && d.KymaProfile == nil &&
+		len(d.AddedComponents) == 0 && len(d.RemovedComponents) == 0 && len(d.ChangedComponents) == 0
+}
+
+type ValueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ComponentDiff struct {
+	Component     string                 `json:"component"`
+	Namespace     *ValueChange           `json:"namespace,omitempty"`
+	Version       *ValueChange           `json:"version,omitempty"`
+	URL           *ValueChange           `json:"url,omitempty"`
+	Dependencies  *ValueChange           `json:"dependencies,omitempty"` //comma separated list of the dependencies
+	Configuration []*ConfigurationChange `json:"configuration"`
+}
+
+//ConfigurationChange describes the change of a component configuration entry (values of secrets are masked)
+type ConfigurationChange struct {
+	Key    string     `json:"key"`
+	Change ChangeType `json:"change"`
+	From   string     `json:"from,omitempty"`
+	To     string     `json:"to,omitempty"`
+	Secret bool       `json:"secret"`
+}
+
+func newConfigurationDiff(from, to *model.ClusterConfigurationEntity) (*ConfigurationDiff, error) {
+	diff := &ConfigurationDiff{
+		Cluster:           to.Cluster,
+		FromVersion:       from.Version,
+		ToVersion:         to.Version,
+		KymaVersion:       newValueChange(from.KymaVersion, to.KymaVersion),
+		KymaProfile:       newValueChange(from.KymaProfile, to.KymaProfile),
+		AddedComponents:   []string{},
+		RemovedComponents: []string{},
+		ChangedComponents: []*ComponentDiff{},
+	}
+
+	fromComponents, err := componentsByName(from)
+	if err != nil {
+		return nil, err
+	}
+	toComponents, err := componentsByName(to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedComponentNames(fromComponents) {
+		if _, ok := toComponents[name]; !ok {
+			diff.RemovedComponents = append(diff.RemovedComponents, name)
+		}
+	}
+	for _, name := range sortedComponentNames(toComponents) {
+		fromComponent, ok := fromComponents[name]
+		if !ok {
+			diff.AddedComponents = append(diff.AddedComponents, name)
+			continue
+		}
+		if componentDiff := newComponentDiff(fromComponent, toComponents[name]); componentDiff != nil {
+			diff.ChangedComponents = append(diff.ChangedComponents, componentDiff)
+		}
+	}
+
+	return diff, nil
+}
+
+func newComponentDiff(from, to *keb.Components) *ComponentDiff {
+	diff := &ComponentDiff{
+		Component:     to.Component,
+		Namespace:     newValueChange(from.Namespace, to.Namespace),
+		Version:       newValueChange(from.Version, to.Version),
+		URL:           newValueChange(from.URL, to.URL),
+		Dependencies:  newValueChange(strings.Join(from.Dependencies, ","), strings.Join(to.Dependencies, ",")),
+		Configuration: []*ConfigurationChange{},
+	}
+
+	fromCfg := configurationByKey(from)
+	toCfg := configurationByKey(to)
+
+	for _, key := range sortedConfigurationKeys(fromCfg) {
+		if _, ok := toCfg[key]; !ok {
+			diff.Configuration = append(diff.Configuration, newConfigurationChange(ChangeRemoved, fromCfg[key], nil))
+		}
+	}
+	for _, key := range sortedConfigurationKeys(toCfg) {
+		fromEntry, ok := fromCfg[key]
+		if !ok {
+			diff.Configuration = append(diff.Configuration, newConfigurationChange(ChangeAdded, nil, toCfg[key]))
+			continue
+		}
+		if fromEntry.Value != toCfg[key].Value || fromEntry.Secret != toCfg[key].Secret {
+			diff.Configuration = append(diff.Configuration, newConfigurationChange(ChangeUpdated, fromEntry, toCfg[key]))
+		}
+	}
+
+	if diff.Namespace == nil && diff.Version == nil && diff.URL == nil && diff.Dependencies == nil &&
+		len(diff.Configuration) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func newConfigurationChange(change ChangeType, from, to *keb.Configuration) *ConfigurationChange {
+	cfgChange := &ConfigurationChange{
+		Change: change,
+	}
+	if from != nil {
+		cfgChange.Key = from.Key
+		cfgChange.From = from.Value
+		cfgChange.Secret = from.Secret
+	}
+	if to != nil {
+		cfgChange.Key = to.Key
+		cfgChange.To = to.Value
+		cfgChange.Secret = cfgChange.Secret || to.Secret
+	}
+	if cfgChange.Secret {
+		if cfgChange.From != "" {
+			cfgChange.From = maskedValue
+		}
+		if cfgChange.To != "" {
+			cfgChange.To = maskedValue
+		}
+	}
+	return cfgChange
+}
+
+func newValueChange(from, to string) *ValueChange {
+	if from == to {
+		return nil
+	}
+	return &ValueChange{
+		From: from,
+		To:   to,
+	}
+}
+
+func componentsByName(config *model.ClusterConfigurationEntity) (map[string]*keb.Components, error) {
+	components, err := config.GetComponents()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*keb.Components, len(components))
+	for _, component := range components {
+		result[component.Component] = component
+	}
+	return result, nil
+}
+
+func configurationByKey(component *keb.Components) map[string]*keb.Configuration {
+	result := make(map[string]*keb.Configuration, len(component.Configuration))
+	for idx := range component.Configuration {
+		result[component.Configuration[idx].Key] = &component.Configuration[idx]
+	}
+	return result
+}
+
+func sortedComponentNames(components map[string]*keb.Components) []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedConfigurationKeys(configuration map[string]*keb.Configuration) []string {
+	keys := make([]string, 0, len(configuration))
+	for key := range configuration {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
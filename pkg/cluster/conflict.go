@@ -0,0 +1,28 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+//StatusConflictError indicates that the status of a cluster was changed concurrently
+//and the status the update was based on is outdated
+type StatusConflictError struct {
+	Cluster          string
+	ExpectedStatusID int64
+	CurrentStatus    *model.ClusterStatusEntity
+}
+
+func (e *StatusConflictError) Error() string {
+	return fmt.Sprintf("Status of cluster '%s' was changed concurrently: expected status ID %d but current status is '%s' (ID %d)",
+		e.Cluster, e.ExpectedStatusID, e.CurrentStatus.Status, e.CurrentStatus.ID)
+}
+
+func IsStatusConflictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*StatusConflictError)
+	return ok
+}
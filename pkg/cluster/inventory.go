@@ -1,31 +1,41 @@
 package cluster
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/kyma-incubator/reconciler/pkg/audit"
 	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/repository"
 )
 
+//maxStatusUpdateAttempts is the number of attempts to update a status which was changed concurrently
+const maxStatusUpdateAttempts = 3
+
 type Inventory interface {
 	CreateOrUpdate(contractVersion int64, cluster *keb.Cluster) (*State, error)
 	UpdateStatus(State *State, status model.Status) (*State, error)
+	MarkReconcilePending(cluster string) error
 	Delete(cluster string) error
 	Get(cluster string, configVersion int64) (*State, error)
 	GetLatest(cluster string) (*State, error)
+	Diff(cluster string, fromConfigVersion, toConfigVersion int64) (*ConfigurationDiff, error)
+	Rollback(cluster string, configVersion int64, username string) (*State, error)
+	Rollbacks(cluster string) ([]*model.ClusterConfigRollbackEntity, error)
 	StatusChanges(cluster string, offset time.Duration) ([]*StatusChange, error)
 	ClustersToReconcile(reconcileInterval time.Duration) ([]*State, error)
 	ClustersNotReady() ([]*State, error)
+	Purge(policy *RetentionPolicy) (*PurgeResult, error)
+	WithActor(actor string) Inventory
 }
 
 type DefaultInventory struct {
 	*repository.Repository
 	metricsCollector
+	actor string //recorded in the audit log as originator of changes
 }
 
 type metricsCollector interface {
@@ -37,28 +47,56 @@ func NewInventory(dbFac db.ConnectionFactory, debug bool, collector metricsColle
 	if err != nil {
 		return nil, err
 	}
-	return &DefaultInventory{repo, collector}, nil
+	return &DefaultInventory{Repository: repo, metricsCollector: collector}, nil
+}
+
+//withConnection returns a copy of the inventory which issues all queries through the given connection
+func (i *DefaultInventory) withConnection(conn db.Connection) *DefaultInventory {
+	return &DefaultInventory{
+		Repository:       i.Repository.WithConnection(conn),
+		metricsCollector: i.metricsCollector,
+		actor:            i.actor,
+	}
+}
+
+//WithActor returns a copy of the inventory which records the given actor as originator of all changes in the audit log
+func (i *DefaultInventory) WithActor(actor string) Inventory {
+	return &DefaultInventory{
+		Repository:       i.Repository,
+		metricsCollector: i.metricsCollector,
+		actor:            actor,
+	}
 }
 
 func (i *DefaultInventory) CreateOrUpdate(contractVersion int64, cluster *keb.Cluster) (*State, error) {
-	dbOps := func() (interface{}, error) {
-		clusterEntity, err := i.createCluster(contractVersion, cluster)
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		txInv := i.withConnection(tx)
+		previousState, err := txInv.GetLatest(cluster.Cluster)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return nil, err
+		}
+		clusterEntity, err := txInv.createCluster(contractVersion, cluster)
 		if err != nil {
 			return nil, err
 		}
-		clusterConfigurationEntity, err := i.createConfiguration(contractVersion, cluster, clusterEntity)
+		clusterConfigurationEntity, err := txInv.createConfiguration(contractVersion, cluster, clusterEntity)
 		if err != nil {
 			return nil, err
 		}
-		clusterStatusEntity, err := i.createStatus(clusterConfigurationEntity, model.ReconcilePending)
+		clusterStatusEntity, err := txInv.createStatus(clusterConfigurationEntity, model.ReconcilePending)
 		if err != nil {
 			return nil, err
 		}
-		return &State{
+		state := &State{
 			Cluster:       clusterEntity,
 			Configuration: clusterConfigurationEntity,
 			Status:        clusterStatusEntity,
-		}, nil
+		}
+		action := audit.ClusterUpdate
+		if previousState == nil {
+			action = audit.ClusterCreate
+		}
+		return state, txInv.audit(action, cluster.Cluster, previousState, state)
 	}
 	stateEntity, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
 	if err != nil {
@@ -80,13 +118,23 @@ func (i *DefaultInventory) createCluster(contractVersion int64, cluster *keb.Clu
 	if err != nil {
 		return nil, err
 	}
+	labels, err := marshalStringMap(cluster.Labels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := marshalStringMap(cluster.Annotations)
+	if err != nil {
+		return nil, err
+	}
 
 	newClusterEntity := &model.ClusterEntity{
-		Cluster:    cluster.Cluster,
-		Runtime:    string(runtime),
-		Metadata:   string(metadata),
-		Kubeconfig: cluster.Kubeconfig,
-		Contract:   contractVersion,
+		Cluster:     cluster.Cluster,
+		Runtime:     string(runtime),
+		Metadata:    string(metadata),
+		Kubeconfig:  cluster.Kubeconfig,
+		Contract:    contractVersion,
+		Labels:      labels,
+		Annotations: annotations,
 	}
 
 	//check if a new version is required
@@ -114,6 +162,16 @@ func (i *DefaultInventory) createCluster(contractVersion int64, cluster *keb.Clu
 	return newClusterEntity, nil
 }
 
+//marshalStringMap returns an empty string for empty maps to keep clusters without labels or annotations
+//equal to clusters which were stored before these fields were introduced
+func marshalStringMap(values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	result, err := json.Marshal(values)
+	return string(result), err
+}
+
 func (i *DefaultInventory) createConfiguration(contractVersion int64, cluster *keb.Cluster, clusterEntity *model.ClusterEntity) (*model.ClusterConfigurationEntity, error) {
 	components, err := json.Marshal(cluster.KymaConfig.Components)
 	if err != nil {
@@ -131,6 +189,7 @@ func (i *DefaultInventory) createConfiguration(contractVersion int64, cluster *k
 		Components:     string(components),
 		Administrators: string(administrators),
 		Contract:       contractVersion,
+		Urgent:         cluster.KymaConfig.Urgent,
 	}
 
 	//check if a new version is required
@@ -191,12 +250,36 @@ func (i *DefaultInventory) createStatus(configEntity *model.ClusterConfiguration
 	return newStatusEntity, nil
 }
 
+//UpdateStatus sets the status of a cluster with compare-and-set semantics: the update is only applied if the
+//status of the given state is still the latest status of the cluster, otherwise a StatusConflictError is returned.
 func (i *DefaultInventory) UpdateStatus(state *State, status model.Status) (*State, error) {
-	newStatus, err := i.createStatus(state.Configuration, status)
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		txInv := i.withConnection(tx)
+		cluster := state.Configuration.Cluster
+		if err := db.Lock(tx, fmt.Sprintf("cluster-status:%s", cluster)); err != nil {
+			return nil, err
+		}
+		currentStatus, err := txInv.latestClusterStatus(cluster)
+		if err != nil {
+			return nil, err
+		}
+		if state.Status == nil || state.Status.ID != currentStatus.ID {
+			conflictErr := &StatusConflictError{
+				Cluster:       cluster,
+				CurrentStatus: currentStatus,
+			}
+			if state.Status != nil {
+				conflictErr.ExpectedStatusID = state.Status.ID
+			}
+			return nil, conflictErr
+		}
+		return txInv.createStatus(state.Configuration, status)
+	}
+	newStatus, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
 	if err != nil {
 		return state, err
 	}
-	state.Status = newStatus
+	state.Status = newStatus.(*model.ClusterStatusEntity)
 	err = i.metricsCollector.OnClusterStateUpdate(state)
 	if err != nil {
 		return state, err
@@ -204,14 +287,38 @@ func (i *DefaultInventory) UpdateStatus(state *State, status model.Status) (*Sta
 	return state, nil
 }
 
+//MarkReconcilePending schedules a reconciliation of the latest cluster configuration
+func (i *DefaultInventory) MarkReconcilePending(cluster string) error {
+	for attempt := 1; ; attempt++ {
+		state, err := i.GetLatest(cluster)
+		if err != nil {
+			return err
+		}
+		previousState := *state //UpdateStatus modifies the given state
+		_, err = i.UpdateStatus(state, model.ReconcilePending)
+		if err == nil {
+			return i.audit(audit.ClusterReconcile, cluster, &previousState, state)
+		}
+		if !IsStatusConflictError(err) || attempt >= maxStatusUpdateAttempts {
+			return err
+		}
+		i.Logger.Debugf("Retrying to mark cluster '%s' as reconcile pending: %s", cluster, err)
+	}
+}
+
 func (i *DefaultInventory) Delete(cluster string) error {
-	dbOps := func() error {
-		newClusterName := fmt.Sprintf("deleted_%d_%s", time.Now().Unix(), cluster)
+	dbOps := func(tx db.Connection) error {
+		txInv := i.withConnection(tx)
+		previousState, err := txInv.GetLatest(cluster)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return err
+		}
+		newClusterName := fmt.Sprintf("%s%d_%s", deletedClusterPrefix, time.Now().Unix(), cluster)
 		updateSQLTpl := "UPDATE %s SET %s=$1, %s='TRUE' WHERE %s=$2 OR %s=$3" //OR condition required for Postgres: new cluster-name is automatically cascaded to config-status table
 
 		//update name of all cluster entities
 		clusterEntity := &model.ClusterEntity{}
-		clusterColHandler, err := db.NewColumnHandler(clusterEntity, i.Conn)
+		clusterColHandler, err := db.NewColumnHandler(clusterEntity, txInv.Conn)
 		if err != nil {
 			return err
 		}
@@ -224,13 +331,13 @@ func (i *DefaultInventory) Delete(cluster string) error {
 			return err
 		}
 		clusterUpdateSQL := fmt.Sprintf(updateSQLTpl, clusterEntity.Table(), clusterColName, clusterDelColName, clusterColName, clusterColName)
-		if _, err := i.Conn.Exec(clusterUpdateSQL, newClusterName, cluster, newClusterName); err != nil {
+		if _, err := txInv.Conn.Exec(clusterUpdateSQL, newClusterName, cluster, newClusterName); err != nil {
 			return err
 		}
 
 		//update cluster-name of all referenced cluster-config entities
 		configEntity := &model.ClusterConfigurationEntity{}
-		configColHandler, err := db.NewColumnHandler(configEntity, i.Conn)
+		configColHandler, err := db.NewColumnHandler(configEntity, txInv.Conn)
 		if err != nil {
 			return err
 		}
@@ -243,12 +350,28 @@ func (i *DefaultInventory) Delete(cluster string) error {
 			return err
 		}
 		configUpdateSQL := fmt.Sprintf(updateSQLTpl, configEntity.Table(), configClusterColName, configDelColName, configClusterColName, configClusterColName)
-		if _, err := i.Conn.Exec(configUpdateSQL, newClusterName, cluster, newClusterName); err != nil {
+		if _, err := txInv.Conn.Exec(configUpdateSQL, newClusterName, cluster, newClusterName); err != nil {
+			return err
+		}
+
+		//update cluster-name of all rollbacks (they are not referenced by a foreign key and would otherwise
+		//be inherited by a new cluster with the same name)
+		rollbackEntity := &model.ClusterConfigRollbackEntity{}
+		rollbackColHandler, err := db.NewColumnHandler(rollbackEntity, txInv.Conn)
+		if err != nil {
+			return err
+		}
+		rollbackClusterColName, err := rollbackColHandler.ColumnName("Cluster")
+		if err != nil {
+			return err
+		}
+		rollbackUpdateSQL := fmt.Sprintf("UPDATE %s SET %s=$1 WHERE %s=$2", rollbackEntity.Table(), rollbackClusterColName, rollbackClusterColName)
+		if _, err := txInv.Conn.Exec(rollbackUpdateSQL, newClusterName, cluster); err != nil {
 			return err
 		}
 
 		//done
-		return nil
+		return txInv.audit(audit.ClusterDelete, cluster, previousState, nil)
 	}
 	return db.Transaction(i.Conn, dbOps, i.Logger)
 }
@@ -294,6 +417,144 @@ func (i *DefaultInventory) GetLatest(cluster string) (*State, error) {
 	}, nil
 }
 
+func (i *DefaultInventory) Diff(cluster string, fromConfigVersion, toConfigVersion int64) (*ConfigurationDiff, error) {
+	fromConfigEntity, err := i.config(cluster, fromConfigVersion)
+	if err != nil {
+		return nil, err
+	}
+	toConfigEntity, err := i.config(cluster, toConfigVersion)
+	if err != nil {
+		return nil, err
+	}
+	return newConfigurationDiff(fromConfigEntity, toConfigEntity)
+}
+
+//Rollback creates a new configuration version which is a copy of the given configuration version
+//and schedules it for reconciliation. The user who triggered the rollback is recorded.
+func (i *DefaultInventory) Rollback(cluster string, configVersion int64, username string) (*State, error) {
+	if username == "" {
+		return nil, fmt.Errorf("username is required to rollback configuration of cluster '%s'", cluster)
+	}
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		txInv := i.withConnection(tx)
+		previousState, err := txInv.GetLatest(cluster)
+		if err != nil {
+			return nil, err
+		}
+		clusterEntity := previousState.Cluster
+		sourceConfigEntity, err := txInv.config(cluster, configVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		//create new version (rollbacks are urgent and bypass maintenance windows)
+		newConfigEntity := &model.ClusterConfigurationEntity{
+			Cluster:        clusterEntity.Cluster,
+			ClusterVersion: clusterEntity.Version,
+			KymaVersion:    sourceConfigEntity.KymaVersion,
+			KymaProfile:    sourceConfigEntity.KymaProfile,
+			Components:     sourceConfigEntity.Components,
+			Administrators: sourceConfigEntity.Administrators,
+			Contract:       sourceConfigEntity.Contract,
+			Urgent:         true,
+		}
+		q, err := db.NewQuery(txInv.Conn, newConfigEntity)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.Insert().Exec(); err != nil {
+			return nil, err
+		}
+
+		//record who triggered the rollback
+		rollbackEntity := &model.ClusterConfigRollbackEntity{
+			Cluster:             clusterEntity.Cluster,
+			ConfigVersion:       newConfigEntity.Version,
+			SourceConfigVersion: sourceConfigEntity.Version,
+			Username:            username,
+		}
+		q, err = db.NewQuery(txInv.Conn, rollbackEntity)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.Insert().Exec(); err != nil {
+			return nil, err
+		}
+
+		clusterStatusEntity, err := txInv.createStatus(newConfigEntity, model.ReconcilePending)
+		if err != nil {
+			return nil, err
+		}
+		txInv.Logger.Infof("User '%s' rolled back configuration of cluster '%s' to version %d (new configuration version is %d)",
+			username, cluster, sourceConfigEntity.Version, newConfigEntity.Version)
+		state := &State{
+			Cluster:       clusterEntity,
+			Configuration: newConfigEntity,
+			Status:        clusterStatusEntity,
+		}
+		after := stateSummary(state)
+		after["sourceConfigVersion"] = sourceConfigEntity.Version
+		after["user"] = username
+		return state, audit.Log(txInv.Conn, &audit.Entry{
+			Actor:    audit.Actor(i.actor, username),
+			Action:   audit.ClusterRollback,
+			Entity:   audit.EntityCluster,
+			EntityID: cluster,
+			Before:   stateSummary(previousState),
+			After:    after,
+		})
+	}
+	stateEntity, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
+	if err != nil {
+		return nil, err
+	}
+	err = i.metricsCollector.OnClusterStateUpdate(stateEntity.(*State))
+	if err != nil {
+		return nil, err
+	}
+	return stateEntity.(*State), nil
+}
+
+//Rollbacks returns the audit trail of all configuration rollbacks of a cluster
+func (i *DefaultInventory) Rollbacks(cluster string) ([]*model.ClusterConfigRollbackEntity, error) {
+	q, err := db.NewQuery(i.Conn, &model.ClusterConfigRollbackEntity{})
+	if err != nil {
+		return nil, err
+	}
+	entities, err := q.Select().
+		Where(map[string]interface{}{"Cluster": cluster}).
+		OrderBy(map[string]string{"ID": "asc"}).
+		GetMany()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.ClusterConfigRollbackEntity, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, entity.(*model.ClusterConfigRollbackEntity))
+	}
+	return result, nil
+}
+
+//latestClusterStatus returns the latest status of a cluster (independent of its configuration version)
+func (i *DefaultInventory) latestClusterStatus(cluster string) (*model.ClusterStatusEntity, error) {
+	q, err := db.NewQuery(i.Conn, &model.ClusterStatusEntity{})
+	if err != nil {
+		return nil, err
+	}
+	whereCond := map[string]interface{}{
+		"Cluster": cluster,
+	}
+	statusEntity, err := q.Select().
+		Where(whereCond).
+		OrderBy(map[string]string{"ID": "desc"}).
+		Limit(1).
+		GetOne()
+	if err != nil {
+		return nil, i.NewNotFoundError(err, statusEntity, whereCond)
+	}
+	return statusEntity.(*model.ClusterStatusEntity), nil
+}
+
 func (i *DefaultInventory) latestStatus(configVersion int64) (*model.ClusterStatusEntity, error) {
 	q, err := db.NewQuery(i.Conn, &model.ClusterStatusEntity{})
 	if err != nil {
@@ -442,35 +703,33 @@ func (i *DefaultInventory) filterClusters(filters ...statusSQLFilter) ([]*State,
 			select maxid from (
 				select max(cluster_version) as maxcfg, max(id) as maxid from inventory_cluster_config_statuses group by cluster
 			) as maxid
-		) and (status in ('xxx', 'yyy') or (status = 'ready' AND created >=  NOW() - INTERVAL '12345 SECOND'))
+		) and (status in ($1, $2))
 	*/
-	var sqlFilterStmt bytes.Buffer
-	if len(filters) == 0 {
-		sqlFilterStmt.WriteString("1=1") //if no filters are provided, use 1=1 as placeholder to ensure valid SQL query
-	}
+	//table and column names cannot be passed as arguments and are rendered into the sub-query
+	latestStatusesSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE %s IN (
+					SELECT maxid FROM (
+						SELECT MAX(%s) AS maxcfg, MAX(%s) AS maxid FROM %s GROUP BY %s
+					) AS maxid
+				)`,
+		configVersionColName, clusterStatus.Table(), idColName,
+		clusterVersionColName, idColName, clusterStatus.Table(), clusterColName)
+
+	//a configuration is selected if its latest status matches one of the filters
+	var filterConds []db.Condition
 	for _, filter := range filters {
-		sqlCond, err := filter.Filter(i.Conn.Type(), statusColHandler)
+		sqlCond, args, err := filter.Filter(i.Conn.Type(), statusColHandler)
 		if err != nil {
 			return nil, err
 		}
-		if sqlFilterStmt.Len() > 0 {
-			sqlFilterStmt.WriteString(" OR ")
-		}
-		sqlFilterStmt.WriteRune('(')
-		sqlFilterStmt.WriteString(sqlCond)
-		sqlFilterStmt.WriteRune(')')
+		filterConds = append(filterConds,
+			db.InSubQuery("Version", fmt.Sprintf("%s AND (%s)", latestStatusesSQL, sqlCond), args...))
+	}
+	if len(filterConds) == 0 {
+		filterConds = append(filterConds, db.InSubQuery("Version", latestStatusesSQL))
 	}
 
 	clusterConfigs, err := q.Select().
-		WhereIn("Version",
-			fmt.Sprintf(`SELECT %s FROM %s WHERE %s IN (
-							SELECT maxid FROM (
-								SELECT MAX(%s) AS maxcfg, MAX(%s) AS maxid FROM %s GROUP BY %s
-							) AS maxid
-						) AND (%s)`,
-				configVersionColName, clusterStatus.Table(), idColName,
-				clusterVersionColName, idColName, clusterStatus.Table(), clusterColName,
-				sqlFilterStmt.String())).
+		WhereCondition(db.Or(filterConds...)).
 		Where(map[string]interface{}{
 			"Deleted": false,
 		}).
@@ -498,46 +757,42 @@ func (i *DefaultInventory) StatusChanges(cluster string, offset time.Duration) (
 	if err != nil {
 		return nil, err
 	}
-	statusColName, err := statusColHandler.ColumnName("Status")
-	if err != nil {
-		return nil, err
+	filter := createdIntervalFilter{
+		interval: offset,
 	}
-	createdColName, err := statusColHandler.ColumnName("Created")
+	sqlCond, args, err := filter.Filter(i.Conn.Type(), statusColHandler)
 	if err != nil {
 		return nil, err
 	}
 
-	filter := createdIntervalFilter{
-		interval: offset,
-		cluster:  cluster,
-	}
-	sqlCond, err := filter.Filter(i.Conn.Type(), statusColHandler)
+	q, err := db.NewQuery(i.Conn, &model.ClusterStatusEntity{})
 	if err != nil {
 		return nil, err
 	}
-	rows, err := i.Conn.Query(fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s ORDER BY %s DESC", statusColName, createdColName, (&model.ClusterStatusEntity{}).Table(), sqlCond, createdColName))
+	statusEntities, err := q.Select().
+		WhereCondition(db.Eq("Cluster", cluster), db.Raw(sqlCond, args...)).
+		OrderBy(map[string]string{"Created": "DESC"}).
+		GetMany()
 	if err != nil {
 		return nil, err
 	}
+
 	var statusChanges []*StatusChange
 	var createdPrevStatus time.Time
-	var createdCurrStatus time.Time
-	for rows.Next() {
-		var status *model.Status
-		if err := rows.Scan(&status, &createdCurrStatus); err != nil {
-			return nil, err
-		}
+	for _, entity := range statusEntities {
+		statusEntity := entity.(*model.ClusterStatusEntity)
+		status := statusEntity.Status
 		var duration string
 		if createdPrevStatus.IsZero() {
-			duration = time.Since(createdCurrStatus).String()
+			duration = time.Since(statusEntity.Created).String()
 		} else {
-			duration = createdPrevStatus.Sub(createdCurrStatus).String()
+			duration = createdPrevStatus.Sub(statusEntity.Created).String()
 		}
 		statusChanges = append(statusChanges, &StatusChange{
-			Status:   status,
+			Status:   &status,
 			Duration: duration,
 		})
-		createdPrevStatus = createdCurrStatus
+		createdPrevStatus = statusEntity.Created
 	}
 	return statusChanges, nil
 }
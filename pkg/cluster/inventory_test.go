@@ -84,6 +84,22 @@ func TestInventory(t *testing.T) {
 		require.True(t, oldStatusID < newState2.Status.ID)
 	})
 
+	t.Run("Mark cluster as reconcile pending", func(t *testing.T) {
+		cluster := newCluster(t, 1, maxVersion)
+		clusterState, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		_, err = inventory.UpdateStatus(clusterState, model.Ready)
+		require.NoError(t, err)
+
+		require.NoError(t, inventory.MarkReconcilePending(cluster.Cluster))
+		newState, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		require.Equal(t, model.ReconcilePending, newState.Status.Status)
+
+		err = inventory.MarkReconcilePending("idontexist")
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
 	t.Run("Delete a cluster", func(t *testing.T) {
 		//get cluster1
 		expectedCluster := newCluster(t, 1, 1)
@@ -129,6 +145,21 @@ func TestInventory(t *testing.T) {
 			listStatuses(statesReconcile),
 			[]model.Status{model.ReconcilePending, model.ReconcileFailed})
 
+		//ready cluster is not reconciled before the reconcile interval elapsed
+		statesReconcile, err = inventory.ClustersToReconcile(1 * time.Hour)
+		require.NoError(t, err)
+		require.ElementsMatch(t,
+			listStatuses(statesReconcile),
+			[]model.Status{model.ReconcilePending, model.ReconcileFailed})
+
+		//ready cluster is reconciled after the reconcile interval elapsed
+		time.Sleep(2 * time.Second)
+		statesReconcile, err = inventory.ClustersToReconcile(1 * time.Second)
+		require.NoError(t, err)
+		require.ElementsMatch(t,
+			listStatuses(statesReconcile),
+			[]model.Status{model.ReconcilePending, model.ReconcileFailed, model.Ready})
+
 		//check clusters which are not ready
 		statesNotReady, err := inventory.ClustersNotReady()
 		require.NoError(t, err)
@@ -216,6 +247,253 @@ func TestInventory(t *testing.T) {
 		//TODO: test for clusters which are inside and outside of filter interval
 	})
 
+	t.Run("Diff between configuration versions", func(t *testing.T) {
+		inventory := newInventory(t)
+
+		clusterV1 := newCluster(t, 1, 1)
+		stateV1, err := inventory.CreateOrUpdate(1, clusterV1)
+		require.NoError(t, err)
+
+		clusterV2 := newCluster(t, 1, 1)
+		clusterV2.KymaConfig.Version = "kymaVersionNew"
+		clusterV2.KymaConfig.Components = clusterV2.KymaConfig.Components[1:] //drop first component
+		clusterV2.KymaConfig.Components = append(clusterV2.KymaConfig.Components, keb.Components{
+			Component: "newComponent",
+			Namespace: "kyma-system",
+		})
+		clusterV2.KymaConfig.Components[0].Configuration = []keb.Configuration{
+			{Key: clusterV2.KymaConfig.Components[0].Configuration[0].Key, Value: "changed", Secret: true},
+		}
+		stateV2, err := inventory.CreateOrUpdate(1, clusterV2)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, inventory.Delete(clusterV1.Cluster))
+		}()
+
+		diff, err := inventory.Diff(clusterV1.Cluster, stateV1.Configuration.Version, stateV2.Configuration.Version)
+		require.NoError(t, err)
+		require.Equal(t, &ValueChange{From: "kymaVersion1", To: "kymaVersionNew"}, diff.KymaVersion)
+		require.Nil(t, diff.KymaProfile)
+		require.Equal(t, []string{clusterV1.KymaConfig.Components[0].Component}, diff.RemovedComponents)
+		require.Equal(t, []string{"newComponent"}, diff.AddedComponents)
+		require.Len(t, diff.ChangedComponents, 1)
+		require.Equal(t, clusterV2.KymaConfig.Components[0].Component, diff.ChangedComponents[0].Component)
+		for _, cfgChange := range diff.ChangedComponents[0].Configuration {
+			switch cfgChange.Change {
+			case ChangeUpdated:
+				require.True(t, cfgChange.Secret)
+				require.Equal(t, maskedValue, cfgChange.From) //secret values are masked
+				require.Equal(t, maskedValue, cfgChange.To)
+			case ChangeRemoved:
+				require.NotEmpty(t, cfgChange.From)
+				require.Empty(t, cfgChange.To)
+			default:
+				require.Fail(t, "Unexpected configuration change '%s'", cfgChange.Change)
+			}
+		}
+
+		//diff of the same version is empty
+		diff, err = inventory.Diff(clusterV1.Cluster, stateV2.Configuration.Version, stateV2.Configuration.Version)
+		require.NoError(t, err)
+		require.True(t, diff.Empty())
+
+		//unknown versions are reported as not found
+		_, err = inventory.Diff(clusterV1.Cluster, stateV1.Configuration.Version, 999999)
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
+	t.Run("Rollback to previous configuration version", func(t *testing.T) {
+		inventory := newInventory(t)
+
+		clusterV1 := newCluster(t, 1, 1)
+		stateV1, err := inventory.CreateOrUpdate(1, clusterV1)
+		require.NoError(t, err)
+
+		clusterV2 := newCluster(t, 1, 1)
+		clusterV2.KymaConfig.Version = "kymaVersionBroken"
+		stateV2, err := inventory.CreateOrUpdate(1, clusterV2)
+		require.NoError(t, err)
+		_, err = inventory.UpdateStatus(stateV2, model.Error)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, inventory.Delete(clusterV1.Cluster))
+		}()
+
+		//username is mandatory
+		_, err = inventory.Rollback(clusterV1.Cluster, stateV1.Configuration.Version, "")
+		require.Error(t, err)
+
+		//unknown config version
+		_, err = inventory.Rollback(clusterV1.Cluster, stateV2.Configuration.Version+100, "jdoe")
+		require.True(t, repository.IsNotFoundError(err))
+
+		stateRollback, err := inventory.Rollback(clusterV1.Cluster, stateV1.Configuration.Version, "jdoe")
+		require.NoError(t, err)
+		require.Greater(t, stateRollback.Configuration.Version, stateV2.Configuration.Version)
+		require.Equal(t, model.ReconcilePending, stateRollback.Status.Status)
+		require.True(t, stateRollback.Configuration.Urgent)
+
+		//rolled back configuration is the latest configuration and equal to the old one
+		stateLatest, err := inventory.GetLatest(clusterV1.Cluster)
+		require.NoError(t, err)
+		require.Equal(t, stateRollback.Configuration.Version, stateLatest.Configuration.Version)
+		diff, err := inventory.Diff(clusterV1.Cluster, stateV1.Configuration.Version, stateLatest.Configuration.Version)
+		require.NoError(t, err)
+		require.True(t, diff.Empty())
+
+		//audit trail
+		rollbacks, err := inventory.Rollbacks(clusterV1.Cluster)
+		require.NoError(t, err)
+		require.Len(t, rollbacks, 1)
+		require.Equal(t, "jdoe", rollbacks[0].Username)
+		require.Equal(t, stateV1.Configuration.Version, rollbacks[0].SourceConfigVersion)
+		require.Equal(t, stateRollback.Configuration.Version, rollbacks[0].ConfigVersion)
+
+		//rollbacks are not inherited by a new cluster with the same name
+		require.NoError(t, inventory.Delete(clusterV1.Cluster))
+		rollbacks, err = inventory.Rollbacks(clusterV1.Cluster)
+		require.NoError(t, err)
+		require.Empty(t, rollbacks)
+	})
+
+	t.Run("Purge outdated inventory entries", func(t *testing.T) {
+		inventory := newInventory(t)
+
+		//create three configuration versions
+		var states []*State
+		for _, kymaVersion := range []string{"1.0.0", "2.0.0", "3.0.0"} {
+			clusterModel := newCluster(t, 1, 1)
+			clusterModel.KymaConfig.Version = kymaVersion
+			state, err := inventory.CreateOrUpdate(1, clusterModel)
+			require.NoError(t, err)
+			states = append(states, state)
+		}
+		clusterName := states[0].Cluster.Cluster
+		for _, status := range []model.Status{model.Reconciling, model.Ready} {
+			_, err := inventory.UpdateStatus(states[2], status)
+			require.NoError(t, err)
+		}
+
+		defer func() {
+			require.NoError(t, inventory.Delete(clusterName))
+		}()
+
+		_, err := inventory.Purge(&RetentionPolicy{ConfigVersions: -1})
+		require.Error(t, err)
+
+		//keep the two latest configuration versions
+		result, err := inventory.Purge(&RetentionPolicy{ConfigVersions: 2})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, result.Configurations, int64(1))
+		require.GreaterOrEqual(t, result.Statuses, int64(1))
+		_, err = inventory.Get(clusterName, states[0].Configuration.Version)
+		require.True(t, repository.IsNotFoundError(err))
+		_, err = inventory.Get(clusterName, states[1].Configuration.Version)
+		require.NoError(t, err)
+
+		//purge all statuses except the latest one
+		time.Sleep(2 * time.Second)
+		result, err = inventory.Purge(&RetentionPolicy{StatusRetention: 1 * time.Second})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, result.Statuses, int64(2))
+		state, err := inventory.GetLatest(clusterName)
+		require.NoError(t, err)
+		require.Equal(t, model.Ready, state.Status.Status)
+		changes, err := inventory.StatusChanges(clusterName, 1*time.Hour)
+		require.NoError(t, err)
+		require.NotContains(t, listStatusesForStatusChanges(changes), model.Reconciling) //interim status was purged
+
+		//purge deleted clusters
+		require.NoError(t, inventory.Delete(clusterName))
+		result, err = inventory.Purge(&RetentionPolicy{DeletedClusterRetention: 1 * time.Nanosecond})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, result.Clusters, int64(1))
+		require.GreaterOrEqual(t, result.Configurations, int64(2))
+		_, err = inventory.Get(clusterName, states[2].Configuration.Version)
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
+	t.Run("Purge rollbacks of outdated configurations and deleted clusters", func(t *testing.T) {
+		inventory := newInventory(t)
+
+		var states []*State
+		for _, kymaVersion := range []string{"1.0.0", "2.0.0"} {
+			clusterModel := newCluster(t, 2, 1)
+			clusterModel.KymaConfig.Version = kymaVersion
+			state, err := inventory.CreateOrUpdate(1, clusterModel)
+			require.NoError(t, err)
+			states = append(states, state)
+		}
+		clusterName := states[0].Cluster.Cluster
+
+		defer func() {
+			require.NoError(t, inventory.Delete(clusterName))
+		}()
+
+		//rollback from the outdated configuration is purged together with it
+		_, err := inventory.Rollback(clusterName, states[0].Configuration.Version, "jdoe")
+		require.NoError(t, err)
+		result, err := inventory.Purge(&RetentionPolicy{ConfigVersions: 2})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, result.Rollbacks, int64(1))
+		rollbacks, err := inventory.Rollbacks(clusterName)
+		require.NoError(t, err)
+		require.Empty(t, rollbacks)
+
+		//rollbacks of a deleted cluster are purged with the cluster
+		_, err = inventory.Rollback(clusterName, states[1].Configuration.Version, "jdoe")
+		require.NoError(t, err)
+		require.NoError(t, inventory.Delete(clusterName))
+		result, err = inventory.Purge(&RetentionPolicy{DeletedClusterRetention: 1 * time.Nanosecond})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, result.Rollbacks, int64(1))
+
+		q, err := db.NewQuery(inventory.(*DefaultInventory).Conn, &model.ClusterConfigRollbackEntity{})
+		require.NoError(t, err)
+		remaining, err := q.Select().WhereCondition(db.HasPrefix("Cluster", deletedClusterPrefix)).GetMany()
+		require.NoError(t, err)
+		require.Empty(t, remaining)
+	})
+
+	t.Run("Kubeconfig and secret configuration values are encrypted at rest", func(t *testing.T) {
+		inventory := newInventory(t)
+
+		clusterModel := newCluster(t, 1, 1)
+		clusterModel.KymaConfig.Components[0].Configuration = append(clusterModel.KymaConfig.Components[0].Configuration,
+			keb.Configuration{Key: "secret.password", Value: "topSecret", Secret: true})
+		state, err := inventory.CreateOrUpdate(1, clusterModel)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, inventory.Delete(clusterModel.Cluster))
+		}()
+
+		//raw database values are encrypted
+		conn := inventory.(*DefaultInventory).Conn
+		var kubeconfig, components string
+		row := conn.QueryRow(fmt.Sprintf("SELECT kubeconfig FROM %s WHERE version=$1", state.Cluster.Table()), state.Cluster.Version)
+		require.NoError(t, row.Scan(&kubeconfig))
+		require.NotEqual(t, clusterModel.Kubeconfig, kubeconfig)
+		require.True(t, conn.Encryptor().Decryptable(kubeconfig))
+		row = conn.QueryRow(fmt.Sprintf("SELECT components FROM %s WHERE version=$1", state.Configuration.Table()), state.Configuration.Version)
+		require.NoError(t, row.Scan(&components))
+		require.NotContains(t, components, "topSecret")
+		require.Contains(t, components, clusterModel.KymaConfig.Components[0].Configuration[0].Value) //non-secrets are not encrypted
+
+		//values are decrypted transparently
+		stateLatest, err := inventory.GetLatest(clusterModel.Cluster)
+		require.NoError(t, err)
+		require.Equal(t, clusterModel.Kubeconfig, stateLatest.Cluster.Kubeconfig)
+		require.Equal(t, toJSON(t, clusterModel.KymaConfig.Components), stateLatest.Configuration.Components)
+
+		//unchanged payload doesn't create a new configuration version
+		stateUnchanged, err := inventory.CreateOrUpdate(1, clusterModel)
+		require.NoError(t, err)
+		require.Equal(t, state.Configuration.Version, stateUnchanged.Configuration.Version)
+	})
+
 	t.Run("Get status changes", func(t *testing.T) {
 		inventory := newInventory(t)
 		expectedStatuses := append(clusterStatuses, model.ReconcilePending)
@@ -243,6 +521,78 @@ func TestInventory(t *testing.T) {
 			listStatusesForStatusChanges(changes),
 			expectedStatuses)
 	})
+
+	t.Run("Concurrent status updates are rejected", func(t *testing.T) {
+		inventory := newInventory(t)
+		cluster := newCluster(t, 99, 1)
+		_, err := inventory.CreateOrUpdate(1, cluster)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, inventory.Delete(cluster.Cluster))
+		}()
+
+		workerState1, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		workerState2, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+
+		//first update wins
+		_, err = inventory.UpdateStatus(workerState1, model.Reconciling)
+		require.NoError(t, err)
+
+		//second update is based on an outdated status
+		_, err = inventory.UpdateStatus(workerState2, model.Reconciling)
+		require.True(t, IsStatusConflictError(err))
+		require.Equal(t, workerState1.Status.ID, err.(*StatusConflictError).CurrentStatus.ID)
+
+		//stale status of an old configuration version can't overwrite the status of a newer version
+		newConfigState, err := inventory.CreateOrUpdate(1, newCluster(t, 99, 2))
+		require.NoError(t, err)
+		_, err = inventory.UpdateStatus(workerState1, model.Ready)
+		require.True(t, IsStatusConflictError(err))
+		latestState, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		require.Equal(t, newConfigState.Status.ID, latestState.Status.ID)
+		require.Equal(t, model.ReconcilePending, latestState.Status.Status)
+	})
+
+	t.Run("Store cluster of contract version 2", func(t *testing.T) {
+		inventory := newInventory(t)
+		cluster := newCluster(t, 98, 1)
+		cluster.Labels = map[string]string{"region": "eu"}
+		cluster.Annotations = map[string]string{"owner": "team-a"}
+		cluster.KymaConfig.Components[0].Version = "1.2.3"
+		cluster.KymaConfig.Components[0].URL = "https://example.com/charts/component.tgz"
+		cluster.KymaConfig.Components[1].Dependencies = []string{cluster.KymaConfig.Components[0].Component}
+		state, err := inventory.CreateOrUpdate(keb.ContractV2, cluster)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, inventory.Delete(cluster.Cluster))
+		}()
+
+		latestState, err := inventory.GetLatest(cluster.Cluster)
+		require.NoError(t, err)
+		require.Equal(t, state.Configuration.Version, latestState.Configuration.Version)
+		require.Equal(t, keb.ContractV2, latestState.Cluster.Contract)
+		require.Equal(t, keb.ContractV2, latestState.Configuration.Contract)
+		labels, err := latestState.Cluster.GetLabels()
+		require.NoError(t, err)
+		require.Equal(t, cluster.Labels, labels)
+		annotations, err := latestState.Cluster.GetAnnotations()
+		require.NoError(t, err)
+		require.Equal(t, cluster.Annotations, annotations)
+		components, err := latestState.Configuration.GetComponents()
+		require.NoError(t, err)
+		require.Equal(t, "1.2.3", components[0].Version)
+		require.Equal(t, cluster.KymaConfig.Components[0].URL, components[0].URL)
+		require.Equal(t, cluster.KymaConfig.Components[1].Dependencies, components[1].Dependencies)
+
+		//changed labels create a new cluster version
+		cluster.Labels["region"] = "us"
+		updatedState, err := inventory.CreateOrUpdate(keb.ContractV2, cluster)
+		require.NoError(t, err)
+		require.NotEqual(t, state.Cluster.Version, updatedState.Cluster.Version)
+	})
 }
 
 func listStatuses(states []*State) []model.Status {
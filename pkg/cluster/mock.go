@@ -13,14 +13,19 @@ import (
 const envVarKubeconfig = "KUBECONFIG"
 
 type MockInventory struct {
-	ClustersToReconcileResult []*State
-	ClustersNotReadyResult    []*State
-	GetResult                 *State
-	GetLatestResult           *State
-	CreateOrUpdateResult      *State
-	DeleteResult              error
-	UpdateStatusResult        *State
-	ChangesResult             []*StatusChange
+	ClustersToReconcileResult  []*State
+	ClustersNotReadyResult     []*State
+	GetResult                  *State
+	GetLatestResult            *State
+	CreateOrUpdateResult       *State
+	DeleteResult               error
+	UpdateStatusResult         *State
+	MarkReconcilePendingResult error
+	ChangesResult              []*StatusChange
+	DiffResult                 *ConfigurationDiff
+	RollbackResult             *State
+	RollbacksResult            []*model.ClusterConfigRollbackEntity
+	PurgeResult                *PurgeResult
 }
 
 func (i *MockInventory) CreateOrUpdate(contractVersion int64, cluster *keb.Cluster) (*State, error) {
@@ -31,6 +36,10 @@ func (i *MockInventory) UpdateStatus(State *State, status model.Status) (*State,
 	return i.UpdateStatusResult, nil
 }
 
+func (i *MockInventory) MarkReconcilePending(cluster string) error {
+	return i.MarkReconcilePendingResult
+}
+
 func (i *MockInventory) Delete(cluster string) error {
 	return i.DeleteResult
 }
@@ -43,6 +52,18 @@ func (i *MockInventory) GetLatest(cluster string) (*State, error) {
 	return i.GetLatestResult, nil
 }
 
+func (i *MockInventory) Diff(cluster string, fromConfigVersion, toConfigVersion int64) (*ConfigurationDiff, error) {
+	return i.DiffResult, nil
+}
+
+func (i *MockInventory) Rollback(cluster string, configVersion int64, username string) (*State, error) {
+	return i.RollbackResult, nil
+}
+
+func (i *MockInventory) Rollbacks(cluster string) ([]*model.ClusterConfigRollbackEntity, error) {
+	return i.RollbacksResult, nil
+}
+
 func (i *MockInventory) ClustersToReconcile(reconcileInterval time.Duration) ([]*State, error) {
 	return i.ClustersToReconcileResult, nil
 }
@@ -55,6 +76,14 @@ func (i *MockInventory) StatusChanges(cluster string, offset time.Duration) ([]*
 	return i.ChangesResult, nil
 }
 
+func (i *MockInventory) Purge(policy *RetentionPolicy) (*PurgeResult, error) {
+	return i.PurgeResult, nil
+}
+
+func (i *MockInventory) WithActor(actor string) Inventory {
+	return i
+}
+
 type MockKubeconfigProvider struct {
 	KubeconfigResult string
 }
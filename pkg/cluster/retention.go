@@ -0,0 +1,318 @@
+package cluster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+const deletedClusterPrefix = "deleted_"
+
+//RetentionPolicy defines which historical inventory entries are purged (a zero value disables the rule)
+type RetentionPolicy struct {
+	ConfigVersions          int           //amount of configuration versions to keep per cluster
+	StatusRetention         time.Duration //statuses older than this are purged (the latest status of a configuration is always kept)
+	DeletedClusterRetention time.Duration //deleted clusters are purged after this period
+}
+
+func (rp *RetentionPolicy) Validate() error {
+	if rp.ConfigVersions < 0 {
+		return fmt.Errorf("amount of configuration versions to keep cannot be < 0")
+	}
+	if rp.StatusRetention < 0 {
+		return fmt.Errorf("status retention cannot be < 0")
+	}
+	if rp.DeletedClusterRetention < 0 {
+		return fmt.Errorf("retention of deleted clusters cannot be < 0")
+	}
+	return nil
+}
+
+//PurgeResult contains the amount of removed rows per inventory table
+type PurgeResult struct {
+	Clusters       int64
+	Configurations int64
+	Statuses       int64
+	Rollbacks      int64
+}
+
+func (pr *PurgeResult) String() string {
+	return fmt.Sprintf("PurgeResult [Clusters=%d,Configurations=%d,Statuses=%d,Rollbacks=%d]",
+		pr.Clusters, pr.Configurations, pr.Statuses, pr.Rollbacks)
+}
+
+func (pr *PurgeResult) add(other *PurgeResult) {
+	pr.Clusters += other.Clusters
+	pr.Configurations += other.Configurations
+	pr.Statuses += other.Statuses
+	pr.Rollbacks += other.Rollbacks
+}
+
+func (i *DefaultInventory) Purge(policy *RetentionPolicy) (*PurgeResult, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		txInv := i.withConnection(tx)
+		result := &PurgeResult{}
+		if policy.DeletedClusterRetention > 0 {
+			purged, err := txInv.purgeDeletedClusters(policy.DeletedClusterRetention)
+			if err != nil {
+				return nil, err
+			}
+			result.add(purged)
+		}
+		if policy.ConfigVersions > 0 {
+			purged, err := txInv.purgeConfigVersions(policy.ConfigVersions)
+			if err != nil {
+				return nil, err
+			}
+			result.add(purged)
+		}
+		if policy.StatusRetention > 0 {
+			purged, err := txInv.purgeStatuses(policy.StatusRetention)
+			if err != nil {
+				return nil, err
+			}
+			result.add(purged)
+		}
+		if result.Clusters+result.Configurations+result.Statuses+result.Rollbacks == 0 {
+			return result, nil
+		}
+		return result, audit.Log(txInv.Conn, &audit.Entry{
+			Actor:    i.actor,
+			Action:   audit.InventoryPurge,
+			Entity:   audit.EntityInventory,
+			EntityID: "*",
+			After:    result,
+		})
+	}
+	result, err := db.TransactionResult(i.Conn, dbOps, i.Logger)
+	if err != nil {
+		return nil, err
+	}
+	i.Logger.Debugf("Purged inventory entries: %s", result)
+	return result.(*PurgeResult), nil
+}
+
+//purgeDeletedClusters removes all entries of clusters which were deleted before the retention period
+func (i *DefaultInventory) purgeDeletedClusters(retention time.Duration) (*PurgeResult, error) {
+	q, err := db.NewQuery(i.Conn, &model.ClusterEntity{})
+	if err != nil {
+		return nil, err
+	}
+	clusterEntities, err := q.Select().
+		WhereCondition(db.HasPrefix("Cluster", deletedClusterPrefix)).
+		GetMany()
+	if err != nil {
+		return nil, err
+	}
+	var clusters []string
+	processed := make(map[string]bool)
+	for _, entity := range clusterEntities {
+		cluster := entity.(*model.ClusterEntity).Cluster
+		if processed[cluster] { //each version of a cluster is a separate entity
+			continue
+		}
+		processed[cluster] = true
+		clusters = append(clusters, cluster)
+	}
+
+	result := &PurgeResult{}
+	threshold := time.Now().Add(-retention)
+	for _, cluster := range clusters {
+		deletedAt, err := deletionTime(cluster)
+		if err != nil {
+			i.Logger.Warnf("Skipping purge of deleted cluster '%s': %s", cluster, err)
+			continue
+		}
+		if deletedAt.After(threshold) {
+			continue
+		}
+		whereCond := map[string]interface{}{
+			"Cluster": cluster,
+		}
+		if result.Statuses, err = i.purgeEntities(&model.ClusterStatusEntity{}, whereCond, result.Statuses); err != nil {
+			return nil, err
+		}
+		if result.Configurations, err = i.purgeEntities(&model.ClusterConfigurationEntity{}, whereCond, result.Configurations); err != nil {
+			return nil, err
+		}
+		if result.Rollbacks, err = i.purgeEntities(&model.ClusterConfigRollbackEntity{}, whereCond, result.Rollbacks); err != nil {
+			return nil, err
+		}
+		if result.Clusters, err = i.purgeEntities(&model.ClusterEntity{}, whereCond, result.Clusters); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (i *DefaultInventory) purgeEntities(entity db.DatabaseEntity, whereCond map[string]interface{}, counter int64) (int64, error) {
+	q, err := db.NewQuery(i.Conn, entity)
+	if err != nil {
+		return counter, err
+	}
+	deleted, err := q.Delete().Where(whereCond).Exec()
+	return counter + deleted, err
+}
+
+//deletionTime extracts the deletion timestamp from the name of a deleted cluster (see Delete())
+func deletionTime(cluster string) (time.Time, error) {
+	tokens := strings.SplitN(strings.TrimPrefix(cluster, deletedClusterPrefix), "_", 2)
+	if len(tokens) != 2 {
+		return time.Time{}, fmt.Errorf("name of deleted cluster doesn't contain a deletion timestamp")
+	}
+	unixTime, err := strconv.ParseInt(tokens[0], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(unixTime, 0), nil
+}
+
+//purgeConfigVersions keeps the latest configuration versions of each cluster and removes all older
+//configurations including their statuses, rollbacks and cluster versions which are no longer referenced
+func (i *DefaultInventory) purgeConfigVersions(keep int) (*PurgeResult, error) {
+	configEntity := &model.ClusterConfigurationEntity{}
+	configColHandler, err := db.NewColumnHandler(configEntity, i.Conn)
+	if err != nil {
+		return nil, err
+	}
+	configVersionColName, err := configColHandler.ColumnName("Version")
+	if err != nil {
+		return nil, err
+	}
+	configClusterColName, err := configColHandler.ColumnName("Cluster")
+	if err != nil {
+		return nil, err
+	}
+	configClusterVersionColName, err := configColHandler.ColumnName("ClusterVersion")
+	if err != nil {
+		return nil, err
+	}
+
+	/*
+		select version from inventory_cluster_configs as c1 where (
+			select count(*) from inventory_cluster_configs as c2 where c2.cluster = c1.cluster and c2.version > c1.version
+		) >= $1
+	*/
+	//table and column names cannot be passed as arguments and are rendered into the sub-query
+	outdatedConfigsSQL := fmt.Sprintf(`SELECT c1.%s FROM %s AS c1 WHERE (
+			SELECT COUNT(*) FROM %s AS c2 WHERE c2.%s = c1.%s AND c2.%s > c1.%s
+		) >= $1`,
+		configVersionColName, configEntity.Table(),
+		configEntity.Table(), configClusterColName, configClusterColName, configVersionColName, configVersionColName)
+
+	result := &PurgeResult{}
+
+	//statuses of outdated configurations
+	qStatus, err := db.NewQuery(i.Conn, &model.ClusterStatusEntity{})
+	if err != nil {
+		return nil, err
+	}
+	result.Statuses, err = qStatus.Delete().WhereIn("ConfigVersion", outdatedConfigsSQL, keep).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	//rollbacks from or to outdated configurations
+	qRollback, err := db.NewQuery(i.Conn, &model.ClusterConfigRollbackEntity{})
+	if err != nil {
+		return nil, err
+	}
+	result.Rollbacks, err = qRollback.Delete().
+		WhereCondition(db.Or(
+			db.InSubQuery("ConfigVersion", outdatedConfigsSQL, keep),
+			db.InSubQuery("SourceConfigVersion", outdatedConfigsSQL, keep))).
+		Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	//outdated configurations
+	qConfig, err := db.NewQuery(i.Conn, configEntity)
+	if err != nil {
+		return nil, err
+	}
+	result.Configurations, err = qConfig.Delete().WhereIn("Version", outdatedConfigsSQL, keep).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	//cluster versions which are neither referenced by a configuration nor the latest version of a cluster
+	clusterEntity := &model.ClusterEntity{}
+	clusterColHandler, err := db.NewColumnHandler(clusterEntity, i.Conn)
+	if err != nil {
+		return nil, err
+	}
+	clusterVersionColName, err := clusterColHandler.ColumnName("Version")
+	if err != nil {
+		return nil, err
+	}
+	clusterColName, err := clusterColHandler.ColumnName("Cluster")
+	if err != nil {
+		return nil, err
+	}
+	qCluster, err := db.NewQuery(i.Conn, clusterEntity)
+	if err != nil {
+		return nil, err
+	}
+	result.Clusters, err = qCluster.Delete().
+		WhereCondition(
+			db.Raw(fmt.Sprintf("%s NOT IN (SELECT %s FROM %s)",
+				clusterVersionColName, configClusterVersionColName, configEntity.Table())),
+			db.Raw(fmt.Sprintf("%s NOT IN (SELECT MAX(%s) FROM %s GROUP BY %s)",
+				clusterVersionColName, clusterVersionColName, clusterEntity.Table(), clusterColName))).
+		Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+//purgeStatuses removes all statuses older than the retention period except the latest status of a configuration
+func (i *DefaultInventory) purgeStatuses(retention time.Duration) (*PurgeResult, error) {
+	statusEntity := &model.ClusterStatusEntity{}
+	statusColHandler, err := db.NewColumnHandler(statusEntity, i.Conn)
+	if err != nil {
+		return nil, err
+	}
+	idColName, err := statusColHandler.ColumnName("ID")
+	if err != nil {
+		return nil, err
+	}
+	configVersionColName, err := statusColHandler.ColumnName("ConfigVersion")
+	if err != nil {
+		return nil, err
+	}
+	createdColName, err := statusColHandler.ColumnName("Created")
+	if err != nil {
+		return nil, err
+	}
+
+	timeAgoSQL, timeAgoArg, err := timeAgo(i.Conn.Type(), "$1", retention)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := db.NewQuery(i.Conn, statusEntity)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := q.Delete().
+		WhereCondition(
+			db.Raw(fmt.Sprintf("%s < %s", createdColName, timeAgoSQL), timeAgoArg),
+			db.Raw(fmt.Sprintf("%s NOT IN (SELECT MAX(%s) FROM %s GROUP BY %s)",
+				idColName, idColName, statusEntity.Table(), configVersionColName))).
+		Exec()
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Statuses: deleted}, nil
+}
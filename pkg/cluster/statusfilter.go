@@ -9,20 +9,28 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/model"
 )
 
+//statusSQLFilter returns an SQL condition for the status table: values are passed as arguments
+//(placeholders start at $1)
 type statusSQLFilter interface {
-	Filter(dbType db.Type, colHdr *db.ColumnHandler) (string, error)
+	Filter(dbType db.Type, colHdr *db.ColumnHandler) (string, []interface{}, error)
 }
 
 type statusFilter struct {
 	allowedStatuses []model.Status
 }
 
-func (sf *statusFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, error) {
+func (sf *statusFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, []interface{}, error) {
 	statusColName, err := statusColHdr.ColumnName("Status")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return fmt.Sprintf("%s IN ('%s')", statusColName, strings.Join(sf.stausesToStrings(), "','")), nil
+	plcHdrs := make([]string, 0, len(sf.allowedStatuses))
+	args := make([]interface{}, 0, len(sf.allowedStatuses))
+	for idx, status := range sf.stausesToStrings() {
+		plcHdrs = append(plcHdrs, fmt.Sprintf("$%d", idx+1))
+		args = append(args, status)
+	}
+	return fmt.Sprintf("%s IN (%s)", statusColName, strings.Join(plcHdrs, ", ")), args, nil
 }
 
 func (sf *statusFilter) stausesToStrings() []string {
@@ -37,23 +45,19 @@ type reconcileIntervalFilter struct {
 	reconcileInterval time.Duration
 }
 
-func (rif *reconcileIntervalFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, error) {
+func (rif *reconcileIntervalFilter) Filter(dbType db.Type, statusColHdr *db.ColumnHandler) (string, []interface{}, error) {
 	statusColName, err := statusColHdr.ColumnName("Status")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	createdColName, err := statusColHdr.ColumnName("Created")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	switch dbType {
-	case db.Postgres:
-		return fmt.Sprintf(`%s = '%s' AND %s <= NOW() - INTERVAL '%.0f SECOND'`,
-			statusColName, model.Ready, createdColName, rif.reconcileInterval.Seconds()), nil
-	case db.SQLite:
-		return fmt.Sprintf(`%s = '%s' AND %s <= DATETIME('now', '-%.0f SECONDS')`,
-			statusColName, model.Ready, createdColName, rif.reconcileInterval.Seconds()), nil
-	default:
-		return "", fmt.Errorf("database type '%s' is not supported by this filter", dbType)
+	timeAgoSQL, timeAgoArg, err := timeAgo(dbType, "$2", rif.reconcileInterval)
+	if err != nil {
+		return "", nil, err
 	}
+	return fmt.Sprintf("%s = $1 AND %s <= %s", statusColName, createdColName, timeAgoSQL),
+		[]interface{}{string(model.Ready), timeAgoArg}, nil
 }
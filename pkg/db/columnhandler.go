@@ -63,7 +63,7 @@ func NewColumnHandler(entity DatabaseEntity, conn Connection) (*ColumnHandler, e
 	}
 
 	//get marshalled values of entity fields
-	marshalledValues, err := entity.Marshaller().Marshal()
+	marshalledValues, err := entity.Marshaller().WithEncryptor(colHdlr.encryptor).Marshal()
 	if err != nil {
 		return colHdlr, newInvalidEntityError(fmt.Sprintf("failed to marshal values of entity '%s': %s", entity, err.Error()))
 	}
@@ -285,5 +285,5 @@ func (ch *ColumnHandler) Unmarshal(row DataRow, entity DatabaseEntity) error {
 		entityData[col.field.Name()] = col.value
 	}
 
-	return entity.Marshaller().Unmarshal(entityData)
+	return entity.Marshaller().WithEncryptor(ch.encryptor).Unmarshal(entityData)
 }
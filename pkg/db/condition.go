@@ -0,0 +1,219 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+//Condition is a criteria of a WHERE clause. Values of conditions are always passed as
+//query arguments and never rendered into the SQL statement.
+type Condition interface {
+	render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error)
+}
+
+//placeholders tracks the arguments of a query and returns the next free placeholder for each added argument
+type placeholders struct {
+	offset int //number of placeholders used by the query before the WHERE clause (e.g. in SET of an UPDATE)
+	args   []interface{}
+}
+
+func (p *placeholders) add(arg interface{}) string {
+	p.args = append(p.args, arg)
+	return fmt.Sprintf("$%d", p.offset+len(p.args))
+}
+
+//addSubQuery shifts the placeholders of a sub-query (starting at $1) behind the already used placeholders
+func (p *placeholders) addSubQuery(subQuery string, args ...interface{}) string {
+	shift := p.offset + len(p.args)
+	p.args = append(p.args, args...)
+	if shift == 0 {
+		return subQuery
+	}
+	return placeholderPattern.ReplaceAllStringFunc(subQuery, func(plcHdr string) string {
+		idx, err := strconv.Atoi(plcHdr[1:])
+		if err != nil {
+			return plcHdr
+		}
+		return fmt.Sprintf("$%d", idx+shift)
+	})
+}
+
+type comparison struct {
+	field    string
+	operator string
+	value    interface{}
+}
+
+func (c *comparison) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	col, err := colHdr.ColumnName(c.field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", col, c.operator, plcHdrs.add(c.value)), nil
+}
+
+//equality renders a compact comparison (used by the map based WHERE conditions of the query builder)
+type equality struct {
+	field string
+	value interface{}
+}
+
+func (e *equality) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	col, err := colHdr.ColumnName(e.field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s=%s", col, plcHdrs.add(e.value)), nil
+}
+
+//Eq matches entities whose field is equal to the value
+func Eq(field string, value interface{}) Condition {
+	return &comparison{field, "=", value}
+}
+
+//NotEq matches entities whose field is not equal to the value
+func NotEq(field string, value interface{}) Condition {
+	return &comparison{field, "<>", value}
+}
+
+//Lt matches entities whose field is less than the value
+func Lt(field string, value interface{}) Condition {
+	return &comparison{field, "<", value}
+}
+
+//Lte matches entities whose field is less than or equal to the value
+func Lte(field string, value interface{}) Condition {
+	return &comparison{field, "<=", value}
+}
+
+//Gt matches entities whose field is greater than the value
+func Gt(field string, value interface{}) Condition {
+	return &comparison{field, ">", value}
+}
+
+//Gte matches entities whose field is greater than or equal to the value
+func Gte(field string, value interface{}) Condition {
+	return &comparison{field, ">=", value}
+}
+
+//Like matches entities whose field matches the pattern ('%' and '_' are wildcards).
+//Be aware that SQLite compares ASCII characters case-insensitive whereas Postgres is case-sensitive.
+func Like(field string, pattern string) Condition {
+	return &comparison{field, "LIKE", pattern}
+}
+
+//likeEscaper escapes the wildcards of a LIKE pattern (backslash is used as escape character)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type prefixCondition struct {
+	field  string
+	prefix string
+}
+
+func (c *prefixCondition) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	col, err := colHdr.ColumnName(c.field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, col, plcHdrs.add(likeEscaper.Replace(c.prefix)+"%")), nil
+}
+
+//HasPrefix matches entities whose field starts with the prefix (wildcards in the prefix are matched literally).
+//Be aware that SQLite compares ASCII characters case-insensitive whereas Postgres is case-sensitive.
+func HasPrefix(field string, prefix string) Condition {
+	return &prefixCondition{field, prefix}
+}
+
+type inCondition struct {
+	field  string
+	values []interface{}
+}
+
+func (c *inCondition) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	col, err := colHdr.ColumnName(c.field)
+	if err != nil {
+		return "", err
+	}
+	if len(c.values) == 0 {
+		return "1=0", nil //an empty list matches nothing
+	}
+	plcHdrList := make([]string, 0, len(c.values))
+	for _, value := range c.values {
+		plcHdrList = append(plcHdrList, plcHdrs.add(value))
+	}
+	return fmt.Sprintf("%s IN (%s)", col, strings.Join(plcHdrList, ", ")), nil
+}
+
+//In matches entities whose field is equal to one of the values
+func In(field string, values ...interface{}) Condition {
+	return &inCondition{field, values}
+}
+
+type subQueryCondition struct {
+	field    string
+	subQuery string
+	args     []interface{}
+}
+
+func (c *subQueryCondition) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	col, err := colHdr.ColumnName(c.field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s IN (%s)", col, plcHdrs.addSubQuery(c.subQuery, c.args...)), nil
+}
+
+//InSubQuery matches entities whose field is part of the result of the sub-query.
+//Placeholders of the sub-query have to start at $1 and are shifted automatically.
+func InSubQuery(field, subQuery string, args ...interface{}) Condition {
+	return &subQueryCondition{field, subQuery, args}
+}
+
+type rawCondition struct {
+	sql  string
+	args []interface{}
+}
+
+func (c *rawCondition) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	return fmt.Sprintf("(%s)", plcHdrs.addSubQuery(c.sql, c.args...)), nil
+}
+
+//Raw adds a plain SQL condition which can't be expressed by the other conditions (e.g. database specific
+//date functions). Values have to be passed as arguments: placeholders have to start at $1 and are shifted automatically.
+func Raw(sql string, args ...interface{}) Condition {
+	return &rawCondition{sql, args}
+}
+
+type group struct {
+	operator   string
+	conditions []Condition
+}
+
+func (g *group) render(colHdr *ColumnHandler, plcHdrs *placeholders) (string, error) {
+	if len(g.conditions) == 0 {
+		return "1=1", nil
+	}
+	rendered := make([]string, 0, len(g.conditions))
+	for _, cond := range g.conditions {
+		sqlCond, err := cond.render(colHdr, plcHdrs)
+		if err != nil {
+			return "", err
+		}
+		rendered = append(rendered, sqlCond)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(rendered, fmt.Sprintf(" %s ", g.operator))), nil
+}
+
+//And matches entities which fulfill all conditions
+func And(conditions ...Condition) Condition {
+	return &group{"AND", conditions}
+}
+
+//Or matches entities which fulfill at least one of the conditions
+func Or(conditions ...Condition) Condition {
+	return &group{"OR", conditions}
+}
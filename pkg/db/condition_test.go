@@ -0,0 +1,85 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition(t *testing.T) {
+	colHdr, err := NewColumnHandler(&MockDbEntity{}, &MockConnection{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		cond     Condition
+		offset   int
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "Comparison",
+			cond:     Gt("Col3", 1),
+			wantSQL:  "col_3 > $1",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "Comparison with offset",
+			cond:     Lte("Col3", 1),
+			offset:   2,
+			wantSQL:  "col_3 <= $3",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "Empty IN",
+			cond:     In("Col1"),
+			wantSQL:  "1=0",
+			wantArgs: nil,
+		},
+		{
+			name:     "Nested groups",
+			cond:     And(Eq("Col1", "a"), Or(Like("Col1", "b%"), In("Col3", 1, 2))),
+			wantSQL:  "(col_1 = $1 AND (col_1 LIKE $2 OR col_3 IN ($3, $4)))",
+			wantArgs: []interface{}{"a", "b%", 1, 2},
+		},
+		{
+			name:     "Prefix with wildcards",
+			cond:     HasPrefix("Col1", `a_b%c\`),
+			wantSQL:  `col_1 LIKE $1 ESCAPE '\'`,
+			wantArgs: []interface{}{`a\_b\%c\\%`},
+		},
+		{
+			name:     "Empty group",
+			cond:     Or(),
+			wantSQL:  "1=1",
+			wantArgs: nil,
+		},
+		{
+			name:     "Raw condition",
+			cond:     And(Eq("Col1", "a"), Raw("col_3 = $1 OR col_3 = $2", 1, 2)),
+			wantSQL:  "(col_1 = $1 AND (col_3 = $2 OR col_3 = $3))",
+			wantArgs: []interface{}{"a", 1, 2},
+		},
+		{
+			name:     "Sub-query",
+			cond:     InSubQuery("Col1", "SELECT col FROM table WHERE x=$1", "a"),
+			offset:   1,
+			wantSQL:  "col_1 IN (SELECT col FROM table WHERE x=$2)",
+			wantArgs: []interface{}{"a"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plcHdrs := &placeholders{offset: tc.offset}
+			sql, err := tc.cond.render(colHdr, plcHdrs)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSQL, sql)
+			require.Equal(t, tc.wantArgs, plcHdrs.args)
+		})
+	}
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := Eq("DoesNotExist", 1).render(colHdr, &placeholders{})
+		require.Error(t, err)
+	})
+}
@@ -9,17 +9,21 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
+	"regexp"
 	"strings"
 )
 
 const keyIDLength = 15
 
 type Encryptor struct {
-	keyID [16]byte
-	aead  cipher.AEAD
+	keyID        [16]byte
+	aead         cipher.AEAD
+	previousKeys []*Encryptor //key-ring of previous keys: only used for decryption
 }
 
-func NewEncryptor(key string) (*Encryptor, error) {
+//NewEncryptor creates an encryptor which encrypts data with the given key. Previous keys are
+//used only for decrypting data which was encrypted before the key was rotated.
+func NewEncryptor(key string, previousKeys ...string) (*Encryptor, error) {
 	if len(key) == 0 {
 		return nil, fmt.Errorf("cannot create new encryptor instance because encryption key was an empty string")
 	}
@@ -29,10 +33,23 @@ func NewEncryptor(key string) (*Encryptor, error) {
 		return nil, err
 	}
 
-	return &Encryptor{
+	encryptor := &Encryptor{
 		aead:  aead,
 		keyID: md5.Sum([]byte(key)), //nolint: gosec //using MD5 just for generating a checksum of the key
-	}, nil
+	}
+
+	for _, previousKey := range previousKeys {
+		previousEncryptor, err := NewEncryptor(previousKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to add previous encryption key to key-ring")
+		}
+		if previousEncryptor.KeyID() == encryptor.KeyID() {
+			continue //previous key is equal to current key
+		}
+		encryptor.previousKeys = append(encryptor.previousKeys, previousEncryptor)
+	}
+
+	return encryptor, nil
 }
 
 //NewEncryptionKey generates a random 32 byte key for AES-256
@@ -69,7 +86,12 @@ func (e *Encryptor) Encrypt(data string) (string, error) {
 }
 
 func (e *Encryptor) Decrypt(encData string) (string, error) {
-	if !e.Decryptable(encData) {
+	if !strings.HasPrefix(encData, e.KeyID()) {
+		for _, previousKey := range e.previousKeys {
+			if previousKey.Decryptable(encData) {
+				return previousKey.Decrypt(encData)
+			}
+		}
 		return "", fmt.Errorf("data cannot be decrypted because encryption key does not match")
 	}
 
@@ -79,6 +101,9 @@ func (e *Encryptor) Decrypt(encData string) (string, error) {
 	}
 
 	nonceSize := e.aead.NonceSize()
+	if len(enc) < nonceSize {
+		return "", fmt.Errorf("encrypted data is too short")
+	}
 	nonce, cipherText := enc[:nonceSize], enc[nonceSize:]
 
 	data, err := e.aead.Open(nil, nonce, cipherText, nil)
@@ -90,6 +115,50 @@ func (e *Encryptor) Decrypt(encData string) (string, error) {
 }
 
 //Decryptable verifies whether the encrypted data can be decrypted by this Encryptor instance
+//(either by the current key or by one of the previous keys)
 func (e *Encryptor) Decryptable(encData string) bool {
-	return strings.HasPrefix(encData, e.KeyID()) //KeyID prefix of encrypted data has to match with current KeyID
+	if strings.HasPrefix(encData, e.KeyID()) { //KeyID prefix of encrypted data has to match with current KeyID
+		return true
+	}
+	return e.Outdated(encData)
+}
+
+//Outdated verifies whether the encrypted data was encrypted by one of the previous keys
+func (e *Encryptor) Outdated(encData string) bool {
+	for _, previousKey := range e.previousKeys {
+		if previousKey.Decryptable(encData) {
+			return true
+		}
+	}
+	return false
+}
+
+//ReEncrypt encrypts all data in the text which was encrypted by one of the previous keys with the current key.
+//Encrypted data can also be embedded in a text (e.g. in a JSON string). Returns true if the text was changed.
+func (e *Encryptor) ReEncrypt(text string) (string, bool, error) {
+	var err error
+	changed := false
+	for _, previousKey := range e.previousKeys {
+		encDataRegex := regexp.MustCompile(fmt.Sprintf("%s[0-9a-f]+", previousKey.KeyID()))
+		text = encDataRegex.ReplaceAllStringFunc(text, func(encData string) string {
+			if err != nil {
+				return encData
+			}
+			var data, reEncData string
+			data, err = previousKey.Decrypt(encData)
+			if err != nil {
+				return encData
+			}
+			reEncData, err = e.Encrypt(data)
+			if err != nil {
+				return encData
+			}
+			changed = true
+			return reEncData
+		})
+		if err != nil {
+			return "", false, err
+		}
+	}
+	return text, changed, nil
 }
@@ -71,6 +71,59 @@ func TestEncryptor(t *testing.T) {
 		require.Equal(t, decData1, decData2)
 	})
 
+	t.Run("Decrypt with key-ring", func(t *testing.T) {
+		oldKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		oldEnc, err := NewEncryptor(oldKey)
+		require.NoError(t, err)
+		newKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		newEnc, err := NewEncryptor(newKey, oldKey)
+		require.NoError(t, err)
+
+		oldEncData, err := oldEnc.Encrypt(data)
+		require.NoError(t, err)
+		require.True(t, newEnc.Decryptable(oldEncData))
+		require.True(t, newEnc.Outdated(oldEncData))
+		decData, err := newEnc.Decrypt(oldEncData)
+		require.NoError(t, err)
+		require.Equal(t, data, decData)
+
+		//new data is encrypted with current key
+		newEncData, err := newEnc.Encrypt(data)
+		require.NoError(t, err)
+		require.False(t, newEnc.Outdated(newEncData))
+		require.False(t, oldEnc.Decryptable(newEncData))
+	})
+
+	t.Run("Re-encrypt with current key", func(t *testing.T) {
+		oldKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		oldEnc, err := NewEncryptor(oldKey)
+		require.NoError(t, err)
+		newEnc, err := NewEncryptor(oldKey) //no previous keys
+		require.NoError(t, err)
+
+		oldEncData, err := oldEnc.Encrypt(data)
+		require.NoError(t, err)
+		text, changed, err := newEnc.ReEncrypt(oldEncData)
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, oldEncData, text)
+
+		newKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		newEnc, err = NewEncryptor(newKey, oldKey)
+		require.NoError(t, err)
+		text, changed, err = newEnc.ReEncrypt("prefix " + oldEncData + " suffix")
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.NotContains(t, text, oldEncData)
+		reEncData := text[len("prefix ") : len(text)-len(" suffix")]
+		decData, err := newEnc.Decrypt(reEncData)
+		require.NoError(t, err)
+		require.Equal(t, data, decData)
+	})
 }
 
 func newEncryptor(t *testing.T) *Encryptor {
@@ -20,11 +20,17 @@ func NewConnectionFactory(configFile string, debug bool) (ConnectionFactory, err
 		return nil, err
 	}
 
+	previousEncKeys, err := readPreviousEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+
 	dbToUse := viper.GetString("db.driver")
 
 	switch dbToUse {
 	case "postgres":
 		connFact := createPostgresConnectionFactory(encKey, debug)
+		connFact.PreviousEncryptionKeys = previousEncKeys
 		return connFact, connFact.Init()
 
 	case "sqlite":
@@ -32,6 +38,15 @@ func NewConnectionFactory(configFile string, debug bool) (ConnectionFactory, err
 		if err != nil {
 			return nil, err
 		}
+		connFact.PreviousEncryptionKeys = previousEncKeys
+		return connFact, connFact.Init()
+
+	case "memory":
+		connFact := &MemoryConnectionFactory{
+			Debug:                  debug,
+			EncryptionKey:          encKey,
+			PreviousEncryptionKeys: previousEncKeys,
+		}
 		return connFact, connFact.Init()
 
 	default:
@@ -64,6 +79,26 @@ func readEncryptionKey() (string, error) {
 	return string(encKeyBytes), nil
 }
 
+//readPreviousEncryptionKeys reads the key-ring of previously used encryption keys
+func readPreviousEncryptionKeys() ([]string, error) {
+	var result []string
+	for _, encKeyFile := range viper.GetStringSlice("db.encryption.previousKeyFiles") {
+		if !filepath.IsAbs(encKeyFile) {
+			//define absolute path relative to config-file directory
+			encKeyFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), encKeyFile)
+		}
+		if !file.Exists(encKeyFile) {
+			return nil, fmt.Errorf("previous encryption key file '%s' not found", encKeyFile)
+		}
+		encKeyBytes, err := ioutil.ReadFile(encKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, string(encKeyBytes))
+	}
+	return result, nil
+}
+
 func createSqliteConnectionFactory(encKey string, debug bool) (*SqliteConnectionFactory, error) {
 	dbFile := viper.GetString("db.sqlite.file")
 	//ensure directory structure of db-file exists
@@ -73,16 +108,13 @@ func createSqliteConnectionFactory(encKey string, debug bool) (*SqliteConnection
 			return nil, err
 		}
 	}
-	connFact := &SqliteConnectionFactory{
+	return &SqliteConnectionFactory{
 		File:          dbFile,
 		Debug:         debug,
 		Reset:         viper.GetBool("db.sqlite.resetDatabase"),
+		Migrate:       viper.GetBool("db.sqlite.deploySchema"),
 		EncryptionKey: encKey,
-	}
-	if viper.GetBool("db.sqlite.deploySchema") {
-		connFact.SchemaFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), "db", "sqlite", "reconciler.sql")
-	}
-	return connFact, nil
+	}, nil
 }
 
 func createPostgresConnectionFactory(encKey string, debug bool) *PostgresConnectionFactory {
@@ -113,13 +145,24 @@ func createPostgresConnectionFactory(encKey string, debug bool) *PostgresConnect
 	}
 
 	return &PostgresConnectionFactory{
-		Host:          host,
-		Port:          port,
-		Database:      database,
-		User:          user,
-		Password:      password,
-		SslMode:       sslMode,
-		EncryptionKey: encKey,
-		Debug:         debug,
+		Host:             host,
+		Port:             port,
+		Database:         database,
+		User:             user,
+		Password:         password,
+		SslMode:          sslMode,
+		EncryptionKey:    encKey,
+		Debug:            debug,
+		Migrate:          viper.GetBool("db.postgres.migrate"),
+		MaxOpenConns:     viper.GetInt("db.postgres.pool.maxOpenConnections"),
+		MaxIdleConns:     viper.GetInt("db.postgres.pool.maxIdleConnections"),
+		ConnMaxLifetime:  viper.GetDuration("db.postgres.pool.connectionMaxLifetime"),
+		ConnMaxIdleTime:  viper.GetDuration("db.postgres.pool.connectionMaxIdleTime"),
+		StatementTimeout: viper.GetDuration("db.postgres.statementTimeout"),
+		Retry: &RetryPolicy{
+			MaxAttempts:    viper.GetInt("db.postgres.retry.maxAttempts"),
+			InitialBackoff: viper.GetDuration("db.postgres.retry.initialBackoff"),
+			MaxBackoff:     viper.GetDuration("db.postgres.retry.maxBackoff"),
+		},
 	}
 }
@@ -15,7 +15,7 @@ type Connection interface {
 	QueryRow(query string, args ...interface{}) DataRow
 	Query(query string, args ...interface{}) (DataRows, error)
 	Exec(query string, args ...interface{}) (sql.Result, error)
-	Begin() (*sql.Tx, error)
+	Begin() (*TxConnection, error)
 	Close() error
 	Type() Type
 }
@@ -41,4 +41,6 @@ type DataRow interface {
 type DataRows interface {
 	Scan(dest ...interface{}) error
 	Next() bool
+	Close() error
+	Err() error
 }
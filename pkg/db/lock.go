@@ -0,0 +1,23 @@
+package db
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+//Lock acquires an exclusive lock for the given name which is released when the transaction ends.
+//SQLite serializes write transactions and doesn't require an explicit lock.
+func Lock(tx Connection, name string) error {
+	if _, ok := tx.(*TxConnection); !ok {
+		return fmt.Errorf("lock '%s' can only be acquired within a transaction", name)
+	}
+	if tx.Type() != Postgres {
+		return nil
+	}
+	hash := fnv.New64a()
+	if _, err := hash.Write([]byte(name)); err != nil {
+		return err
+	}
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", int64(hash.Sum64()))
+	return err
+}
@@ -8,10 +8,14 @@ import (
 	"github.com/fatih/structs"
 )
 
+//EncryptionFct is a marshal/unmarshal function which has access to the Encryptor of the DB connection
+type EncryptionFct func(value interface{}, encryptor *Encryptor) (interface{}, error)
+
 type EntityMarshaller struct {
 	structs       *structs.Struct
 	marshalFcts   map[string]func(value interface{}) (interface{}, error)
 	unmarshalFcts map[string]func(value interface{}) (interface{}, error)
+	encryptor     *Encryptor
 }
 
 func NewEntityMarshaller(entity interface{}) *EntityMarshaller {
@@ -22,6 +26,12 @@ func NewEntityMarshaller(entity interface{}) *EntityMarshaller {
 	}
 }
 
+//WithEncryptor defines the Encryptor which is passed to the encryption marshal/unmarshal functions
+func (es *EntityMarshaller) WithEncryptor(encryptor *Encryptor) *EntityMarshaller {
+	es.encryptor = encryptor
+	return es
+}
+
 func (es *EntityMarshaller) AddMarshaller(field string, fct func(value interface{}) (interface{}, error)) {
 	es.ensureFieldExist(field)
 	es.marshalFcts[field] = fct
@@ -32,6 +42,26 @@ func (es *EntityMarshaller) AddUnmarshaller(field string, fct func(value interfa
 	es.unmarshalFcts[field] = fct
 }
 
+//AddEncryptionMarshaller adds a marshal function which can encrypt (parts of) the field value
+func (es *EntityMarshaller) AddEncryptionMarshaller(field string, fct EncryptionFct) {
+	es.AddMarshaller(field, es.withEncryption(field, fct))
+}
+
+//AddEncryptionUnmarshaller adds an unmarshal function which can decrypt (parts of) the field value
+func (es *EntityMarshaller) AddEncryptionUnmarshaller(field string, fct EncryptionFct) {
+	es.AddUnmarshaller(field, es.withEncryption(field, fct))
+}
+
+func (es *EntityMarshaller) withEncryption(field string, fct EncryptionFct) func(value interface{}) (interface{}, error) {
+	return func(value interface{}) (interface{}, error) {
+		if es.encryptor == nil {
+			return nil, fmt.Errorf("Failure in Marshaller: no encryptor defined for encrypted field '%s' of entity '%s'",
+				field, es.structs.Name())
+		}
+		return fct(value, es.encryptor)
+	}
+}
+
 func (es *EntityMarshaller) ensureFieldExist(field string) {
 	if _, ok := es.structs.FieldOk(field); !ok {
 		panic(fmt.Sprintf("Failure in Marshaller: the entity '%s' has not field '%s'", es.structs.Name(), field))
@@ -68,4 +68,28 @@ func TestEntityMarshaller(t *testing.T) {
 		err := marshaller.Unmarshal(map[string]interface{}{"Col1": "bar", "Col2": 123, "Col3": "abc"})
 		require.Error(t, err)
 	})
+
+	t.Run("Test encryption marshalling", func(t *testing.T) {
+		encryptor, err := NewEncryptor(MockEncryptorKey)
+		require.NoError(t, err)
+
+		marshaller := NewEntityMarshaller(mock)
+		marshaller.AddEncryptionMarshaller("Col1", func(value interface{}, encryptor *Encryptor) (interface{}, error) {
+			return encryptor.Encrypt(value.(string))
+		})
+		_, err = marshaller.Marshal()
+		require.Error(t, err) //no encryptor defined
+
+		data, err := marshaller.WithEncryptor(encryptor).Marshal()
+		require.NoError(t, err)
+		require.True(t, encryptor.Decryptable(data["Col1"].(string)))
+
+		unmarshalledMock := &MockDbEntity{}
+		unmarshaller := NewEntityMarshaller(unmarshalledMock).WithEncryptor(encryptor)
+		unmarshaller.AddEncryptionUnmarshaller("Col1", func(value interface{}, encryptor *Encryptor) (interface{}, error) {
+			return encryptor.Decrypt(value.(string))
+		})
+		require.NoError(t, unmarshaller.Unmarshal(data))
+		require.Equal(t, mock, unmarshalledMock)
+	})
 }
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	//add SQlite driver:
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//MemoryConnectionFactory creates connections to an in-memory SQLite database. The database schema is
+//deployed during the initialization and all connections of a factory share the same database which
+//exists until the process ends. It is intended for unit tests and local runs.
+type MemoryConnectionFactory struct {
+	Debug         bool
+	EncryptionKey string
+	//PreviousEncryptionKeys are used to decrypt data which was encrypted before the encryption key was rotated
+	PreviousEncryptionKeys []string
+	db                     *sql.DB
+}
+
+func (mcf *MemoryConnectionFactory) Init() error {
+	if mcf.db != nil {
+		return nil //already initialized: a new database would drop the existing data
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return err
+	}
+	//each connection to ':memory:' opens a separate database: use exactly one connection
+	//and keep it open to share the database between all callers
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	if err := db.Ping(); err != nil {
+		return err
+	}
+	mcf.db = db
+	return migrate(mcf, mcf.Debug)
+}
+
+func (mcf *MemoryConnectionFactory) NewConnection() (Connection, error) {
+	if mcf.db == nil {
+		return nil, fmt.Errorf("in-memory database is not initialized: call Init() before creating connections")
+	}
+	conn, err := newSqliteConnection(mcf.db, mcf.EncryptionKey, mcf.PreviousEncryptionKeys, mcf.Debug)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryConnection{conn}, nil
+}
+
+//memoryConnection doesn't close the shared in-memory database: its content would get lost
+type memoryConnection struct {
+	*SqliteConnection
+}
+
+func (mc *memoryConnection) Begin() (*TxConnection, error) {
+	tx, err := mc.SqliteConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	tx.conn = mc
+	return tx, nil
+}
+
+func (mc *memoryConnection) Close() error {
+	mc.logger.Debug("Sqlite3 Close() skipped for in-memory database")
+	return nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryConnectionFactory(t *testing.T) {
+	encKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+
+	t.Run("Connection requires initialization", func(t *testing.T) {
+		_, err := (&MemoryConnectionFactory{EncryptionKey: encKey}).NewConnection()
+		require.Error(t, err)
+	})
+
+	t.Run("Connections share the database", func(t *testing.T) {
+		connFact := &MemoryConnectionFactory{EncryptionKey: encKey}
+		require.NoError(t, connFact.Init())
+
+		conn1, err := connFact.NewConnection()
+		require.NoError(t, err)
+		require.Equal(t, SQLite, conn1.Type())
+
+		//schema is deployed during the initialization
+		var cnt int
+		require.NoError(t, conn1.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&cnt))
+		require.NotZero(t, cnt)
+
+		_, err = conn1.Exec("CREATE TABLE key_rotation (data text NOT NULL)")
+		require.NoError(t, err)
+		q, err := NewQuery(conn1, &rotationEntity{Data: "memory"})
+		require.NoError(t, err)
+		require.NoError(t, q.Insert().Exec())
+		require.NoError(t, conn1.Close()) //closing a connection keeps the data
+
+		conn2, err := connFact.NewConnection()
+		require.NoError(t, err)
+		require.NoError(t, conn2.QueryRow("SELECT COUNT(*) FROM key_rotation").Scan(&cnt))
+		require.Equal(t, 1, cnt)
+
+		err = Transaction(conn2, func(tx Connection) error {
+			_, err := tx.Exec("DELETE FROM key_rotation")
+			return err
+		}, nil)
+		require.NoError(t, err)
+		require.NoError(t, conn2.QueryRow("SELECT COUNT(*) FROM key_rotation").Scan(&cnt))
+		require.Equal(t, 0, cnt)
+	})
+
+	t.Run("Factories use separate databases", func(t *testing.T) {
+		connFact := &MemoryConnectionFactory{EncryptionKey: encKey}
+		require.NoError(t, connFact.Init())
+		conn, err := connFact.NewConnection()
+		require.NoError(t, err)
+		var cnt int
+		err = conn.QueryRow("SELECT COUNT(*) FROM key_rotation").Scan(&cnt)
+		require.Error(t, err) //table was only created in the database of the other factory
+	})
+
+	t.Run("Repeated initialization keeps the database", func(t *testing.T) {
+		connFact := &MemoryConnectionFactory{EncryptionKey: encKey}
+		require.NoError(t, connFact.Init())
+		conn, err := connFact.NewConnection()
+		require.NoError(t, err)
+		_, err = conn.Exec("CREATE TABLE key_rotation (data text NOT NULL)")
+		require.NoError(t, err)
+		_, err = conn.Exec("INSERT INTO key_rotation (data) VALUES ($1)", "kept")
+		require.NoError(t, err)
+
+		require.NoError(t, connFact.Init())
+		conn, err = connFact.NewConnection()
+		require.NoError(t, err)
+		var cnt int
+		require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM key_rotation").Scan(&cnt))
+		require.Equal(t, 1, cnt)
+	})
+}
@@ -0,0 +1,461 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	schemaVersionTable       = "schema_version"
+	legacySchemaVersionTable = "schema_migrations" //version table of golang-migrate which was used by previous releases
+	migrationLockID          = 7310455             //identifier of the Postgres advisory lock which serializes concurrent migrations
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+//Migration is a versioned change of the database schema
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+//Checksum identifies the content of the migration (used to detect modified migrations which were already applied)
+func (m *Migration) Checksum() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(m.Up)))
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+//MigrationStatus indicates whether a migration was applied on the database
+type MigrationStatus struct {
+	*Migration
+	Applied bool
+}
+
+//Migrations returns the embedded migrations of a database type ordered by their version
+func Migrations(dbType Type) ([]*Migration, error) {
+	dir := path.Join("migrations", string(dbType))
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations found for database type '%s': %s", dbType, err)
+	}
+
+	migrations := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file '%s' doesn't match the naming convention 'VERSION_NAME.(up|down).sql'", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		sqlStmts, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrations[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration files of version %d have different names ('%s' and '%s')",
+				version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(sqlStmts)
+		} else {
+			migration.Down = string(sqlStmts)
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration '%s' requires an up and a down file", migration)
+		}
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+//Migrator applies the embedded migrations on a database and tracks the applied versions in the schema_version table
+type Migrator struct {
+	conn       Connection
+	migrations []*Migration
+	logger     *zap.SugaredLogger
+}
+
+func NewMigrator(conn Connection, logger *zap.SugaredLogger) (*Migrator, error) {
+	migrations, err := Migrations(conn.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		conn:       conn,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+//Version returns the version of the latest applied migration (0 if no migration was applied)
+func (m *Migrator) Version() (int64, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	var version int64
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+//Status returns all known migrations and whether they were applied
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.Verify()
+	if err != nil {
+		return nil, err
+	}
+	var result []*MigrationStatus
+	for _, migration := range m.migrations {
+		_, ok := applied[migration.Version]
+		result = append(result, &MigrationStatus{Migration: migration, Applied: ok})
+	}
+	return result, nil
+}
+
+//Verify compares the checksums of the applied migrations with the embedded migrations and
+//returns the checksums of the applied migrations by their version
+func (m *Migrator) Verify() (map[int64]string, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	for version, checksum := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("database schema contains migration version %d which is unknown "+
+				"(database was probably migrated by a newer release)", version)
+		}
+		if migration.Checksum() != checksum {
+			return nil, &ChecksumError{Migration: migration.String()}
+		}
+	}
+	return applied, nil
+}
+
+//Up applies all pending migrations and returns the number of applied migrations
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.Verify()
+	if err != nil {
+		return 0, err
+	}
+	var cnt int
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		executed, err := m.execute(migration, true)
+		if err != nil {
+			return cnt, err
+		}
+		if executed {
+			cnt++
+		}
+	}
+	return cnt, nil
+}
+
+//Down reverts the given number of applied migrations (starting with the latest) and returns the number of
+//reverted migrations
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.Verify()
+	if err != nil {
+		return 0, err
+	}
+	var cnt int
+	for idx := len(m.migrations) - 1; idx >= 0 && cnt < steps; idx-- {
+		migration := m.migrations[idx]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		executed, err := m.execute(migration, false)
+		if err != nil {
+			return cnt, err
+		}
+		if executed {
+			cnt++
+		}
+	}
+	return cnt, nil
+}
+
+//execute runs a migration within a transaction. It returns false if the migration was
+//already applied (or reverted) by a concurrently running migrator.
+func (m *Migrator) execute(migration *Migration, up bool) (bool, error) {
+	tx, err := m.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	executed, err := m.executeTx(tx, migration, up)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = errors.Wrap(err, fmt.Sprintf("Rollback of migration failed: %s", rollbackErr))
+		}
+		return false, err
+	}
+	return executed, tx.Commit()
+}
+
+func (m *Migrator) executeTx(tx *TxConnection, migration *Migration, up bool) (bool, error) {
+	if m.conn.Type() == Postgres {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return false, err
+		}
+	}
+
+	var cnt int
+	err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE version=$1", schemaVersionTable), migration.Version).
+		Scan(&cnt)
+	if err != nil {
+		return false, err
+	}
+	if (cnt > 0) == up {
+		return false, nil
+	}
+
+	if up {
+		m.debugf("Applying migration '%s'", migration)
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return false, errors.Wrap(err, fmt.Sprintf("failed to apply migration '%s'", migration))
+		}
+		_, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3)", schemaVersionTable),
+			migration.Version, migration.Name, migration.Checksum())
+		return err == nil, err
+	}
+
+	m.debugf("Reverting migration '%s'", migration)
+	if _, err := tx.Exec(migration.Down); err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("failed to revert migration '%s'", migration))
+	}
+	_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE version=$1", schemaVersionTable), migration.Version)
+	return err == nil, err
+}
+
+//applied returns the checksums of the applied migrations by their version
+func (m *Migrator) applied() (map[int64]string, error) {
+	_, err := m.conn.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	"version" bigint PRIMARY KEY,
+	"name" varchar(255) NOT NULL,
+	"checksum" varchar(64) NOT NULL,
+	"applied" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`, schemaVersionTable))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := m.appliedVersions()
+	if err != nil || len(result) > 0 {
+		return result, err
+	}
+	adopted, err := m.adoptLegacyVersion()
+	if err != nil || !adopted {
+		return result, err
+	}
+	return m.appliedVersions()
+}
+
+func (m *Migrator) appliedVersions() (map[int64]string, error) {
+	rows, err := m.conn.Query(fmt.Sprintf("SELECT version, checksum FROM %s", schemaVersionTable))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			m.warnf("Failed to close result set of schema versions: %s", err)
+		}
+	}()
+	result := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		result[version] = checksum
+	}
+	return result, rows.Err()
+}
+
+//adoptLegacyVersion marks the migrations up to the version of a database schema which was migrated by
+//golang-migrate as applied (their DDL exists already). It returns false if no legacy version was found.
+func (m *Migrator) adoptLegacyVersion() (bool, error) {
+	legacyVersion, err := m.legacyVersion()
+	if err != nil || legacyVersion == 0 {
+		return false, err
+	}
+
+	tx, err := m.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	if err := m.adoptLegacyVersionTx(tx, legacyVersion); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = errors.Wrap(err, fmt.Sprintf("Rollback of legacy schema version adoption failed: %s", rollbackErr))
+		}
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (m *Migrator) adoptLegacyVersionTx(tx *TxConnection, legacyVersion int64) error {
+	if m.conn.Type() == Postgres {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return err
+		}
+	}
+
+	//a concurrently running migrator could have adopted the legacy version already
+	var cnt int
+	if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", schemaVersionTable)).Scan(&cnt); err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return nil
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > legacyVersion {
+			break
+		}
+		m.debugf("Adopting migration '%s' applied by golang-migrate", migration)
+		_, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3)", schemaVersionTable),
+			migration.Version, migration.Name, migration.Checksum())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//legacyVersion returns the schema version stored by golang-migrate (0 if the database wasn't migrated by it)
+func (m *Migrator) legacyVersion() (int64, error) {
+	tableQuery := "SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=$1"
+	if m.conn.Type() == Postgres {
+		tableQuery = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema=current_schema() AND table_name=$1"
+	}
+	var cnt int
+	if err := m.conn.QueryRow(tableQuery, legacySchemaVersionTable).Scan(&cnt); err != nil {
+		return 0, err
+	}
+	if cnt == 0 {
+		return 0, nil
+	}
+
+	var version int64
+	var dirty bool
+	err := m.conn.QueryRow(fmt.Sprintf("SELECT version, dirty FROM %s", legacySchemaVersionTable)).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database schema was left dirty by golang-migrate at version %d: "+
+			"repair the schema and reset the dirty flag in table '%s' first", version, legacySchemaVersionTable)
+	}
+	return version, nil
+}
+
+func (m *Migrator) debugf(msg string, args ...interface{}) {
+	if m.logger != nil {
+		m.logger.Debugf(msg, args...)
+	}
+}
+
+func (m *Migrator) warnf(msg string, args ...interface{}) {
+	if m.logger != nil {
+		m.logger.Warnf(msg, args...)
+	}
+}
+
+//Migrate applies all pending migrations on the database of the connection
+func Migrate(conn Connection, logger *zap.SugaredLogger) (int, error) {
+	migrator, err := NewMigrator(conn, logger)
+	if err != nil {
+		return 0, err
+	}
+	return migrator.Up()
+}
+
+//migrate applies all pending migrations on the database of a connection factory
+func migrate(connFact ConnectionFactory, debug bool) error {
+	logger, err := log.NewLogger(debug)
+	if err != nil {
+		return err
+	}
+	conn, err := connFact.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Warnf("Failed to close database connection after schema migration: %s", err)
+		}
+	}()
+	applied, err := Migrate(conn, logger)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		logger.Infof("Database schema migrated: %d migrations applied", applied)
+	}
+	return nil
+}
+
+//ChecksumError indicates that an already applied migration was modified afterwards
+type ChecksumError struct {
+	Migration string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum of applied migration '%s' doesn't match: migrations must not be modified after they were applied",
+		e.Migration)
+}
+
+func IsChecksumError(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(&ChecksumError{})
+}
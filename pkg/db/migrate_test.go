@@ -0,0 +1,164 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("Postgres and SQLite migrations are in lockstep", func(t *testing.T) {
+		pgMigrations, err := Migrations(Postgres)
+		require.NoError(t, err)
+		require.NotEmpty(t, pgMigrations)
+		sqliteMigrations, err := Migrations(SQLite)
+		require.NoError(t, err)
+
+		require.Len(t, sqliteMigrations, len(pgMigrations))
+		for idx, pgMigration := range pgMigrations {
+			require.Equal(t, pgMigration.String(), sqliteMigrations[idx].String())
+		}
+	})
+
+	t.Run("Unsupported database type", func(t *testing.T) {
+		_, err := Migrations(Mock)
+		require.Error(t, err)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	encKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+	conn, err := (&SqliteConnectionFactory{
+		File:          filepath.Join(t.TempDir(), "migration.db"),
+		EncryptionKey: encKey,
+	}).NewConnection()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+
+	migrator, err := NewMigrator(conn, nil)
+	require.NoError(t, err)
+	migrations, err := Migrations(SQLite)
+	require.NoError(t, err)
+	latest := migrations[len(migrations)-1].Version
+
+	t.Run("Migrate up", func(t *testing.T) {
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), applied)
+
+		version, err := migrator.Version()
+		require.NoError(t, err)
+		require.Equal(t, latest, version)
+
+		_, err = conn.Exec("SELECT COUNT(*) FROM config_keys")
+		require.NoError(t, err)
+
+		//migrating again has no effect
+		applied, err = migrator.Up()
+		require.NoError(t, err)
+		require.Equal(t, 0, applied)
+
+		status, err := migrator.Status()
+		require.NoError(t, err)
+		for _, migration := range status {
+			require.True(t, migration.Applied)
+		}
+	})
+
+	t.Run("Migrate down", func(t *testing.T) {
+		reverted, err := migrator.Down(len(migrations))
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), reverted)
+
+		version, err := migrator.Version()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), version)
+
+		_, err = conn.Exec("SELECT COUNT(*) FROM config_keys")
+		require.Error(t, err)
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), applied)
+	})
+
+	t.Run("Detect modified migration", func(t *testing.T) {
+		_, err := conn.Exec("UPDATE schema_version SET checksum=$1 WHERE version=$2", "modified", latest)
+		require.NoError(t, err)
+
+		_, err = migrator.Up()
+		require.Error(t, err)
+		require.True(t, IsChecksumError(err))
+	})
+
+	t.Run("Detect unknown migration", func(t *testing.T) {
+		_, err := conn.Exec("DELETE FROM schema_version")
+		require.NoError(t, err)
+		_, err = conn.Exec("INSERT INTO schema_version (version, name, checksum) VALUES ($1, $2, $3)",
+			latest+1, "unknown", "checksum")
+		require.NoError(t, err)
+
+		_, err = migrator.Up()
+		require.Error(t, err)
+		require.False(t, IsChecksumError(err))
+	})
+}
+
+func TestMigratorAdoptsLegacyVersion(t *testing.T) {
+	migrations, err := Migrations(SQLite)
+	require.NoError(t, err)
+
+	newLegacyDatabase := func(t *testing.T, dirty bool) Connection {
+		encKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		conn, err := (&SqliteConnectionFactory{
+			File:          filepath.Join(t.TempDir(), "legacy.db"),
+			EncryptionKey: encKey,
+		}).NewConnection()
+		require.NoError(t, err)
+
+		//schema as created by golang-migrate with the first migration
+		_, err = conn.Exec(migrations[0].Up)
+		require.NoError(t, err)
+		_, err = conn.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+		require.NoError(t, err)
+		_, err = conn.Exec("INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", migrations[0].Version, dirty)
+		require.NoError(t, err)
+		return conn
+	}
+
+	t.Run("Apply only migrations newer than the legacy version", func(t *testing.T) {
+		conn := newLegacyDatabase(t, false)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		migrator, err := NewMigrator(conn, nil)
+		require.NoError(t, err)
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		require.Equal(t, len(migrations)-1, applied)
+
+		//columns and tables of later migrations exist
+		_, err = conn.Exec(`SELECT "urgent" FROM inventory_cluster_configs`)
+		require.NoError(t, err)
+		_, err = conn.Exec("SELECT COUNT(*) FROM config_triggers")
+		require.NoError(t, err)
+	})
+
+	t.Run("Reject dirty legacy version", func(t *testing.T) {
+		conn := newLegacyDatabase(t, true)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		migrator, err := NewMigrator(conn, nil)
+		require.NoError(t, err)
+
+		_, err = migrator.Up()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,17 @@
+--AUDIT LOG
+
+--DDL for audit entries of mutating operations:
+CREATE TABLE IF NOT EXISTS audit_log (
+	"id" SERIAL UNIQUE,
+	"actor" varchar(255) NOT NULL,
+	"action" varchar(255) NOT NULL,
+	"entity" varchar(255) NOT NULL,
+	"entity_id" text NOT NULL,
+	"before_summary" text,
+	"after_summary" text,
+	"created" TIMESTAMP WITHOUT TIME ZONE DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_idx_entity ON audit_log ("entity", "entity_id");
+CREATE INDEX IF NOT EXISTS audit_log_idx_actor ON audit_log ("actor");
+CREATE INDEX IF NOT EXISTS audit_log_idx_created ON audit_log ("created");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS config_triggers;
+ALTER TABLE config_keys DROP COLUMN "trigger_phase";
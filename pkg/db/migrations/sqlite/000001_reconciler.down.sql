@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS config_values;
+DROP TABLE IF EXISTS config_keys;
+DROP TABLE IF EXISTS config_cache;
+DROP TABLE IF EXISTS config_cachedeps;
+
+DROP TABLE IF EXISTS inventory_clusters;
+DROP TABLE IF EXISTS inventory_cluster_configs;
+DROP TABLE IF EXISTS inventory_cluster_config_statuses;
@@ -0,0 +1,5 @@
+--CLUSTER LABELS AND ANNOTATIONS (contract version 2)
+
+--JSON encoded labels and annotations of a cluster (empty for clusters registered with contract version 1):
+ALTER TABLE inventory_clusters ADD COLUMN "labels" text NOT NULL DEFAULT '';
+ALTER TABLE inventory_clusters ADD COLUMN "annotations" text NOT NULL DEFAULT '';
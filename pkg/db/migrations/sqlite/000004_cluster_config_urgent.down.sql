@@ -0,0 +1 @@
+ALTER TABLE inventory_cluster_configs DROP COLUMN "urgent";
@@ -0,0 +1,4 @@
+--URGENT CLUSTER CONFIGURATIONS
+
+--urgent configuration changes are applied outside of maintenance windows:
+ALTER TABLE inventory_cluster_configs ADD COLUMN "urgent" boolean DEFAULT FALSE;
@@ -0,0 +1,11 @@
+--ROLLBACKS OF CLUSTER CONFIGURATIONS
+
+--DDL for rollbacks of a cluster configuration to a previous version:
+CREATE TABLE IF NOT EXISTS inventory_cluster_config_rollbacks (
+	"id" integer PRIMARY KEY AUTOINCREMENT,
+	"cluster" text NOT NULL,
+	"config_version" int NOT NULL,
+	"source_config_version" int NOT NULL,
+	"username" varchar(255) NOT NULL,
+	"created" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
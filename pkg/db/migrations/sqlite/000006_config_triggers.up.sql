@@ -0,0 +1,17 @@
+--CONFIGURATION TRIGGERS
+
+--phase in which the trigger of a key is executed:
+ALTER TABLE config_keys ADD COLUMN "trigger_phase" varchar(255);
+
+--DDL for pending configuration trigger entities:
+CREATE TABLE IF NOT EXISTS config_triggers (
+	"id" integer PRIMARY KEY AUTOINCREMENT, --just another unique identifer for a pending trigger
+	"cluster" text NOT NULL,
+	"bucket" text NOT NULL,
+	"key" text NOT NULL,
+	"key_version" integer NOT NULL,
+	"phase" varchar(255) NOT NULL,
+	"value_version" integer NOT NULL,
+	"previous_value_version" integer,
+	"created" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
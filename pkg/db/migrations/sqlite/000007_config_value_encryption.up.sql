@@ -0,0 +1,4 @@
+--ENCRYPTED CONFIGURATION VALUES
+
+--values of encrypted keys are stored encrypted (values stored before stay unencrypted until a new version is created):
+ALTER TABLE config_values ADD COLUMN "encrypted" boolean DEFAULT FALSE;
@@ -36,6 +36,14 @@ func (dr *MockDataRows) Next() bool {
 	return false
 }
 
+func (dr *MockDataRows) Close() error {
+	return nil
+}
+
+func (dr *MockDataRows) Err() error {
+	return nil
+}
+
 type MockResult struct {
 }
 
@@ -73,7 +81,7 @@ func (c *MockConnection) Exec(query string, args ...interface{}) (sql.Result, er
 	return &MockResult{}, nil
 }
 
-func (c *MockConnection) Begin() (*sql.Tx, error) {
+func (c *MockConnection) Begin() (*TxConnection, error) {
 	return nil, nil
 }
 
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
 
@@ -13,24 +14,26 @@ import (
 )
 
 type PostgresConnection struct {
-	db        *sql.DB
-	encryptor *Encryptor
-	logger    *zap.SugaredLogger
+	db          *sql.DB
+	encryptor   *Encryptor
+	logger      *zap.SugaredLogger
+	retryPolicy *RetryPolicy
 }
 
-func newPostgresConnection(db *sql.DB, encryptionKey string, debug bool) (*PostgresConnection, error) {
+func newPostgresConnection(db *sql.DB, encryptionKey string, previousEncryptionKeys []string, retryPolicy *RetryPolicy, debug bool) (*PostgresConnection, error) {
 	logger, err := log.NewLogger(debug)
 	if err != nil {
 		return nil, err
 	}
-	encryptor, err := NewEncryptor(encryptionKey)
+	encryptor, err := NewEncryptor(encryptionKey, previousEncryptionKeys...)
 	if err != nil {
 		return nil, err
 	}
 	return &PostgresConnection{
-		db:        db,
-		encryptor: encryptor,
-		logger:    logger,
+		db:          db,
+		encryptor:   encryptor,
+		logger:      logger,
+		retryPolicy: retryPolicy,
 	}, nil
 }
 
@@ -38,6 +41,11 @@ func (pc *PostgresConnection) Encryptor() *Encryptor {
 	return pc.encryptor
 }
 
+//RetryPolicy returns the policy used for retrying transactions which failed because of transient errors
+func (pc *PostgresConnection) RetryPolicy() *RetryPolicy {
+	return pc.retryPolicy
+}
+
 func (pc *PostgresConnection) QueryRow(query string, args ...interface{}) DataRow {
 	pc.logger.Debugf("Postgres QueryRow(): %s | %v", query, args)
 	return pc.db.QueryRow(query, args...)
@@ -61,9 +69,13 @@ func (pc *PostgresConnection) Exec(query string, args ...interface{}) (sql.Resul
 	return result, err
 }
 
-func (pc *PostgresConnection) Begin() (*sql.Tx, error) {
+func (pc *PostgresConnection) Begin() (*TxConnection, error) {
 	pc.logger.Debug("Postgres Begin()")
-	return pc.db.Begin()
+	tx, err := pc.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return newTxConnection(tx, pc, pc.logger), nil
 }
 
 func (pc *PostgresConnection) Close() error {
@@ -84,10 +96,24 @@ type PostgresConnectionFactory struct {
 	SslMode       bool
 	EncryptionKey string
 	Debug         bool
+	Migrate       bool //apply pending schema migrations during the initialization
+	//PreviousEncryptionKeys are used to decrypt data which was encrypted before the encryption key was rotated
+	PreviousEncryptionKeys []string
+	//Connection pool settings (0 = default of database/sql)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	//StatementTimeout aborts statements which are running longer (0 = no timeout)
+	StatementTimeout time.Duration
+	//Retry is used for transactions which failed because of serialization failures or lost connections (nil = no retry)
+	Retry *RetryPolicy
 }
 
 func (pcf *PostgresConnectionFactory) Init() error {
-	//no init action required for postgres
+	if pcf.Migrate {
+		return migrate(pcf, pcf.Debug)
+	}
 	return nil
 }
 
@@ -97,12 +123,20 @@ func (pcf *PostgresConnectionFactory) NewConnection() (Connection, error) {
 		sslMode = "require"
 	}
 
-	db, err := sql.Open(
-		"postgres",
-		fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-			pcf.Host, pcf.Port, pcf.User, pcf.Password, pcf.Database, sslMode))
+	dataSource := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		pcf.Host, pcf.Port, pcf.User, pcf.Password, pcf.Database, sslMode)
+	if pcf.StatementTimeout > 0 {
+		//passed as run-time parameter to the database server
+		dataSource = fmt.Sprintf("%s statement_timeout=%d", dataSource, pcf.StatementTimeout.Milliseconds())
+	}
+
+	db, err := sql.Open("postgres", dataSource)
 
 	if err == nil {
+		db.SetMaxOpenConns(pcf.MaxOpenConns)
+		db.SetMaxIdleConns(pcf.MaxIdleConns)
+		db.SetConnMaxLifetime(pcf.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(pcf.ConnMaxIdleTime)
 		err = db.Ping()
 	}
 
@@ -110,5 +144,5 @@ func (pcf *PostgresConnectionFactory) NewConnection() (Connection, error) {
 		return nil, err
 	}
 
-	return newPostgresConnection(db, pcf.EncryptionKey, pcf.Debug)
+	return newPostgresConnection(db, pcf.EncryptionKey, pcf.PreviousEncryptionKeys, pcf.Retry, pcf.Debug)
 }
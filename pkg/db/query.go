@@ -12,7 +12,6 @@ type Query struct {
 	entity        DatabaseEntity
 	columnHandler *ColumnHandler
 	buffer        bytes.Buffer
-	whereClause   bool
 }
 
 func NewQuery(conn Connection, entity DatabaseEntity) (*Query, error) {
@@ -34,7 +33,7 @@ func (q Query) String() string {
 func (q *Query) Select() *Select {
 	q.buffer.WriteString(fmt.Sprintf("SELECT %s FROM %s", q.columnHandler.ColumnNamesCsv(false), q.entity.Table()))
 
-	return &Select{q, []interface{}{}, nil}
+	return &Select{Query: q}
 }
 
 func (q *Query) Insert() *Insert {
@@ -49,7 +48,7 @@ func (q *Query) Insert() *Insert {
 func (q *Query) Delete() *Delete {
 	q.buffer.WriteString(fmt.Sprintf("DELETE FROM %s", q.entity.Table()))
 
-	return &Delete{q, []interface{}{}, nil}
+	return &Delete{Query: q}
 }
 
 func (q *Query) Update() *Update {
@@ -58,111 +57,91 @@ func (q *Query) Update() *Update {
 	q.buffer.WriteString(fmt.Sprintf("UPDATE %s SET %s",
 		q.entity.Table(), colEntriesCsv))
 
-	return &Update{q, []interface{}{}, plcHdrCnt, err}
+	return &Update{Query: q, placeholderOffset: plcHdrCnt, err: err}
 }
 
 // helper functions:
 func (q *Query) reset() {
 	q.buffer = bytes.Buffer{}
-	q.whereClause = false
 }
 
-func (q *Query) addWhereCondition(whereCond map[string]interface{}, plcHdrOffset int) ([]interface{}, error) {
-	var args []interface{}
-	var plcHdrIdx int
-
-	if len(whereCond) == 0 {
-		return args, nil
-	}
-
-	//get sort list of fields
+//whereConditions converts a map of field-value pairs into equality conditions (sorted by field name)
+func whereConditions(whereCond map[string]interface{}) []Condition {
 	fields := make([]string, 0, len(whereCond))
 	for field := range whereCond {
 		fields = append(fields, field)
 	}
 	sort.Strings(fields)
 
-	//render WHERE condition
-	q.addWhere()
+	conds := make([]Condition, 0, len(fields))
 	for _, field := range fields {
-		col, err := q.columnHandler.ColumnName(field)
-		if err != nil {
-			return args, err
-		}
-		if plcHdrIdx > 0 {
-			q.buffer.WriteString(" AND")
-		}
-		plcHdrIdx++
-		q.buffer.WriteString(fmt.Sprintf(" %s=$%d", col, plcHdrIdx+plcHdrOffset))
-		args = append(args, whereCond[field])
+		conds = append(conds, &equality{field, whereCond[field]})
 	}
-	return args, nil
+	return conds
 }
 
-func (q *Query) addWhereInCondition(field, subQuery string) error {
-	colName, err := q.columnHandler.ColumnName(field)
-	if err != nil {
-		return err
+//addWhere renders the conditions as WHERE clause and returns the query arguments
+//(placeholders start after the given offset)
+func (q *Query) addWhere(conds []Condition, plcHdrOffset int) ([]interface{}, error) {
+	plcHdrs := &placeholders{offset: plcHdrOffset}
+	if len(conds) == 0 {
+		return plcHdrs.args, nil
 	}
-	q.addWhere()
-	q.buffer.WriteString(fmt.Sprintf(" %s IN (%s)", colName, subQuery))
-	return nil
-}
-
-func (q *Query) addWhere() {
-	if q.whereClause {
-		q.buffer.WriteString(" AND")
-	} else {
-		q.buffer.WriteString(" WHERE")
-		q.whereClause = true
+	rendered := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		sqlCond, err := cond.render(q.columnHandler, plcHdrs)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, sqlCond)
 	}
+	q.buffer.WriteString(fmt.Sprintf(" WHERE %s", strings.Join(rendered, " AND ")))
+	return plcHdrs.args, nil
 }
 
 // SELECT:
 type Select struct {
 	*Query
-	args []interface{}
-	err  error
+	conds   []Condition
+	groupBy []string
+	orderBy []string
+	limit   int
+	offset  int
+	err     error
 }
 
 func (s *Select) Where(args map[string]interface{}) *Select {
-	s.args, s.err = s.addWhereCondition(args, 0)
+	s.conds = append(s.conds, whereConditions(args)...)
 	return s
 }
 
+//WhereIn matches entities whose field is part of the result of the sub-query.
+//Placeholders of the sub-query have to start at $1: they get shifted automatically
+//if the query contains further arguments.
 func (s *Select) WhereIn(field, subQuery string, args ...interface{}) *Select {
-	s.err = s.addWhereInCondition(field, subQuery)
-	s.args = args
+	s.conds = append(s.conds, &subQueryCondition{field, subQuery, args})
 	return s
 }
 
-func (s *Select) GroupBy(args []string) *Select {
-	if len(args) == 0 {
-		return s
-	}
+//WhereCondition adds the conditions to the WHERE clause
+func (s *Select) WhereCondition(conds ...Condition) *Select {
+	s.conds = append(s.conds, conds...)
+	return s
+}
 
-	//get groupings
-	var grouping []string
+func (s *Select) GroupBy(args []string) *Select {
 	for _, field := range args {
 		col, err := s.columnHandler.ColumnName(field)
 		if err != nil {
 			s.err = err
 			return s
 		}
-		grouping = append(grouping, fmt.Sprintf(" %s", col))
+		s.groupBy = append(s.groupBy, col)
 	}
-
-	//render group condition
-	s.buffer.WriteString(" GROUP BY")
-	s.buffer.WriteString(strings.Join(grouping, ", "))
 	return s
 }
 
 func (s *Select) OrderBy(args map[string]string) *Select {
-	if len(args) == 0 {
-		return s
-	}
-
 	//get sorted list of fields
 	fields := make([]string, 0, len(args))
 	for field := range args {
@@ -170,46 +149,108 @@ func (s *Select) OrderBy(args map[string]string) *Select {
 	}
 	sort.Strings(fields)
 
-	//get orderings
-	var ordering []string
 	for _, field := range fields {
-		col, err := s.columnHandler.ColumnName(field)
-		if err != nil {
+		if err := s.addOrdering(field, args[field]); err != nil {
 			s.err = err
 			return s
 		}
-		ordering = append(ordering, fmt.Sprintf(" %s %s", col, args[field]))
 	}
-
-	//render order condition
-	s.buffer.WriteString(" ORDER BY")
-	s.buffer.WriteString(strings.Join(ordering, ", "))
 	return s
 }
 
+func (s *Select) addOrdering(field, direction string) error {
+	col, err := s.columnHandler.ColumnName(field)
+	if err != nil {
+		return err
+	}
+	direction = strings.ToUpper(direction)
+	if direction != "ASC" && direction != "DESC" {
+		return fmt.Errorf("ordering direction '%s' of field '%s' is invalid: use 'ASC' or 'DESC'", direction, field)
+	}
+	s.orderBy = append(s.orderBy, fmt.Sprintf("%s %s", col, direction))
+	return nil
+}
+
 func (s *Select) Limit(limit int) *Select {
-	s.buffer.WriteString(fmt.Sprintf(" LIMIT %d", limit))
+	s.limit = limit
 	return s
 }
 
+//Offset skips the given number of entities (requires a deterministic ordering to be useful for pagination)
+func (s *Select) Offset(offset int) *Select {
+	s.offset = offset
+	return s
+}
+
+//After is used for cursor based pagination: it returns only entities whose field is greater than
+//the cursor (the field value of the last entity of the previous page) and orders the result ascending by this field.
+func (s *Select) After(field string, cursor interface{}) *Select {
+	s.conds = append(s.conds, Gt(field, cursor))
+	if err := s.addOrdering(field, "ASC"); err != nil {
+		s.err = err
+	}
+	return s
+}
+
+//Before is used for cursor based pagination: it returns only entities whose field is less than
+//the cursor (the field value of the last entity of the previous page) and orders the result descending by this field.
+func (s *Select) Before(field string, cursor interface{}) *Select {
+	s.conds = append(s.conds, Lt(field, cursor))
+	if err := s.addOrdering(field, "DESC"); err != nil {
+		s.err = err
+	}
+	return s
+}
+
+//render finalizes the SELECT statement and returns its arguments
+func (s *Select) render() ([]interface{}, error) {
+	args, err := s.addWhere(s.conds, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.groupBy) > 0 {
+		s.buffer.WriteString(fmt.Sprintf(" GROUP BY %s", strings.Join(s.groupBy, ", ")))
+	}
+	if len(s.orderBy) > 0 {
+		s.buffer.WriteString(fmt.Sprintf(" ORDER BY %s", strings.Join(s.orderBy, ", ")))
+	}
+	if s.limit > 0 {
+		s.buffer.WriteString(fmt.Sprintf(" LIMIT %d", s.limit))
+	}
+	if s.offset > 0 {
+		if s.limit <= 0 && s.conn.Type() == SQLite {
+			s.buffer.WriteString(" LIMIT -1") //SQLite doesn't support OFFSET without LIMIT
+		}
+		s.buffer.WriteString(fmt.Sprintf(" OFFSET %d", s.offset))
+	}
+	return args, nil
+}
+
 func (s *Select) GetOne() (DatabaseEntity, error) {
+	defer s.reset()
 	if s.err != nil {
 		return nil, s.err
 	}
-	defer s.reset()
-	row := s.conn.QueryRow(s.buffer.String(), s.args...)
+	args, err := s.render()
+	if err != nil {
+		return nil, err
+	}
+	row := s.conn.QueryRow(s.buffer.String(), args...)
 	return s.entity, s.columnHandler.Unmarshal(row, s.entity)
 }
 
 func (s *Select) GetMany() ([]DatabaseEntity, error) {
+	defer s.reset()
 	if s.err != nil {
 		return nil, s.err
 	}
-
-	defer s.reset()
+	args, err := s.render()
+	if err != nil {
+		return nil, err
+	}
 
 	//get results
-	rows, err := s.conn.Query(s.buffer.String(), s.args...)
+	rows, err := s.conn.Query(s.buffer.String(), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +271,33 @@ func (s *Select) GetMany() ([]DatabaseEntity, error) {
 	return result, nil
 }
 
+//Count returns the number of matching entities (or groups if GROUP BY is used).
+//Ordering, limit and offset are ignored which allows to retrieve the total for paginated results.
+func (s *Select) Count() (int, error) {
+	defer s.reset()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.buffer.Reset()
+	if len(s.groupBy) > 0 {
+		s.buffer.WriteString(fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s", strings.Join(s.groupBy, ", "), s.entity.Table()))
+	} else {
+		s.buffer.WriteString(fmt.Sprintf("SELECT COUNT(*) FROM %s", s.entity.Table()))
+	}
+	args, err := s.addWhere(s.conds, 0)
+	if err != nil {
+		return 0, err
+	}
+	if len(s.groupBy) > 0 {
+		s.buffer.WriteString(fmt.Sprintf(" GROUP BY %s) AS grouped", strings.Join(s.groupBy, ", ")))
+	}
+
+	var count int
+	err = s.conn.QueryRow(s.buffer.String(), args...).Scan(&count)
+	return count, err
+}
+
 // INSERT:
 type Insert struct {
 	*Query
@@ -252,52 +320,79 @@ func (i *Insert) Exec() error {
 // DELETE:
 type Delete struct {
 	*Query
-	args []interface{}
-	err  error
+	conds []Condition
+	err   error
 }
 
 func (d *Delete) Where(args map[string]interface{}) *Delete {
-	d.args, d.err = d.addWhereCondition(args, 0)
+	d.conds = append(d.conds, whereConditions(args)...)
+	return d
+}
+
+//WhereIn matches entities whose field is part of the result of the sub-query.
+//Placeholders of the sub-query have to start at $1: they get shifted automatically
+//if the query contains further arguments.
+func (d *Delete) WhereIn(field, subQuery string, args ...interface{}) *Delete {
+	d.conds = append(d.conds, &subQueryCondition{field, subQuery, args})
+	return d
+}
+
+//WhereCondition adds the conditions to the WHERE clause
+func (d *Delete) WhereCondition(conds ...Condition) *Delete {
+	d.conds = append(d.conds, conds...)
 	return d
 }
 
 func (d *Delete) Exec() (int64, error) {
+	defer d.reset()
 	if d.err != nil {
 		return 0, d.err
 	}
-	defer d.reset()
-	res, err := d.conn.Exec(d.buffer.String(), d.args...)
+	args, err := d.addWhere(d.conds, 0)
+	if err != nil {
+		return 0, err
+	}
+	res, err := d.conn.Exec(d.buffer.String(), args...)
 	if err == nil {
 		return res.RowsAffected()
 	}
 	return 0, err
 }
 
-func (d *Delete) WhereIn(field, subQuery string, args ...interface{}) *Delete {
-	d.err = d.addWhereInCondition(field, subQuery)
-	d.args = args
-	return d
-}
-
 // UPDATE:
 type Update struct {
 	*Query
-	args              []interface{}
+	conds             []Condition
 	placeholderOffset int
 	err               error
 }
 
 func (u *Update) Where(args map[string]interface{}) *Update {
-	u.args, u.err = u.addWhereCondition(args, u.placeholderOffset)
+	u.conds = append(u.conds, whereConditions(args)...)
+	return u
+}
+
+//WhereCondition adds the conditions to the WHERE clause
+func (u *Update) WhereCondition(conds ...Condition) *Update {
+	u.conds = append(u.conds, conds...)
 	return u
 }
 
 func (u *Update) Exec() error {
 	defer u.reset()
+	if u.err != nil {
+		return u.err
+	}
 	if err := u.columnHandler.Validate(); err != nil {
 		return err
 	}
 
+	//placeholders of the WHERE clause start after the placeholders of the SET clause
+	args, err := u.addWhere(u.conds, u.placeholderOffset)
+	if err != nil {
+		return err
+	}
+
 	//finalize query by appending RETURNING
 	u.buffer.WriteString(fmt.Sprintf(" RETURNING %s", u.columnHandler.ColumnNamesCsv(false)))
 
@@ -305,6 +400,6 @@ func (u *Update) Exec() error {
 	if err != nil {
 		return err
 	}
-	row := u.conn.QueryRow(u.buffer.String(), colVals...)
+	row := u.conn.QueryRow(u.buffer.String(), append(colVals, args...)...)
 	return u.columnHandler.Unmarshal(row, u.entity)
 }
@@ -65,4 +65,88 @@ func TestQuery(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "UPDATE mockTable SET col_1=$1, col_3=$2 WHERE col_1=$3 RETURNING col_1, col_2, col_3", conn.query)
 	})
+	t.Run("Select with conditions", func(t *testing.T) {
+		_, err := q.Select().
+			Where(map[string]interface{}{"Col1": "col1Value"}).
+			WhereCondition(Or(Like("Col1", "abc%"), Gte("Col3", 3))).
+			WhereIn("Col2", "SELECT col FROM table WHERE x=$1 AND y=$2", "a", "b").
+			GetMany()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT col_1, col_2, col_3 FROM mockTable WHERE col_1=$1 AND (col_1 LIKE $2 OR col_3 >= $3) "+
+			"AND col_2 IN (SELECT col FROM table WHERE x=$4 AND y=$5)", conn.query)
+		require.Equal(t, []interface{}{"col1Value", "abc%", 3, "a", "b"}, conn.args)
+	})
+
+	t.Run("Select with invalid field in condition", func(t *testing.T) {
+		_, err := q.Select().WhereCondition(Eq("DoesNotExist", 1)).GetMany()
+		require.Error(t, err)
+	})
+
+	t.Run("Select with invalid ordering", func(t *testing.T) {
+		_, err := q.Select().OrderBy(map[string]string{"Col1": "ASC; DROP TABLE x"}).GetMany()
+		require.Error(t, err)
+	})
+
+	t.Run("Select with offset pagination", func(t *testing.T) {
+		_, err := q.Select().
+			OrderBy(map[string]string{"Col1": "asc"}).
+			Limit(10).
+			Offset(20).
+			GetMany()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT col_1, col_2, col_3 FROM mockTable ORDER BY col_1 ASC LIMIT 10 OFFSET 20", conn.query)
+	})
+
+	t.Run("Select with cursor pagination", func(t *testing.T) {
+		_, err := q.Select().
+			Where(map[string]interface{}{"Col2": true}).
+			After("Col3", 5).
+			Limit(10).
+			GetMany()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT col_1, col_2, col_3 FROM mockTable WHERE col_2=$1 AND col_3 > $2 ORDER BY col_3 ASC LIMIT 10", conn.query)
+		require.Equal(t, []interface{}{true, 5}, conn.args)
+
+		_, err = q.Select().
+			Before("Col3", 5).
+			Limit(10).
+			GetMany()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT col_1, col_2, col_3 FROM mockTable WHERE col_3 < $1 ORDER BY col_3 DESC LIMIT 10", conn.query)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		_, err := q.Select().
+			Where(map[string]interface{}{"Col1": "col1Value"}).
+			Limit(10).
+			Count()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT COUNT(*) FROM mockTable WHERE col_1=$1", conn.query)
+		require.Equal(t, []interface{}{"col1Value"}, conn.args)
+
+		_, err = q.Select().
+			WhereCondition(NotEq("Col1", "col1Value")).
+			GroupBy([]string{"Col2"}).
+			Count()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT COUNT(*) FROM (SELECT col_2 FROM mockTable WHERE col_1 <> $1 GROUP BY col_2) AS grouped", conn.query)
+	})
+
+	t.Run("Delete with conditions", func(t *testing.T) {
+		_, err := q.Delete().
+			WhereCondition(In("Col3", 1, 2)).
+			WhereIn("Col1", "SELECT col FROM table WHERE y=$1", "abc").
+			Exec()
+		require.NoError(t, err)
+		require.Equal(t, "DELETE FROM mockTable WHERE col_3 IN ($1, $2) AND col_1 IN (SELECT col FROM table WHERE y=$3)", conn.query)
+		require.Equal(t, []interface{}{1, 2, "abc"}, conn.args)
+	})
+
+	t.Run("Update with conditions", func(t *testing.T) {
+		err = q.Update().WhereCondition(Lt("Col3", 10)).Exec()
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE mockTable SET col_1=$1, col_3=$2 WHERE col_3 < $3 RETURNING col_1, col_2, col_3", conn.query)
+		require.Len(t, conn.args, 3)
+		require.Equal(t, 10, conn.args[2])
+	})
 }
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+//RetryPolicy defines how often a transaction is retried if it failed because of a transient error
+type RetryPolicy struct {
+	MaxAttempts    int           //maximal amount of attempts (including the first one)
+	InitialBackoff time.Duration //waiting time before the first retry
+	MaxBackoff     time.Duration //upper limit of the exponentially increasing waiting time
+}
+
+//noRetry executes a transaction only once
+var noRetry = &RetryPolicy{MaxAttempts: 1}
+
+//retryPolicyProvider is implemented by connections which support retries of transactions
+type retryPolicyProvider interface {
+	RetryPolicy() *RetryPolicy
+}
+
+func retryPolicy(conn Connection) *RetryPolicy {
+	if _, ok := conn.(*TxConnection); ok {
+		return noRetry //nested transactions are retried by the outer transaction
+	}
+	if provider, ok := conn.(retryPolicyProvider); ok && provider.RetryPolicy() != nil {
+		return provider.RetryPolicy()
+	}
+	return noRetry
+}
+
+//backoff returns the waiting time before the next attempt (attempt starts at 1)
+func (rp *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := rp.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if rp.MaxBackoff > 0 && backoff >= rp.MaxBackoff {
+			return rp.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+//IsRetryableError returns true for transient errors which can succeed when the transaction is retried:
+//serialization failures, deadlocks and lost connections
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "40001": //serialization_failure
+			return true
+		case pqErr.Code == "40P01": //deadlock_detected
+			return true
+		case pqErr.Code.Class() == "08": //connection_exception
+			return true
+		case pqErr.Code == "57P01": //admin_shutdown
+			return true
+		}
+		return false
+	}
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
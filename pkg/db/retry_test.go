@@ -0,0 +1,94 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+type retryConnection struct {
+	Connection
+	policy *RetryPolicy
+}
+
+func (rc *retryConnection) RetryPolicy() *RetryPolicy {
+	return rc.policy
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Backoff", func(t *testing.T) {
+		policy := &RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     300 * time.Millisecond,
+		}
+		require.Equal(t, 100*time.Millisecond, policy.backoff(1))
+		require.Equal(t, 200*time.Millisecond, policy.backoff(2))
+		require.Equal(t, 300*time.Millisecond, policy.backoff(3))
+		require.Equal(t, 300*time.Millisecond, policy.backoff(10))
+	})
+
+	t.Run("Retryable errors", func(t *testing.T) {
+		require.True(t, IsRetryableError(&pq.Error{Code: "40001"}))
+		require.True(t, IsRetryableError(&pq.Error{Code: "40P01"}))
+		require.True(t, IsRetryableError(&pq.Error{Code: "08006"}))
+		require.True(t, IsRetryableError(fmt.Errorf("wrapped: %w", driver.ErrBadConn)))
+		require.True(t, IsRetryableError(fmt.Errorf("wrapped: %w", syscall.ECONNRESET)))
+		require.False(t, IsRetryableError(&pq.Error{Code: "23505"})) //unique_violation
+		require.False(t, IsRetryableError(errors.New("any error")))
+		require.False(t, IsRetryableError(nil))
+	})
+
+	t.Run("Transaction is retried", func(t *testing.T) {
+		encKey, err := NewEncryptionKey()
+		require.NoError(t, err)
+		sqliteConn, err := (&SqliteConnectionFactory{
+			File:          filepath.Join(t.TempDir(), "retry.db"),
+			EncryptionKey: encKey,
+		}).NewConnection()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, sqliteConn.Close())
+		}()
+		conn := &retryConnection{
+			Connection: sqliteConn,
+			policy:     &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		}
+
+		//transient errors are retried
+		var attempts int
+		err = Transaction(conn, func(tx Connection) error {
+			attempts++
+			if attempts < 2 {
+				return &pq.Error{Code: "40001"}
+			}
+			return nil
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+
+		//retries are limited
+		attempts = 0
+		err = Transaction(conn, func(tx Connection) error {
+			attempts++
+			return &pq.Error{Code: "40001"}
+		}, nil)
+		require.Error(t, err)
+		require.Equal(t, 3, attempts)
+
+		//other errors are not retried
+		attempts = 0
+		err = Transaction(conn, func(tx Connection) error {
+			attempts++
+			return errors.New("fail")
+		}, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+//EncryptedField defines an entity field which contains encrypted data
+type EncryptedField struct {
+	Entity DatabaseEntity
+	Field  string
+}
+
+func (ef *EncryptedField) String() string {
+	return fmt.Sprintf("%s.%s", ef.Entity.Table(), ef.Field)
+}
+
+//RotationProgress is called after each processed value of an encrypted field
+type RotationProgress func(field *EncryptedField, processed, total int)
+
+//RotateEncryption re-encrypts all values of the encrypted fields which were encrypted by a previous key
+//of the connection's key-ring with the current encryption key. All updates happen in one transaction.
+func RotateEncryption(conn Connection, fields []*EncryptedField, progress RotationProgress, logger *zap.SugaredLogger) (int, error) {
+	tx, err := conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var reEncrypted int
+	for _, field := range fields {
+		cnt, err := rotateField(conn, tx, field, progress)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = errors.Wrap(err, fmt.Sprintf("Rollback of key rotation failed: %s", rollbackErr))
+			}
+			return 0, err
+		}
+		if logger != nil {
+			logger.Debugf("Re-encrypted %d values of field '%s'", cnt, field)
+		}
+		reEncrypted += cnt
+	}
+
+	return reEncrypted, tx.Commit()
+}
+
+func rotateField(conn Connection, tx *TxConnection, field *EncryptedField, progress RotationProgress) (int, error) {
+	colHdr, err := NewColumnHandler(field.Entity, conn)
+	if err != nil {
+		return 0, err
+	}
+	colName, err := colHdr.ColumnName(field.Field)
+	if err != nil {
+		return 0, err
+	}
+
+	//read all values before updating them
+	rows, err := tx.Query(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL", colName, field.Entity.Table(), colName))
+	if err != nil {
+		return 0, err
+	}
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	var reEncrypted int
+	updateSQL := fmt.Sprintf("UPDATE %s SET %s=$1 WHERE %s=$2", field.Entity.Table(), colName, colName)
+	for idx, value := range values {
+		newValue, changed, err := conn.Encryptor().ReEncrypt(value)
+		if err != nil {
+			return 0, errors.Wrap(err, fmt.Sprintf("failed to re-encrypt value of field '%s'", field))
+		}
+		if changed {
+			if _, err := tx.Exec(updateSQL, newValue, value); err != nil {
+				return 0, err
+			}
+			reEncrypted++
+		}
+		if progress != nil {
+			progress(field, idx+1, len(values))
+		}
+	}
+	return reEncrypted, nil
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rotationEntity struct {
+	Data string `db:"notNull"`
+}
+
+func (re *rotationEntity) Table() string {
+	return "key_rotation"
+}
+
+func (re *rotationEntity) Marshaller() *EntityMarshaller {
+	return NewEntityMarshaller(&re)
+}
+
+func (re *rotationEntity) New() DatabaseEntity {
+	return &rotationEntity{}
+}
+
+func (re *rotationEntity) Equal(other DatabaseEntity) bool {
+	return false
+}
+
+func TestRotateEncryption(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "rotation.db")
+	oldKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+	oldEncryptor, err := NewEncryptor(oldKey)
+	require.NoError(t, err)
+	newKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+
+	//create test data encrypted with old key
+	oldConn, err := (&SqliteConnectionFactory{File: dbFile, EncryptionKey: oldKey}).NewConnection()
+	require.NoError(t, err)
+	_, err = oldConn.Exec("CREATE TABLE key_rotation (data text NOT NULL)")
+	require.NoError(t, err)
+	encData, err := oldEncryptor.Encrypt("secret")
+	require.NoError(t, err)
+	for _, value := range []string{
+		encData,                                  //encrypted value
+		fmt.Sprintf(`[{"value":"%s"}]`, encData), //encrypted value embedded in JSON
+		"plain value",                            //unencrypted value
+	} {
+		_, err = oldConn.Exec("INSERT INTO key_rotation (data) VALUES ($1)", value)
+		require.NoError(t, err)
+	}
+	require.NoError(t, oldConn.Close())
+
+	//rotate key
+	conn, err := (&SqliteConnectionFactory{
+		File:                   dbFile,
+		EncryptionKey:          newKey,
+		PreviousEncryptionKeys: []string{oldKey},
+	}).NewConnection()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+
+	var progressCalls int
+	reEncrypted, err := RotateEncryption(conn, []*EncryptedField{{Entity: &rotationEntity{}, Field: "Data"}},
+		func(field *EncryptedField, processed, total int) {
+			progressCalls++
+			require.Equal(t, 3, total)
+		}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, reEncrypted)
+	require.Equal(t, 3, progressCalls)
+
+	//all values are encrypted with the new key
+	rows, err := conn.Query("SELECT data FROM key_rotation")
+	require.NoError(t, err)
+	var values []string
+	for rows.Next() {
+		var value string
+		require.NoError(t, rows.Scan(&value))
+		values = append(values, value)
+	}
+	require.Len(t, values, 3)
+	for _, value := range values {
+		require.NotContains(t, value, oldEncryptor.KeyID())
+	}
+	require.Contains(t, values, "plain value")
+	require.True(t, conn.Encryptor().Decryptable(values[0]))
+	require.False(t, conn.Encryptor().Outdated(values[0]))
+	decData, err := conn.Encryptor().Decrypt(values[0])
+	require.NoError(t, err)
+	require.Equal(t, "secret", decData)
+}
@@ -2,7 +2,6 @@ package db
 
 import (
 	"database/sql"
-	"io/ioutil"
 	"os"
 
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
@@ -19,12 +18,12 @@ type SqliteConnection struct {
 	logger    *zap.SugaredLogger
 }
 
-func newSqliteConnection(db *sql.DB, encKey string, debug bool) (*SqliteConnection, error) {
+func newSqliteConnection(db *sql.DB, encKey string, previousEncKeys []string, debug bool) (*SqliteConnection, error) {
 	logger, err := log.NewLogger(debug)
 	if err != nil {
 		return nil, err
 	}
-	encryptor, err := NewEncryptor(encKey)
+	encryptor, err := NewEncryptor(encKey, previousEncKeys...)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +61,13 @@ func (sc *SqliteConnection) Exec(query string, args ...interface{}) (sql.Result,
 	return result, err
 }
 
-func (sc *SqliteConnection) Begin() (*sql.Tx, error) {
+func (sc *SqliteConnection) Begin() (*TxConnection, error) {
 	sc.logger.Debug("Sqlite3 Begin()")
-	return sc.db.Begin()
+	tx, err := sc.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return newTxConnection(tx, sc, sc.logger), nil
 }
 
 func (sc *SqliteConnection) Close() error {
@@ -80,8 +83,10 @@ type SqliteConnectionFactory struct {
 	File          string
 	Debug         bool
 	Reset         bool
-	SchemaFile    string
+	Migrate       bool //apply pending schema migrations during the initialization
 	EncryptionKey string
+	//PreviousEncryptionKeys are used to decrypt data which was encrypted before the encryption key was rotated
+	PreviousEncryptionKeys []string
 }
 
 func (scf *SqliteConnectionFactory) Init() error {
@@ -90,22 +95,8 @@ func (scf *SqliteConnectionFactory) Init() error {
 			return err
 		}
 	}
-	if scf.SchemaFile != "" {
-		//read DDL (test-table structure)
-		ddl, err := ioutil.ReadFile(scf.SchemaFile)
-		if err != nil {
-			return err
-		}
-
-		//get connection
-		conn, err := scf.NewConnection()
-		if err != nil {
-			return err
-		}
-
-		//populate DB schema
-		_, err = conn.Exec(string(ddl))
-		return err
+	if scf.Migrate {
+		return migrate(scf, scf.Debug)
 	}
 	return nil
 }
@@ -121,7 +112,7 @@ func (scf *SqliteConnectionFactory) NewConnection() (Connection, error) {
 		return nil, err
 	}
 
-	return newSqliteConnection(db, scf.EncryptionKey, scf.Debug) //connection ready to use
+	return newSqliteConnection(db, scf.EncryptionKey, scf.PreviousEncryptionKeys, scf.Debug) //connection ready to use
 }
 
 func (scf *SqliteConnectionFactory) resetFile() error {
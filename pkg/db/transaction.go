@@ -1,13 +1,126 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
-func TransactionResult(conn Connection, dbOps func() (interface{}, error), logger *zap.SugaredLogger) (interface{}, error) {
+//TxConnection is a connection which is bound to a database transaction. Beginning a transaction
+//on a TxConnection creates a savepoint within the surrounding transaction (nested transaction).
+type TxConnection struct {
+	tx        *sql.Tx
+	conn      Connection
+	savepoint string
+	depth     int
+	logger    *zap.SugaredLogger
+}
+
+func newTxConnection(tx *sql.Tx, conn Connection, logger *zap.SugaredLogger) *TxConnection {
+	return &TxConnection{
+		tx:     tx,
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (t *TxConnection) Encryptor() *Encryptor {
+	return t.conn.Encryptor()
+}
+
+func (t *TxConnection) QueryRow(query string, args ...interface{}) DataRow {
+	t.logger.Debugf("%s Tx QueryRow(): %s | %v", t.conn.Type(), query, args)
+	return t.tx.QueryRow(query, args...)
+}
+
+func (t *TxConnection) Query(query string, args ...interface{}) (DataRows, error) {
+	t.logger.Debugf("%s Tx Query(): %s | %v", t.conn.Type(), query, args)
+	rows, err := t.tx.Query(query, args...)
+	if err != nil {
+		t.logger.Errorf("%s Tx Query() error: %s", t.conn.Type(), err)
+	}
+	return rows, err
+}
+
+func (t *TxConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	t.logger.Debugf("%s Tx Exec(): %s | %v", t.conn.Type(), query, args)
+	result, err := t.tx.Exec(query, args...)
+	if err != nil {
+		t.logger.Errorf("%s Tx Exec() error: %s", t.conn.Type(), err)
+	}
+	return result, err
+}
+
+//Begin creates a savepoint within the transaction
+func (t *TxConnection) Begin() (*TxConnection, error) {
+	savepoint := fmt.Sprintf("reconciler_sp%d", t.depth+1)
+	if _, err := t.Exec(fmt.Sprintf("SAVEPOINT %s", savepoint)); err != nil {
+		return nil, err
+	}
+	return &TxConnection{
+		tx:        t.tx,
+		conn:      t.conn,
+		savepoint: savepoint,
+		depth:     t.depth + 1,
+		logger:    t.logger,
+	}, nil
+}
+
+//Commit commits the transaction or releases the savepoint of a nested transaction
+func (t *TxConnection) Commit() error {
+	if t.savepoint == "" {
+		t.logger.Debugf("%s Tx Commit()", t.conn.Type())
+		return t.tx.Commit()
+	}
+	_, err := t.Exec(fmt.Sprintf("RELEASE SAVEPOINT %s", t.savepoint))
+	return err
+}
+
+//Rollback rolls the transaction back or reverts all changes since the savepoint of a nested transaction
+func (t *TxConnection) Rollback() error {
+	if t.savepoint == "" {
+		t.logger.Debugf("%s Tx Rollback()", t.conn.Type())
+		return t.tx.Rollback()
+	}
+	_, err := t.Exec(fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", t.savepoint))
+	return err
+}
+
+//Close is a no-op: the transaction ends with Commit or Rollback and the underlying
+//database connection is owned by the connection which started the transaction
+func (t *TxConnection) Close() error {
+	return nil
+}
+
+func (t *TxConnection) Type() Type {
+	return t.conn.Type()
+}
+
+//TransactionResult executes the database operations within a transaction. All operations have to use the
+//connection passed to dbOps, otherwise they are not part of the transaction. If the given connection
+//is already bound to a transaction, the operations are executed in a nested transaction (savepoint).
+//Transactions which failed because of a transient error are retried according to the retry policy of
+//the connection: dbOps has to be repeatable.
+func TransactionResult(conn Connection, dbOps func(tx Connection) (interface{}, error), logger *zap.SugaredLogger) (interface{}, error) {
+	policy := retryPolicy(conn)
+	for attempt := 1; ; attempt++ {
+		result, err := transactionResult(conn, dbOps, logger)
+		if err == nil || attempt >= policy.MaxAttempts || !IsRetryableError(err) {
+			return result, err
+		}
+		backoff := policy.backoff(attempt)
+		if logger != nil {
+			logger.Warnf("Transaction failed with transient error (attempt %d of %d), retrying in %s: %s",
+				attempt, policy.MaxAttempts, backoff, err)
+		}
+		time.Sleep(backoff)
+	}
+}
+
+func transactionResult(conn Connection, dbOps func(tx Connection) (interface{}, error), logger *zap.SugaredLogger) (interface{}, error) {
 	log := func(msg string) {
 		if logger != nil {
 			logger.Debug(msg)
@@ -19,11 +132,11 @@ func TransactionResult(conn Connection, dbOps func() (interface{}, error), logge
 		return nil, err
 	}
 
-	result, err := dbOps()
+	result, err := dbOps(tx)
 	if err != nil {
 		log("Rollback transactional DB context")
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			err = errors.Wrap(err, fmt.Sprintf("Rollback of db operations failed: %s", tx.Rollback()))
+			err = errors.Wrap(err, fmt.Sprintf("Rollback of db operations failed: %s", rollbackErr))
 		}
 		return result, err
 	}
@@ -32,9 +145,9 @@ func TransactionResult(conn Connection, dbOps func() (interface{}, error), logge
 	return result, tx.Commit()
 }
 
-func Transaction(conn Connection, dbOps func() error, logger *zap.SugaredLogger) error {
-	dbOpsAdapter := func() (interface{}, error) {
-		return nil, dbOps()
+func Transaction(conn Connection, dbOps func(tx Connection) error, logger *zap.SugaredLogger) error {
+	dbOpsAdapter := func(tx Connection) (interface{}, error) {
+		return nil, dbOps(tx)
 	}
 	_, err := TransactionResult(conn, dbOpsAdapter, logger)
 	return err
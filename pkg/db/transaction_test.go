@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	encKey, err := NewEncryptionKey()
+	require.NoError(t, err)
+	conn, err := (&SqliteConnectionFactory{
+		File:          filepath.Join(t.TempDir(), "transaction.db"),
+		EncryptionKey: encKey,
+	}).NewConnection()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	_, err = conn.Exec("CREATE TABLE tx_test (data text NOT NULL)")
+	require.NoError(t, err)
+
+	insert := func(tx Connection, value string) error {
+		_, err := tx.Exec("INSERT INTO tx_test (data) VALUES ($1)", value)
+		return err
+	}
+	count := func() int {
+		var cnt int
+		require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM tx_test").Scan(&cnt))
+		return cnt
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := Transaction(conn, func(tx Connection) error {
+			require.IsType(t, &TxConnection{}, tx)
+			return insert(tx, "committed")
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, count())
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		err := Transaction(conn, func(tx Connection) error {
+			if err := insert(tx, "rolled back"); err != nil {
+				return err
+			}
+			return errors.New("fail")
+		}, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, count())
+	})
+
+	t.Run("Nested transaction rolls back to savepoint", func(t *testing.T) {
+		err := Transaction(conn, func(tx Connection) error {
+			if err := insert(tx, "outer"); err != nil {
+				return err
+			}
+			nestedErr := Transaction(tx, func(nestedTx Connection) error {
+				if err := insert(nestedTx, "inner"); err != nil {
+					return err
+				}
+				return errors.New("fail")
+			}, nil)
+			require.Error(t, nestedErr)
+			return nil
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, count()) //only the outer insert was committed
+	})
+
+	t.Run("Nested transaction is committed with outer transaction", func(t *testing.T) {
+		err := Transaction(conn, func(tx Connection) error {
+			return Transaction(tx, func(nestedTx Connection) error {
+				return insert(nestedTx, "inner")
+			}, nil)
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 3, count())
+	})
+
+	t.Run("Query builder uses transaction", func(t *testing.T) {
+		_, err := conn.Exec("CREATE TABLE key_rotation (data text NOT NULL)")
+		require.NoError(t, err)
+		err = Transaction(conn, func(tx Connection) error {
+			q, err := NewQuery(tx, &rotationEntity{Data: "query"})
+			if err != nil {
+				return err
+			}
+			if err := q.Insert().Exec(); err != nil {
+				return err
+			}
+			return errors.New("fail")
+		}, nil)
+		require.Error(t, err)
+		var cnt int
+		require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM key_rotation").Scan(&cnt))
+		require.Equal(t, 0, cnt)
+	})
+}
@@ -0,0 +1,299 @@
+package interpreter
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const allowedPackages = "fmt|regexp|net/url|strings|time|strconv"
+
+var allowedImport = regexp.MustCompile(fmt.Sprintf(`^(%s)$`, allowedPackages))
+
+//Program is Go code which was verified (e.g. only whitelisted imports are used), instrumented to enforce the
+//execution limits and split into segments. A program can be executed multiple times: each execution uses
+//a new interpreter (bindings, declarations and limits must not leak between executions) which parses the segments.
+type Program struct {
+	source   string
+	segments []string //top-level declarations and statements, evaluated in this order
+}
+
+//Prepare verifies and instruments Go code. The code can contain imports and function declarations
+//followed by statements: the value of the last statement is the result of the program.
+func Prepare(code string) (*Program, error) {
+	program := &Program{source: code}
+	for _, segment := range split(code) {
+		var prepared []string
+		var err error
+		if segment.declaration {
+			prepared, err = prepareDeclaration(segment.code)
+		} else {
+			prepared, err = prepareStatements(segment.code)
+		}
+		if err != nil {
+			return nil, err
+		}
+		program.segments = append(program.segments, prepared...)
+	}
+	return program, nil
+}
+
+func (p *Program) Source() string {
+	return p.source
+}
+
+//Interpreter returns an interpreter which executes the program
+func (p *Program) Interpreter() *GolangInterpreter {
+	return &GolangInterpreter{
+		code:    p.source,
+		program: p,
+	}
+}
+
+type segment struct {
+	code        string
+	declaration bool
+}
+
+//split separates top-level declarations (imports and functions) from statements
+//because the interpreter cannot evaluate them within one source
+func split(code string) []*segment {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+	var s scanner.Scanner
+	s.Init(file, []byte(code), nil, 0)
+
+	type tok struct {
+		offset int
+		tok    token.Token
+	}
+	var tokens []tok
+	for {
+		pos, t, _ := s.Scan()
+		if t == token.EOF {
+			break
+		}
+		tokens = append(tokens, tok{offset: file.Offset(pos), tok: t})
+	}
+
+	var segments []*segment
+	addSegment := func(start, end int, declaration bool) {
+		if code := strings.TrimSpace(code[start:end]); code != "" {
+			segments = append(segments, &segment{code: code, declaration: declaration})
+		}
+	}
+
+	var depth, stmtStart, declStart int
+	inDeclaration := false
+	for idx, t := range tokens {
+		switch t.tok {
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			depth--
+		case token.IMPORT, token.FUNC:
+			isDeclaration := t.tok == token.IMPORT || (idx+1 < len(tokens) && tokens[idx+1].tok == token.IDENT)
+			if depth == 0 && !inDeclaration && isDeclaration {
+				addSegment(stmtStart, t.offset, false)
+				declStart = t.offset
+				inDeclaration = true
+			}
+		case token.SEMICOLON:
+			if depth == 0 && inDeclaration {
+				end := t.offset + 1
+				if end > len(code) { //semicolon was inserted at the end of the code
+					end = len(code)
+				}
+				addSegment(declStart, end, true)
+				stmtStart = end
+				inDeclaration = false
+			}
+		}
+	}
+	if inDeclaration {
+		addSegment(declStart, len(code), true)
+	} else {
+		addSegment(stmtStart, len(code), false)
+	}
+	return segments
+}
+
+func prepareDeclaration(code string) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", "package main\n"+code, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Go interpreter failed to parse code '%s':\n%s", code, err)
+	}
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !allowedImport.MatchString(path) {
+			return nil, &BlockedImportError{BlockedImport: fmt.Sprintf("import %s", imp.Path.Value)}
+		}
+	}
+	if err := instrument(file); err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	for _, decl := range file.Decls {
+		if err := printer.Fprint(&buffer, fset, decl); err != nil {
+			return nil, err
+		}
+		buffer.WriteString("\n")
+	}
+	return []string{buffer.String()}, nil
+}
+
+//prepareStatements returns each statement separately: the interpreter evaluates a source which starts
+//with a declaration keyword (e.g. 'var') as file and would reject any following statement
+func prepareStatements(code string) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", "package main\nfunc _() {\n"+code+"\n}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("Go interpreter failed to parse code '%s':\n%s", code, err)
+	}
+	if err := instrument(file); err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, stmt := range file.Decls[0].(*ast.FuncDecl).Body.List {
+		var buffer bytes.Buffer
+		if err := printer.Fprint(&buffer, fset, stmt); err != nil {
+			return nil, err
+		}
+		statements = append(statements, buffer.String())
+	}
+	return statements, nil
+}
+
+//instrument adds a step-counter call to each loop and function body and rejects goroutines
+//(they could outlive the execution limits)
+func instrument(file *ast.File) error {
+	var err error
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.GoStmt:
+			err = fmt.Errorf("Go interpreter doesn't allow the execution of goroutines")
+		case *ast.FuncDecl:
+			if n.Name.Name != "_" { //skip the wrapper function of statements
+				addStep(n.Body)
+			}
+		case *ast.FuncLit:
+			addStep(n.Body)
+		case *ast.ForStmt:
+			addStep(n.Body)
+		case *ast.RangeStmt:
+			addStep(n.Body)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func addStep(body *ast.BlockStmt) {
+	if body == nil {
+		return
+	}
+	step := &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   ast.NewIdent(sandboxPackage),
+				Sel: ast.NewIdent(sandboxStepFunc),
+			},
+		},
+	}
+	body.List = append([]ast.Stmt{step}, body.List...)
+}
+
+//PreparedProgramCache stores prepared programs by name (e.g. validator of a configuration key) and version.
+//Only the latest version of a name is kept and the least recently used programs are evicted if the
+//cache exceeds its size. It saves the verification and instrumentation of the code but the interpreter
+//still parses the program on each execution.
+type PreparedProgramCache struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List //most recently used entry is at the front
+	mutex      sync.Mutex
+}
+
+type cacheEntry struct {
+	name    string
+	version int64
+	program *Program
+}
+
+func NewPreparedProgramCache(maxEntries int) *PreparedProgramCache {
+	return &PreparedProgramCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+//Get returns the cached program or prepares the code if no program exists for this name and version
+//or its code has changed
+func (pc *PreparedProgramCache) Get(name string, version int64, code string) (*Program, error) {
+	if program := pc.lookup(name, version, code); program != nil {
+		return program, nil
+	}
+
+	program, err := Prepare(code)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	if elem, ok := pc.entries[name]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if entry.version > version { //outdated versions are not cached
+			return program, nil
+		}
+		entry.version = version
+		entry.program = program
+		pc.lru.MoveToFront(elem)
+		return program, nil
+	}
+	pc.entries[name] = pc.lru.PushFront(&cacheEntry{name: name, version: version, program: program})
+	for pc.lru.Len() > pc.maxEntries {
+		oldest := pc.lru.Back()
+		pc.lru.Remove(oldest)
+		delete(pc.entries, oldest.Value.(*cacheEntry).name)
+	}
+	return program, nil
+}
+
+func (pc *PreparedProgramCache) lookup(name string, version int64, code string) *Program {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	elem, ok := pc.entries[name]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if entry.version != version || entry.program.source != code {
+		return nil
+	}
+	pc.lru.MoveToFront(elem)
+	return entry.program
+}
+
+//Len returns the number of cached programs
+func (pc *PreparedProgramCache) Len() int {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	return pc.lru.Len()
+}
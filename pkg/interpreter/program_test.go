@@ -0,0 +1,153 @@
+package interpreter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgram(t *testing.T) {
+
+	t.Run("Multi-line code with functions", func(t *testing.T) {
+		program, err := Prepare(`
+import (
+	"fmt"
+	"strings"
+)
+
+func isUpper(s string) bool {
+	return strings.ToUpper(s) == s
+}
+
+var count int
+for _, char := range strings.Split(value, "") {
+	if isUpper(char) {
+		count++
+	}
+}
+fmt.Sprintf("%d", count)
+`)
+		require.NoError(t, err)
+		result, err := program.Interpreter().WithBindings(map[string]interface{}{"value": "HeLLo"}).EvalString()
+		require.NoError(t, err)
+		require.Equal(t, "3", result)
+	})
+
+	t.Run("Block denied import in grouped imports", func(t *testing.T) {
+		_, err := Prepare(`
+import (
+	"strings"
+	"os/exec"
+)
+true
+`)
+		require.Error(t, err)
+		require.True(t, IsBlockedImportError(err))
+	})
+
+	t.Run("Block goroutines", func(t *testing.T) {
+		_, err := Prepare(`
+go func() {
+	for {}
+}()
+true
+`)
+		require.Error(t, err)
+	})
+
+	t.Run("Stop endless loop after timeout", func(t *testing.T) {
+		start := time.Now()
+		_, err := NewGolangInterpreter(`
+for {
+}
+`).WithLimits(Limits{Timeout: 200 * time.Millisecond}).Eval()
+		require.Error(t, err)
+		require.True(t, IsExecutionLimitError(err))
+		require.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	})
+
+	t.Run("Stop endless loop after max steps", func(t *testing.T) {
+		_, err := NewGolangInterpreter(`
+func endless(i int) int {
+	return endless(i + 1)
+}
+endless(0)
+`).WithLimits(Limits{Timeout: 10 * time.Second, MaxSteps: 1000}).Eval()
+		require.Error(t, err)
+		require.True(t, IsExecutionLimitError(err))
+		require.Contains(t, err.Error(), "steps")
+	})
+
+	t.Run("Stop execution after exceeding memory limit", func(t *testing.T) {
+		_, err := NewGolangInterpreter(`
+import "fmt"
+var data []string
+for i := 0; i < 100000; i++ {
+	data = append(data, fmt.Sprintf("entry %d", i))
+}
+len(data)
+`).WithLimits(Limits{Timeout: 10 * time.Second, MaxMemory: 1024}).Eval()
+		require.Error(t, err)
+		require.True(t, IsExecutionLimitError(err))
+		require.Contains(t, err.Error(), "memory")
+	})
+
+	t.Run("Cancel execution by context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+		_, err := NewGolangInterpreter(`
+for {
+}
+`).WithContext(ctx).WithLimits(Limits{Timeout: 10 * time.Second}).Eval()
+		require.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("Cache prepared programs", func(t *testing.T) {
+		cache := NewPreparedProgramCache(2)
+		program1, err := cache.Get("key/validator", 1, `it > 5`)
+		require.NoError(t, err)
+		program2, err := cache.Get("key/validator", 1, `it > 5`)
+		require.NoError(t, err)
+		require.Same(t, program1, program2)
+
+		//changed code is prepared again
+		program3, err := cache.Get("key/validator", 1, `it > 6`)
+		require.NoError(t, err)
+		require.NotSame(t, program1, program3)
+
+		result, err := program3.Interpreter().WithBindings(map[string]interface{}{"it": int64(6)}).EvalBool()
+		require.NoError(t, err)
+		require.False(t, result)
+
+		//new version replaces the old version
+		program4, err := cache.Get("key/validator", 2, `it > 6`)
+		require.NoError(t, err)
+		require.NotSame(t, program3, program4)
+		require.Equal(t, 1, cache.Len())
+
+		//outdated version doesn't replace the new version
+		_, err = cache.Get("key/validator", 1, `it > 6`)
+		require.NoError(t, err)
+		program5, err := cache.Get("key/validator", 2, `it > 6`)
+		require.NoError(t, err)
+		require.Same(t, program4, program5)
+
+		//least recently used program is evicted
+		_, err = cache.Get("key2/validator", 1, `it > 1`)
+		require.NoError(t, err)
+		_, err = cache.Get("key/validator", 2, `it > 6`)
+		require.NoError(t, err)
+		_, err = cache.Get("key3/validator", 1, `it > 1`)
+		require.NoError(t, err)
+		require.Equal(t, 2, cache.Len())
+		program6, err := cache.Get("key/validator", 2, `it > 6`)
+		require.NoError(t, err)
+		require.Same(t, program4, program6)
+	})
+
+}
@@ -0,0 +1,86 @@
+package interpreter
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/yaegi/interp"
+)
+
+const (
+	sandboxPackage  = "__sandbox"
+	sandboxStepFunc = "Step"
+
+	memoryCheckInterval = 1000 //verify the memory consumption after this number of steps
+)
+
+//DefaultLimits are used if no limits were defined for an interpreter
+var DefaultLimits = Limits{
+	Timeout:   3 * time.Second,
+	MaxSteps:  1000000,
+	MaxMemory: 128 * 1024 * 1024,
+}
+
+//Limits restrict the execution of interpreted code
+type Limits struct {
+	Timeout   time.Duration //maximum execution time
+	MaxSteps  int64         //maximum number of loop iterations and function calls
+	MaxMemory uint64        //maximum heap growth in bytes during the execution (approximation: the heap is shared with the host process)
+}
+
+//sandbox tracks the resource consumption of one execution
+type sandbox struct {
+	limits     Limits
+	steps      int64
+	heapOffset uint64
+}
+
+func newSandbox(limits Limits) *sandbox {
+	sb := &sandbox{limits: limits}
+	if limits.MaxMemory > 0 {
+		sb.heapOffset = heapAlloc()
+	}
+	return sb
+}
+
+//exports returns the symbols the instrumented code is calling
+func (sb *sandbox) exports() interp.Exports {
+	return interp.Exports{
+		sandboxPackage: {
+			sandboxStepFunc: reflect.ValueOf(sb.step),
+		},
+	}
+}
+
+func (sb *sandbox) step() {
+	steps := atomic.AddInt64(&sb.steps, 1)
+	if sb.limits.MaxSteps > 0 && steps > sb.limits.MaxSteps {
+		panic(&ExecutionLimitError{Limit: fmt.Sprintf("maximum of %d steps exceeded", sb.limits.MaxSteps)})
+	}
+	if sb.limits.MaxMemory > 0 && steps%memoryCheckInterval == 0 {
+		if heap := heapAlloc(); heap > sb.heapOffset && heap-sb.heapOffset > sb.limits.MaxMemory {
+			panic(&ExecutionLimitError{Limit: fmt.Sprintf("maximum memory of %d bytes exceeded", sb.limits.MaxMemory)})
+		}
+	}
+}
+
+func heapAlloc() uint64 {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapAlloc
+}
+
+type ExecutionLimitError struct {
+	Limit string
+}
+
+func (e *ExecutionLimitError) Error() string {
+	return fmt.Sprintf("Go interpreter stopped the execution: %s", e.Limit)
+}
+
+func IsExecutionLimitError(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(&ExecutionLimitError{})
+}
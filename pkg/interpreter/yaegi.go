@@ -1,24 +1,25 @@
 package interpreter
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"reflect"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/traefik/yaegi/interp"
 	"github.com/traefik/yaegi/stdlib"
 )
 
-const allowedPackages = "fmt|regexp|net/url|strings|time|strconv"
-
-var regExp = regexp.MustCompile(fmt.Sprintf(`^import\s+"(%s)"$`, allowedPackages))
-
 type GolangInterpreter struct {
 	code     string
+	program  *Program
 	bindings map[string]interface{}
+	ctx      context.Context
+	limits   *Limits
 }
 
 func NewGolangInterpreter(code string) *GolangInterpreter {
@@ -31,42 +32,85 @@ func (gi *GolangInterpreter) WithBindings(bindings map[string]interface{}) *Gola
 	if gi.bindings == nil {
 		gi.bindings = bindings
 	} else {
-		for k, v := range gi.bindings {
+		for k, v := range bindings {
 			gi.bindings[k] = v
 		}
 	}
 	return gi
 }
 
-func (gi *GolangInterpreter) Eval() (reflect.Value, error) {
-	interp := interp.New(interp.Options{})
-	interp.Use(stdlib.Symbols)
+//WithContext defines a context which cancels the execution
+func (gi *GolangInterpreter) WithContext(ctx context.Context) *GolangInterpreter {
+	gi.ctx = ctx
+	return gi
+}
 
+//WithLimits overwrites the default execution limits
+func (gi *GolangInterpreter) WithLimits(limits Limits) *GolangInterpreter {
+	gi.limits = &limits
+	return gi
+}
+
+func (gi *GolangInterpreter) Eval() (reflect.Value, error) {
 	var lastResult reflect.Value
-	var err error
+
+	program := gi.program
+	if program == nil {
+		var err error
+		if program, err = Prepare(gi.code); err != nil {
+			return lastResult, err
+		}
+	}
+
+	limits := DefaultLimits
+	if gi.limits != nil {
+		limits = *gi.limits
+	}
+	parentCtx := gi.ctx
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	ctx := parentCtx
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parentCtx, limits.Timeout)
+		defer cancel()
+	}
+
+	yaegi := interp.New(interp.Options{Stdout: ioutil.Discard, Stderr: ioutil.Discard})
+	yaegi.Use(stdlib.Symbols)
+	yaegi.Use(newSandbox(limits).exports())
+	if _, err := yaegi.Eval(fmt.Sprintf(`import "%s"`, sandboxPackage)); err != nil {
+		return lastResult, err
+	}
 
 	//add bindings to interpreter
-	if err := gi.bind(interp, gi.bindings); err != nil {
+	if err := gi.bind(yaegi, gi.bindings); err != nil {
 		return lastResult, err
 	}
 
 	//execute the code
-	scanner := bufio.NewScanner(strings.NewReader(gi.code))
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		//block execution of non-whitelisted imports
-		if strings.HasPrefix(line, "import") && !regExp.MatchString(line) {
-			return lastResult, &BlockedImportError{BlockedImport: line}
+	for _, segment := range program.segments {
+		var err error
+		lastResult, err = yaegi.EvalWithContext(ctx, segment)
+		if err == nil {
+			continue
 		}
-
-		lastResult, err = interp.Eval(line)
-		if err != nil {
-			return lastResult, fmt.Errorf("Go interpreter failed to execute line '%s':\n%s", line, err.Error())
+		if parentCtx.Err() != nil { //execution was cancelled by caller
+			return lastResult, parentCtx.Err()
+		}
+		if ctx.Err() != nil {
+			return lastResult, &ExecutionLimitError{Limit: fmt.Sprintf("timeout of %.1f secs exceeded", limits.Timeout.Seconds())}
+		}
+		if panicErr, ok := err.(interp.Panic); ok {
+			if limitErr, ok := panicErr.Value.(*ExecutionLimitError); ok {
+				return lastResult, limitErr
+			}
 		}
+		return lastResult, fmt.Errorf("Go interpreter failed to execute code '%s':\n%s", segment, err.Error())
 	}
 
-	return lastResult, err
+	return lastResult, nil
 }
 
 func (gi *GolangInterpreter) EvalBool() (bool, error) {
@@ -107,7 +151,7 @@ func (gi *GolangInterpreter) bind(interp *interp.Interpreter, bindings map[strin
 	for k, v := range bindings {
 		switch v.(type) {
 		case string:
-			_, err = interp.Eval(fmt.Sprintf(`var %s string = "%s"`, k, v))
+			_, err = interp.Eval(fmt.Sprintf(`var %s string = %q`, k, v))
 		case bool:
 			_, err = interp.Eval(fmt.Sprintf(`var %s bool = %t`, k, v))
 		case int:
@@ -118,14 +162,80 @@ func (gi *GolangInterpreter) bind(interp *interp.Interpreter, bindings map[strin
 			_, err = interp.Eval(fmt.Sprintf(`var %s float32 = %f`, k, v))
 		case float64:
 			_, err = interp.Eval(fmt.Sprintf(`var %s float64 = %f`, k, v))
+		case nil, []interface{}, map[string]interface{}:
+			err = gi.bindStructure(interp, k, v)
 		default:
 			err = fmt.Errorf("Cannot bind key '%s' because value of type '%T' is not supported", k, v)
 		}
+		if err != nil {
+			return err
+		}
 	}
 
+	return nil
+}
+
+//bindStructure binds a list or map (e.g. parsed from JSON or YAML) by converting it to a Go literal
+func (gi *GolangInterpreter) bindStructure(interp *interp.Interpreter, key string, value interface{}) error {
+	literal, err := goLiteral(value)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Cannot bind key '%s'", key))
+	}
+	varType := "interface{}"
+	switch value.(type) {
+	case []interface{}:
+		varType = "[]interface{}"
+	case map[string]interface{}:
+		varType = "map[string]interface{}"
+	}
+	_, err = interp.Eval(fmt.Sprintf(`var %s %s = %s`, key, varType, literal))
 	return err
 }
 
+func goLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return fmt.Sprintf("%q", v), nil
+	case bool:
+		return fmt.Sprintf("%t", v), nil
+	case int:
+		return fmt.Sprintf("int(%d)", v), nil
+	case int64:
+		return fmt.Sprintf("int64(%d)", v), nil
+	case float64:
+		return fmt.Sprintf("float64(%s)", strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			literal, err := goLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, literal)
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(items, ", ")), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(v))
+		for _, key := range keys {
+			literal, err := goLiteral(v[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, fmt.Sprintf("%q: %s", key, literal))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(items, ", ")), nil
+	default:
+		return "", fmt.Errorf("value of type '%T' is not supported", v)
+	}
+}
+
 type BlockedImportError struct {
 	BlockedImport string
 }
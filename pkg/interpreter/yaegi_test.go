@@ -58,6 +58,29 @@ fmt.Sprintf("foo=%s | x=%d | y=%t", foo, x, y)
 		require.Equal(t, "foo=bar | x=123 | y=true", result)
 	})
 
+	t.Run("Happy path with merged bindings containing quotes", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+import "fmt"
+fmt.Sprintf("foo=%s | x=%d", foo, x)
+`).WithBindings(map[string]interface{}{"foo": `"bar"`}).WithBindings(map[string]interface{}{"x": 123})
+		result, err := goInt.EvalString()
+		require.NoError(t, err)
+		require.Equal(t, `foo="bar" | x=123`, result)
+	})
+
+	t.Run("Happy path with structured bindings", func(t *testing.T) {
+		goInt := NewGolangInterpreter(`
+len(list) == 3 && list[1].(int64) == 2 && m["name"].(string) == "test" && m["nested"].(map[string]interface{})["pi"].(float64) == 3.14 && empty == nil
+`).WithBindings(map[string]interface{}{
+			"list":  []interface{}{"a", int64(2), true},
+			"m":     map[string]interface{}{"name": "test", "nested": map[string]interface{}{"pi": 3.14}},
+			"empty": nil,
+		})
+		result, err := goInt.EvalBool()
+		require.NoError(t, err)
+		require.True(t, result)
+	})
+
 	t.Run("Invalid boolean result", func(t *testing.T) {
 		goInt := NewGolangInterpreter(`
 "xyz"
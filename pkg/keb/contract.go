@@ -1,11 +1,16 @@
 package keb
 
+//The types of this file represent the latest contract version (see LatestContract) and are used internally
+//for all contract versions: models of older contracts are converted into these types when they are loaded.
+
 type Cluster struct {
-	Cluster      string       `json:"runtimeID"`
-	RuntimeInput RuntimeInput `json:"runtimeInput"`
-	KymaConfig   KymaConfig   `json:"kymaConfig"`
-	Metadata     Metadata     `json:"metadata"`
-	Kubeconfig   string       `json:"kubeconfig"`
+	Cluster      string            `json:"runtimeID"`
+	RuntimeInput RuntimeInput      `json:"runtimeInput"`
+	KymaConfig   KymaConfig        `json:"kymaConfig"`
+	Metadata     Metadata          `json:"metadata"`
+	Kubeconfig   string            `json:"kubeconfig"`
+	Labels       map[string]string `json:"labels,omitempty"`      //since contract version 2
+	Annotations  map[string]string `json:"annotations,omitempty"` //since contract version 2
 }
 
 type RuntimeInput struct {
@@ -14,15 +19,19 @@ type RuntimeInput struct {
 }
 
 type Configuration struct {
-	Key    string `json:"key"`
-	Value  string `json:"value"`
-	Secret bool   `json:"secret"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Secret   bool   `json:"secret"`
+	DataType string `json:"dataType,omitempty"`
 }
 
 type Components struct {
 	Component     string          `json:"component"`
 	Namespace     string          `json:"namespace"`
 	Configuration []Configuration `json:"configuration"`
+	Version       string          `json:"version,omitempty"`      //overrides the Kyma version (since contract version 2)
+	URL           string          `json:"url,omitempty"`          //URL of the component chart (since contract version 2)
+	Dependencies  []string        `json:"dependencies,omitempty"` //components which have to be reconciled before (since contract version 2)
 }
 
 type KymaConfig struct {
@@ -30,6 +39,7 @@ type KymaConfig struct {
 	Profile        string       `json:"profile"`
 	Components     []Components `json:"components"`
 	Administrators []string     `json:"administrators"`
+	Urgent         bool         `json:"urgent,omitempty"` //urgent configuration changes are applied outside of maintenance windows
 }
 
 type Metadata struct {
@@ -39,4 +49,14 @@ type Metadata struct {
 	ServicePlanID   string `json:"servicePlanID"`
 	ShootName       string `json:"shootName"`
 	InstanceID      string `json:"instanceID"`
+
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+//MaintenanceWindow defines a recurring time range in which non-urgent reconciliations are allowed
+type MaintenanceWindow struct {
+	Days     []string `json:"days"`     //weekdays (e.g. "monday" or "mon"), an empty list matches every day
+	Begin    string   `json:"begin"`    //begin of the time range in format "HH:MM"
+	End      string   `json:"end"`      //end of the time range in format "HH:MM" (if smaller than begin, range ends next day)
+	Timezone string   `json:"timezone"` //IANA timezone name (e.g. "Europe/Berlin"), UTC is used if undefined
 }
@@ -0,0 +1,86 @@
+package keb
+
+//ClusterV1 is the cluster model of contract version 1
+type ClusterV1 struct {
+	Cluster      string       `json:"runtimeID"`
+	RuntimeInput RuntimeInput `json:"runtimeInput"`
+	KymaConfig   KymaConfigV1 `json:"kymaConfig"`
+	Metadata     Metadata     `json:"metadata"`
+	Kubeconfig   string       `json:"kubeconfig"`
+}
+
+type KymaConfigV1 struct {
+	Version        string         `json:"version"`
+	Profile        string         `json:"profile"`
+	Components     []ComponentsV1 `json:"components"`
+	Administrators []string       `json:"administrators"`
+	Urgent         bool           `json:"urgent,omitempty"`
+}
+
+type ComponentsV1 struct {
+	Component     string          `json:"component"`
+	Namespace     string          `json:"namespace"`
+	Configuration []Configuration `json:"configuration"`
+}
+
+//NewClusterV1 converts a cluster into the model of contract version 1: fields introduced by later contract
+//versions are dropped
+func NewClusterV1(cluster *Cluster) *ClusterV1 {
+	components := make([]ComponentsV1, 0, len(cluster.KymaConfig.Components))
+	for i := range cluster.KymaConfig.Components {
+		components = append(components, *NewComponentsV1(&cluster.KymaConfig.Components[i]))
+	}
+	return &ClusterV1{
+		Cluster:      cluster.Cluster,
+		RuntimeInput: cluster.RuntimeInput,
+		KymaConfig: KymaConfigV1{
+			Version:        cluster.KymaConfig.Version,
+			Profile:        cluster.KymaConfig.Profile,
+			Components:     components,
+			Administrators: cluster.KymaConfig.Administrators,
+			Urgent:         cluster.KymaConfig.Urgent,
+		},
+		Metadata:   cluster.Metadata,
+		Kubeconfig: cluster.Kubeconfig,
+	}
+}
+
+//Convert returns the cluster in the latest contract version
+func (c *ClusterV1) Convert() *Cluster {
+	components := make([]Components, 0, len(c.KymaConfig.Components))
+	for i := range c.KymaConfig.Components {
+		components = append(components, *c.KymaConfig.Components[i].Convert())
+	}
+	return &Cluster{
+		Cluster:      c.Cluster,
+		RuntimeInput: c.RuntimeInput,
+		KymaConfig: KymaConfig{
+			Version:        c.KymaConfig.Version,
+			Profile:        c.KymaConfig.Profile,
+			Components:     components,
+			Administrators: c.KymaConfig.Administrators,
+			Urgent:         c.KymaConfig.Urgent,
+		},
+		Metadata:   c.Metadata,
+		Kubeconfig: c.Kubeconfig,
+	}
+}
+
+//NewComponentsV1 converts a component into the model of contract version 1: the version override,
+//chart URL and dependencies are dropped
+func NewComponentsV1(component *Components) *ComponentsV1 {
+	return &ComponentsV1{
+		Component:     component.Component,
+		Namespace:     component.Namespace,
+		Configuration: component.Configuration,
+	}
+}
+
+//Convert returns the component in the latest contract version
+func (c *ComponentsV1) Convert() *Components {
+	return &Components{
+		Component:     c.Component,
+		Namespace:     c.Namespace,
+		Configuration: c.Configuration,
+	}
+}
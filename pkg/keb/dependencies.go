@@ -0,0 +1,74 @@
+package keb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//ValidateDependencies verifies that components depend only on components of the same cluster and
+//that the dependencies are free of cycles
+func ValidateDependencies(components []*Components) error {
+	names := make(map[string]bool, len(components))
+	for _, component := range components {
+		names[component.Component] = true
+	}
+	for _, component := range components {
+		for _, dependency := range component.Dependencies {
+			if !names[dependency] {
+				return fmt.Errorf("component '%s' depends on undefined component '%s'", component.Component, dependency)
+			}
+		}
+	}
+	_, err := DependencyLevels(components)
+	return err
+}
+
+//DependencyLevels groups the components into levels: the components of a level depend only on components
+//of previous levels and can be reconciled in parallel. Dependencies to components which are not part of
+//the list are treated as fulfilled. The order of the components within a level is preserved.
+func DependencyLevels(components []*Components) ([][]*Components, error) {
+	pending := make(map[string]*Components, len(components))
+	for _, component := range components {
+		pending[component.Component] = component
+	}
+
+	var levels [][]*Components
+	for len(pending) > 0 {
+		var level []*Components
+		for _, component := range components {
+			if _, ok := pending[component.Component]; !ok {
+				continue
+			}
+			if !dependsOnAny(component, pending) {
+				level = append(level, component)
+			}
+		}
+		if len(level) == 0 {
+			return nil, fmt.Errorf("dependencies of components '%s' are cyclic", strings.Join(sortedNames(pending), "', '"))
+		}
+		for _, component := range level {
+			delete(pending, component.Component)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func dependsOnAny(component *Components, components map[string]*Components) bool {
+	for _, dependency := range component.Dependencies {
+		if _, ok := components[dependency]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedNames(components map[string]*Components) []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
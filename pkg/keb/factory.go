@@ -7,6 +7,13 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+const (
+	ContractV1 int64 = 1
+	//ContractV2 adds component version overrides, chart URLs, component dependencies and cluster labels/annotations
+	ContractV2     int64 = 2
+	LatestContract       = ContractV2
+)
+
 type ModelFactory struct {
 	version int64
 }
@@ -17,7 +24,7 @@ func NewModelFactory(contractV int64) *ModelFactory {
 
 func (mf *ModelFactory) load(model interface{}, data []byte) (interface{}, error) {
 	switch mf.version { //add here further case statement if multiple contract versions have to be supported
-	case 1:
+	case ContractV1, ContractV2:
 		err := json.Unmarshal(data, &model)
 		return model, err
 	default:
@@ -42,11 +49,23 @@ func (mf *ModelFactory) Runtime(data []byte) (*RuntimeInput, error) {
 }
 
 func (mf *ModelFactory) Cluster(data []byte) (*Cluster, error) {
+	if mf.version == ContractV1 {
+		model, err := mf.load(&ClusterV1{}, data)
+		if err != nil {
+			return nil, err
+		}
+		return model.(*ClusterV1).Convert(), nil
+	}
 	model, err := mf.load(&Cluster{}, data)
 	if err != nil {
 		return nil, err
 	}
-	return model.(*Cluster), err
+	cluster := model.(*Cluster)
+	components := make([]*Components, 0, len(cluster.KymaConfig.Components))
+	for i := range cluster.KymaConfig.Components {
+		components = append(components, &cluster.KymaConfig.Components[i])
+	}
+	return cluster, ValidateDependencies(components)
 }
 
 func (mf *ModelFactory) Components(data []byte) ([]*Components, error) {
@@ -56,9 +75,16 @@ func (mf *ModelFactory) Components(data []byte) ([]*Components, error) {
 	}
 	result := []*Components{}
 	for _, untypedModel := range untypedModels.([]interface{}) {
+		if mf.version == ContractV1 {
+			typedModel := &ComponentsV1{}
+			if err := mapstructure.Decode(untypedModel, typedModel); err != nil {
+				return result, err
+			}
+			result = append(result, typedModel.Convert())
+			continue
+		}
 		typedModel := &Components{}
-		err := mapstructure.Decode(untypedModel, typedModel)
-		if err != nil {
+		if err := mapstructure.Decode(untypedModel, typedModel); err != nil {
 			return result, err
 		}
 		result = append(result, typedModel)
@@ -80,3 +106,23 @@ func (mf *ModelFactory) Administrators(data []byte) ([]string, error) {
 	}
 	return result, err
 }
+
+func (mf *ModelFactory) Labels(data []byte) (map[string]string, error) {
+	return mf.stringMap(data)
+}
+
+func (mf *ModelFactory) Annotations(data []byte) (map[string]string, error) {
+	return mf.stringMap(data)
+}
+
+func (mf *ModelFactory) stringMap(data []byte) (map[string]string, error) {
+	model, err := mf.load(&map[string]string{}, data)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := model.(*map[string]string)
+	if mf.version == ContractV1 || !ok || *result == nil { //labels and annotations were introduced in contract version 2
+		return map[string]string{}, nil
+	}
+	return *result, nil
+}
@@ -0,0 +1,131 @@
+package keb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const clusterV2JSON = `{
+	"runtimeID": "runtime1",
+	"kubeconfig": "apiVersion: v1",
+	"labels": {"region": "eu"},
+	"annotations": {"owner": "team-a"},
+	"kymaConfig": {
+		"version": "2.0.0",
+		"components": [
+			{"component": "istio", "version": "1.11.0", "url": "https://example.com/istio.tgz"},
+			{"component": "logging", "dependencies": ["istio"]}
+		]
+	}
+}`
+
+func TestModelFactory(t *testing.T) {
+	t.Run("Load cluster of contract version 1", func(t *testing.T) {
+		cluster, err := NewModelFactory(ContractV1).Cluster([]byte(clusterV2JSON))
+		require.NoError(t, err)
+		require.Equal(t, "runtime1", cluster.Cluster)
+		require.Len(t, cluster.KymaConfig.Components, 2)
+		//fields of later contract versions are ignored
+		require.Empty(t, cluster.Labels)
+		require.Empty(t, cluster.Annotations)
+		require.Empty(t, cluster.KymaConfig.Components[0].Version)
+		require.Empty(t, cluster.KymaConfig.Components[0].URL)
+		require.Empty(t, cluster.KymaConfig.Components[1].Dependencies)
+	})
+
+	t.Run("Load cluster of contract version 2", func(t *testing.T) {
+		cluster, err := NewModelFactory(ContractV2).Cluster([]byte(clusterV2JSON))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"region": "eu"}, cluster.Labels)
+		require.Equal(t, map[string]string{"owner": "team-a"}, cluster.Annotations)
+		require.Equal(t, "1.11.0", cluster.KymaConfig.Components[0].Version)
+		require.Equal(t, "https://example.com/istio.tgz", cluster.KymaConfig.Components[0].URL)
+		require.Equal(t, []string{"istio"}, cluster.KymaConfig.Components[1].Dependencies)
+	})
+
+	t.Run("Reject invalid dependencies", func(t *testing.T) {
+		_, err := NewModelFactory(ContractV2).Cluster([]byte(`{
+			"runtimeID": "runtime1",
+			"kymaConfig": {"components": [{"component": "logging", "dependencies": ["istio"]}]}
+		}`))
+		require.Error(t, err)
+	})
+
+	t.Run("Reject unsupported contract version", func(t *testing.T) {
+		_, err := NewModelFactory(3).Cluster([]byte(clusterV2JSON))
+		require.Error(t, err)
+	})
+
+	t.Run("Load components", func(t *testing.T) {
+		data := []byte(`[{"component": "istio", "version": "1.11.0", "dependencies": ["cluster-essentials"]}]`)
+
+		componentsV1, err := NewModelFactory(ContractV1).Components(data)
+		require.NoError(t, err)
+		require.Equal(t, []*Components{{Component: "istio"}}, componentsV1)
+
+		componentsV2, err := NewModelFactory(ContractV2).Components(data)
+		require.NoError(t, err)
+		require.Equal(t, []*Components{{
+			Component:    "istio",
+			Version:      "1.11.0",
+			Dependencies: []string{"cluster-essentials"},
+		}}, componentsV2)
+	})
+
+	t.Run("Load labels", func(t *testing.T) {
+		data := []byte(`{"region": "eu"}`)
+
+		labels, err := NewModelFactory(ContractV1).Labels(data)
+		require.NoError(t, err)
+		require.Empty(t, labels)
+
+		labels, err = NewModelFactory(ContractV2).Labels(data)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"region": "eu"}, labels)
+	})
+}
+
+func TestContractConversion(t *testing.T) {
+	cluster, err := NewModelFactory(ContractV2).Cluster([]byte(clusterV2JSON))
+	require.NoError(t, err)
+
+	clusterV1 := NewClusterV1(cluster)
+	data, err := json.Marshal(clusterV1)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "labels")
+	require.NotContains(t, string(data), "dependencies")
+
+	//conversion back to the latest contract version loses only the fields of contract version 2
+	converted := clusterV1.Convert()
+	require.Equal(t, cluster.Cluster, converted.Cluster)
+	require.Equal(t, cluster.KymaConfig.Version, converted.KymaConfig.Version)
+	require.Equal(t, []Components{{Component: "istio"}, {Component: "logging"}}, converted.KymaConfig.Components)
+	require.Empty(t, converted.Labels)
+}
+
+func TestDependencyLevels(t *testing.T) {
+	components := []*Components{
+		{Component: "tracing", Dependencies: []string{"monitoring", "istio"}},
+		{Component: "monitoring", Dependencies: []string{"istio"}},
+		{Component: "logging", Dependencies: []string{"cluster-essentials"}}, //not part of the list
+		{Component: "istio"},
+	}
+
+	levels, err := DependencyLevels(components)
+	require.NoError(t, err)
+	require.Equal(t, [][]*Components{
+		{components[2], components[3]},
+		{components[1]},
+		{components[0]},
+	}, levels)
+
+	require.Error(t, ValidateDependencies(components)) //cluster-essentials is undefined
+
+	_, err = DependencyLevels([]*Components{
+		{Component: "a", Dependencies: []string{"b"}},
+		{Component: "b", Dependencies: []string{"a"}},
+	})
+	require.Error(t, err)
+}
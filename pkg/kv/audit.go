@@ -0,0 +1,60 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+)
+
+//maskedValue replaces the content of values whose key requires encryption
+const maskedValue = "********"
+
+//audit records a change of a configuration entity in the audit log: the actor of the repository
+//takes precedence over the user stored in the entity
+func (cer *Repository) audit(action audit.Action, entity, entityID, username string, before, after interface{}) error {
+	return audit.Log(cer.Conn, &audit.Entry{
+		Actor:    audit.Actor(cer.actor, username),
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+		Before:   before,
+		After:    after,
+	})
+}
+
+func keySummary(key *model.KeyEntity) interface{} {
+	if key == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"version":      key.Version,
+		"dataType":     key.DataType,
+		"encrypted":    key.Encrypted,
+		"validator":    key.Validator,
+		"trigger":      key.Trigger,
+		"triggerPhase": key.TriggerPhase,
+		"user":         key.Username,
+	}
+}
+
+func valueSummary(value *model.ValueEntity, encrypted bool) interface{} {
+	if value == nil {
+		return nil
+	}
+	content := value.Value
+	if encrypted {
+		content = maskedValue
+	}
+	return map[string]interface{}{
+		"version":    value.Version,
+		"keyVersion": value.KeyVersion,
+		"dataType":   value.DataType,
+		"value":      content,
+		"user":       value.Username,
+	}
+}
+
+func valueID(bucket, key string) string {
+	return fmt.Sprintf("%s/%s", bucket, key)
+}
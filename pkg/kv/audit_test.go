@@ -0,0 +1,90 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryAudit(t *testing.T) {
+	connFact, err := db.NewTestConnectionFactory()
+	require.NoError(t, err)
+	ceRepo, err := NewRepository(connFact, true)
+	require.NoError(t, err)
+	auditRepo, err := audit.NewRepository(connFact, true)
+	require.NoError(t, err)
+
+	auditEntries := func(entity, entityID string) []*model.AuditEntity {
+		entries, err := auditRepo.Entries(&audit.Filter{Entity: entity, EntityID: entityID})
+		require.NoError(t, err)
+		return entries
+	}
+
+	bucket := "test-audit-bucket"
+	keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+		Key:       "testAuditKey",
+		DataType:  model.String,
+		Encrypted: true,
+		Username:  "keyUser",
+	})
+	require.NoError(t, err)
+
+	t.Run("Key creation is recorded with user of the key", func(t *testing.T) {
+		entries := auditEntries(audit.EntityKey, keyEntity.Key)
+		require.Len(t, entries, 1)
+		require.Equal(t, string(audit.KeyCreate), entries[0].Action)
+		require.Equal(t, "keyUser", entries[0].Actor)
+		require.Empty(t, entries[0].BeforeSummary)
+		require.Contains(t, entries[0].AfterSummary, `"encrypted":true`)
+	})
+
+	t.Run("Value changes are recorded without secret content", func(t *testing.T) {
+		for _, val := range []string{"secret1", "secret2"} {
+			_, err := ceRepo.WithActor("operator").CreateValue(&model.ValueEntity{
+				Key:        keyEntity.Key,
+				KeyVersion: keyEntity.Version,
+				Bucket:     bucket,
+				Value:      val,
+				Username:   "valueUser",
+			})
+			require.NoError(t, err)
+		}
+		_, err := ceRepo.RestoreValue(bucket, keyEntity.Key, 1, "restoreUser")
+		require.NoError(t, err)
+
+		entries := auditEntries(audit.EntityValue, valueID(bucket, keyEntity.Key))
+		require.Len(t, entries, 3)
+		require.Equal(t, string(audit.ValueRestore), entries[0].Action)
+		require.Equal(t, "restoreUser", entries[0].Actor)
+		require.Equal(t, string(audit.ValueCreate), entries[1].Action)
+		require.Equal(t, "operator", entries[1].Actor)
+		for _, entry := range entries {
+			require.NotContains(t, entry.BeforeSummary, "secret")
+			require.NotContains(t, entry.AfterSummary, "secret")
+		}
+		require.Contains(t, entries[1].AfterSummary, maskedValue)
+	})
+
+	t.Run("Deletions are recorded", func(t *testing.T) {
+		require.NoError(t, ceRepo.WithActor("operator").DeleteValue(keyEntity.Key, bucket))
+		require.NoError(t, ceRepo.WithActor("operator").DeleteBucket(bucket))
+		require.NoError(t, ceRepo.WithActor("operator").DeleteKey(keyEntity.Key))
+
+		valueEntries := auditEntries(audit.EntityValue, valueID(bucket, keyEntity.Key))
+		require.Equal(t, string(audit.ValueDelete), valueEntries[0].Action)
+		require.Contains(t, valueEntries[0].BeforeSummary, maskedValue)
+		require.Empty(t, valueEntries[0].AfterSummary)
+
+		bucketEntries := auditEntries(audit.EntityBucket, bucket)
+		require.Len(t, bucketEntries, 1)
+		require.Equal(t, string(audit.BucketDelete), bucketEntries[0].Action)
+
+		keyEntries := auditEntries(audit.EntityKey, keyEntity.Key)
+		require.Len(t, keyEntries, 2)
+		require.Equal(t, string(audit.KeyDelete), keyEntries[0].Action)
+		require.Equal(t, "operator", keyEntries[0].Actor)
+	})
+}
@@ -3,13 +3,22 @@ package kv
 import (
 	"fmt"
 
+	"github.com/kyma-incubator/reconciler/pkg/audit"
 	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/interpreter"
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/repository"
 )
 
+//ReconciliationScheduler schedules the reconciliation of a cluster
+type ReconciliationScheduler interface {
+	MarkReconcilePending(cluster string) error
+}
+
 type Repository struct {
 	*repository.Repository
+	reconciliationScheduler ReconciliationScheduler
+	actor                   string //recorded in the audit log as originator of changes
 }
 
 func NewRepository(dbFac db.ConnectionFactory, debug bool) (*Repository, error) {
@@ -17,7 +26,33 @@ func NewRepository(dbFac db.ConnectionFactory, debug bool) (*Repository, error)
 	if err != nil {
 		return nil, err
 	}
-	return &Repository{repo}, nil
+	return &Repository{Repository: repo}, nil
+}
+
+//withConnection returns a copy of the repository which issues all queries through the given connection
+func (cer *Repository) withConnection(conn db.Connection) *Repository {
+	return &Repository{
+		Repository:              cer.Repository.WithConnection(conn),
+		reconciliationScheduler: cer.reconciliationScheduler,
+		actor:                   cer.actor,
+	}
+}
+
+//WithActor returns a copy of the repository which records the given actor as originator of all changes
+//in the audit log (if no actor is defined, the user stored in the changed entity is recorded)
+func (cer *Repository) WithActor(actor string) *Repository {
+	return &Repository{
+		Repository:              cer.Repository,
+		reconciliationScheduler: cer.reconciliationScheduler,
+		actor:                   actor,
+	}
+}
+
+//WithReconciliationScheduler defines the scheduler which is informed about clusters
+//whose cached configuration was invalidated by a configuration change
+func (cer *Repository) WithReconciliationScheduler(scheduler ReconciliationScheduler) *Repository {
+	cer.reconciliationScheduler = scheduler
+	return cer
 }
 
 func (cer *Repository) Keys() ([]*model.KeyEntity, error) {
@@ -128,8 +163,10 @@ func (cer *Repository) Key(key string, version int64) (*model.KeyEntity, error)
 }
 
 func (cer *Repository) CreateKey(key *model.KeyEntity) (*model.KeyEntity, error) {
-	q, err := db.NewQuery(cer.Conn, key)
-	if err != nil {
+	if _, err := db.NewQuery(cer.Conn, key); err != nil {
+		return nil, err
+	}
+	if err := verifyPrograms(key); err != nil {
 		return nil, err
 	}
 	existingKey, err := cer.LatestKey(key.Key)
@@ -140,19 +177,59 @@ func (cer *Repository) CreateKey(key *model.KeyEntity) (*model.KeyEntity, error)
 		cer.Logger.Debugf("No differences found for key '%s': not creating new database entity", key.Key)
 		return existingKey, nil
 	}
-	return key, q.Insert().Exec()
+	dbOps := func(tx db.Connection) error {
+		txRepo := cer.withConnection(tx)
+		q, err := db.NewQuery(txRepo.Conn, key)
+		if err != nil {
+			return err
+		}
+		if err := q.Insert().Exec(); err != nil {
+			return err
+		}
+		return txRepo.audit(audit.KeyCreate, audit.EntityKey, key.Key, key.Username, keySummary(existingKey), keySummary(key))
+	}
+	return key, cer.Transactional(dbOps)
+}
+
+//verifyPrograms ensures that the validator and trigger of the key can be prepared (e.g. they use only
+//allowed imports) before the key is stored
+func verifyPrograms(key *model.KeyEntity) error {
+	for _, code := range []string{key.Validator, key.Trigger} {
+		if code == "" {
+			continue
+		}
+		if _, err := interpreter.Prepare(code); err != nil {
+			return &InvalidKeyError{Key: key, Reason: err}
+		}
+	}
+	return nil
 }
 
 func (cer *Repository) DeleteKey(key string) error {
+	var invalidatedClusters []string
+
 	//bundle DB operations
-	dbOps := func() error {
+	dbOps := func(tx db.Connection) error {
+		txRepo := cer.withConnection(tx)
+		existingKey, err := txRepo.LatestKey(key)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return err
+		}
+
 		//delete all cache entities which were using a value of this key
-		if err := cer.CacheDep.Invalidate().WithKey(key).Exec(false); err != nil {
+		invalidation := txRepo.CacheDep.Invalidate().WithKey(key)
+		if err := invalidation.Exec(false); err != nil {
+			return err
+		}
+		invalidatedClusters = invalidation.Clusters()
+
+		//delete pending triggers of this key
+		if err := txRepo.deleteTriggers(map[string]interface{}{"Key": key}); err != nil {
 			return err
 		}
 
 		//delete the values mapped to this key
-		q, err := db.NewQuery(cer.Conn, &model.ValueEntity{})
+		q, err := db.NewQuery(txRepo.Conn, &model.ValueEntity{})
 		if err != nil {
 			return err
 		}
@@ -164,17 +241,24 @@ func (cer *Repository) DeleteKey(key string) error {
 		}
 
 		//delete the key
-		qKey, err := db.NewQuery(cer.Conn, &model.KeyEntity{})
+		qKey, err := db.NewQuery(txRepo.Conn, &model.KeyEntity{})
 		if err != nil {
 			return err
 		}
 		_, err = qKey.Delete().
 			Where(map[string]interface{}{"Key": key}).
 			Exec()
-		return err
+		if err != nil {
+			return err
+		}
+		return txRepo.audit(audit.KeyDelete, audit.EntityKey, key, "", keySummary(existingKey), nil)
 	}
 
-	return cer.Transactional(dbOps)
+	if err := cer.Transactional(dbOps); err != nil {
+		return err
+	}
+	cer.scheduleReconciliation(invalidatedClusters)
+	return nil
 }
 
 func (cer *Repository) ValuesByBucket(bucket string) ([]*model.ValueEntity, error) {
@@ -320,85 +404,328 @@ func (cer *Repository) Value(bucket, key string, version int64) (*model.ValueEnt
 	return entity.(*model.ValueEntity), nil
 }
 
+//RestoreValue creates a new version of a value which contains the content of an earlier version.
+//The restored value is mapped to the latest key and has to pass its validation.
+func (cer *Repository) RestoreValue(bucket, key string, version int64, user string) (*model.ValueEntity, error) {
+	historicValue, err := cer.Value(bucket, key, version)
+	if err != nil {
+		return nil, err
+	}
+	latestKey, err := cer.LatestKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return cer.createValue(&model.ValueEntity{
+		Bucket:     bucket,
+		Key:        key,
+		KeyVersion: latestKey.Version,
+		DataType:   latestKey.DataType,
+		Value:      historicValue.Value,
+		Username:   user,
+	}, audit.ValueRestore)
+}
+
 func (cer *Repository) CreateValue(value *model.ValueEntity) (*model.ValueEntity, error) {
+	return cer.createValue(value, audit.ValueCreate)
+}
+
+func (cer *Repository) createValue(value *model.ValueEntity, action audit.Action) (*model.ValueEntity, error) {
+	var change *valueChange
+	dbOps := func(tx db.Connection) (interface{}, error) {
+		valueEntity, txChange, err := cer.withConnection(tx).storeValue(value, action)
+		change = txChange
+		return valueEntity, err
+	}
+
+	result, err := cer.TransactionalResult(dbOps)
+	var valueEntity *model.ValueEntity
+	if result != nil {
+		valueEntity = result.(*model.ValueEntity)
+	}
+
+	if err == nil && change != nil {
+		cer.runOnChangeTrigger(change)
+		cer.scheduleReconciliation(change.invalidatedClusters)
+	}
+
+	return valueEntity, err
+}
+
+//valueChange describes a stored value and the follow-up actions which have to run after its transaction
+//was committed
+type valueChange struct {
+	key                 *model.KeyEntity
+	value               *model.ValueEntity
+	previousValue       *model.ValueEntity
+	invalidatedClusters []string
+}
+
+//storeValue validates and inserts the value using the connection of the repository (it has to be bound to
+//a transaction). The returned change is nil if the value was equal to the existing value.
+func (cer *Repository) storeValue(value *model.ValueEntity, action audit.Action) (*model.ValueEntity, *valueChange, error) {
 	existingValue, err := cer.LatestValue(value.Bucket, value.Key)
 	if err != nil && !repository.IsNotFoundError(err) {
-		return nil, err
+		return nil, nil, err
 	}
 	if existingValue != nil && existingValue.Equal(value) {
 		cer.Logger.Debugf("No differences found for value of key '%s': not creating new database entity", value.Key)
-		return existingValue, nil
+		return existingValue, nil, nil
 	}
 
 	//validate value with metadata defined in key before storing it
 	key, err := cer.Key(value.Key, value.KeyVersion)
 	if err != nil {
-		return nil, err //provided key doesn't exist
+		return nil, nil, err //provided key doesn't exist
 	}
 
 	if value.DataType == "" { //verify data-type is properly defined
 		value.DataType = key.DataType
 	} else if value.DataType != key.DataType {
-		return nil, &InvalidDataTypeError{
+		return nil, nil, &InvalidDataTypeError{
 			Key:             key,
 			InvalidDataType: value.DataType,
 		}
 	}
 
 	if err := key.Validate(value.Value); err != nil {
-		return nil, err //provided value is invalid
+		return nil, nil, err //provided value is invalid
 	}
+	value.Encrypted = key.Encrypted
 
-	//insert operation
-	dbOps := func() (interface{}, error) {
-		//add value entity
-		q, err := db.NewQuery(cer.Conn, value)
-		if err != nil {
-			return nil, err
+	//add value entity
+	q, err := db.NewQuery(cer.Conn, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := q.Insert().Exec(); err != nil {
+		return value, nil, err
+	}
+
+	//remember triggers which have to run when the affected clusters get reconciled
+	//(has to happen before the cache dependencies get dropped by the invalidation)
+	if err := cer.recordTriggers(key, value, existingValue); err != nil {
+		return value, nil, err
+	}
+
+	//a changed value invalidates the caches which were using the previous value, a new key in a bucket
+	//invalidates all caches which were using this bucket
+	invalidation := cer.CacheDep.Invalidate().WithBucket(value.Bucket)
+	if existingValue != nil {
+		invalidation.WithKey(value.Key)
+	}
+	if err := invalidation.Exec(false); err != nil {
+		return value, nil, err
+	}
+
+	//done
+	change := &valueChange{
+		key:                 key,
+		value:               value,
+		previousValue:       existingValue,
+		invalidatedClusters: invalidation.Clusters(),
+	}
+	return value, change, cer.audit(action, audit.EntityValue, valueID(value.Bucket, value.Key), value.Username,
+		valueSummary(existingValue, key.Encrypted), valueSummary(value, key.Encrypted))
+}
+
+//runOnChangeTrigger executes the trigger of a changed value if it has to run immediately
+func (cer *Repository) runOnChangeTrigger(change *valueChange) {
+	if change.key.TriggerPhase == model.TriggerOnChange {
+		cer.runTrigger(change.key, change.value, change.previousValue)
+	}
+}
+
+//scheduleReconciliation marks clusters as reconcile-pending after their cached configuration was invalidated
+func (cer *Repository) scheduleReconciliation(clusters []string) {
+	if cer.reconciliationScheduler == nil || len(clusters) == 0 {
+		return
+	}
+	for _, cluster := range clusters {
+		err := cer.reconciliationScheduler.MarkReconcilePending(cluster)
+		if err == nil {
+			cer.Logger.Debugf("Scheduled reconciliation of cluster '%s' because its cached configuration was invalidated", cluster)
+		} else if repository.IsNotFoundError(err) {
+			cer.Logger.Debugf("Cluster '%s' is not part of the inventory: no reconciliation scheduled", cluster)
+		} else {
+			cer.Logger.Warnf("Failed to schedule reconciliation of cluster '%s' after invalidating its cached configuration: %s", cluster, err)
 		}
-		valueEntity, err := value, q.Insert().Exec()
+	}
+}
+
+//recordTriggers stores a pending trigger for each cluster which is using the changed value
+func (cer *Repository) recordTriggers(key *model.KeyEntity, value, previousValue *model.ValueEntity) error {
+	if key.Trigger == "" || key.TriggerPhase == model.TriggerOnChange {
+		return nil
+	}
+
+	cacheDeps, err := cer.CacheDep.Get().WithBucket(value.Bucket).WithKey(value.Key).Exec()
+	if err != nil {
+		return err
+	}
+
+	var previousValueVersion int64
+	if previousValue != nil {
+		previousValueVersion = previousValue.Version
+	}
+
+	clusters := make(map[string]interface{}, len(cacheDeps))
+	for _, cacheDep := range cacheDeps {
+		if _, ok := clusters[cacheDep.Cluster]; ok {
+			continue
+		}
+		clusters[cacheDep.Cluster] = nil
+
+		q, err := db.NewQuery(cer.Conn, &model.TriggerEntity{
+			Cluster:              cacheDep.Cluster,
+			Bucket:               value.Bucket,
+			Key:                  value.Key,
+			KeyVersion:           key.Version,
+			Phase:                key.TriggerPhase,
+			ValueVersion:         value.Version,
+			PreviousValueVersion: previousValueVersion,
+		})
 		if err != nil {
-			return valueEntity, err
+			return err
 		}
-
-		//new value provided - invalidate caches which were using the old value
-		if err := cer.CacheDep.Invalidate().WithBucket(value.Bucket).WithKey(value.Key).Exec(false); err != nil {
-			return valueEntity, err
+		if err := q.Insert().Exec(); err != nil {
+			return err
 		}
+	}
+	cer.Logger.Debugf("Recorded %s-trigger of key '%s' for %d clusters", key.TriggerPhase, key.Key, len(clusters))
+	return nil
+}
 
-		//done
-		return valueEntity, nil
+//runTrigger evaluates the trigger of a key: failures are logged but don't influence the value change
+func (cer *Repository) runTrigger(key *model.KeyEntity, value, previousValue *model.ValueEntity) {
+	if key.Trigger == "" {
+		return
 	}
+	result, err := key.RunTrigger(value, previousValue)
+	if err != nil {
+		cer.Logger.Warnf("Trigger of key '%s' failed for value in bucket '%s': %s", key.Key, value.Bucket, err)
+		return
+	}
+	cer.Logger.Debugf("Trigger of key '%s' executed for value in bucket '%s': %s", key.Key, value.Bucket, result)
+}
 
-	result, err := cer.TransactionalResult(dbOps)
-	var valueEntity *model.ValueEntity
-	if result != nil {
-		valueEntity = result.(*model.ValueEntity)
+//PendingTriggers returns the triggers which wait for the given reconciliation phase of a cluster
+func (cer *Repository) PendingTriggers(cluster string, phase model.TriggerPhase) ([]*model.TriggerEntity, error) {
+	q, err := db.NewQuery(cer.Conn, &model.TriggerEntity{})
+	if err != nil {
+		return nil, err
+	}
+	entities, err := q.Select().
+		Where(map[string]interface{}{"Cluster": cluster, "Phase": phase}).
+		OrderBy(map[string]string{"ID": "ASC"}).
+		GetMany()
+	if err != nil {
+		return nil, err
 	}
+	//cast to specific entity
+	var result []*model.TriggerEntity
+	for _, entity := range entities {
+		result = append(result, entity.(*model.TriggerEntity))
+	}
+	return result, nil
+}
 
-	return valueEntity, err
+//ExecTriggers runs all pending triggers of a cluster for the given reconciliation phase and drops them afterwards
+func (cer *Repository) ExecTriggers(cluster string, phase model.TriggerPhase) error {
+	triggers, err := cer.PendingTriggers(cluster, phase)
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		if err := cer.execTrigger(trigger); err != nil {
+			return err
+		}
+		q, err := db.NewQuery(cer.Conn, &model.TriggerEntity{})
+		if err != nil {
+			return err
+		}
+		if _, err := q.Delete().Where(map[string]interface{}{"ID": trigger.ID}).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cer *Repository) execTrigger(trigger *model.TriggerEntity) error {
+	key, err := cer.Key(trigger.Key, trigger.KeyVersion)
+	if err != nil {
+		return cer.skipOrphanedTrigger(trigger, err)
+	}
+	value, err := cer.Value(trigger.Bucket, trigger.Key, trigger.ValueVersion)
+	if err != nil {
+		return cer.skipOrphanedTrigger(trigger, err)
+	}
+	var previousValue *model.ValueEntity
+	if trigger.PreviousValueVersion > 0 {
+		previousValue, err = cer.Value(trigger.Bucket, trigger.Key, trigger.PreviousValueVersion)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return err
+		}
+	}
+	cer.runTrigger(key, value, previousValue)
+	return nil
+}
+
+//skipOrphanedTrigger ignores triggers whose key or value no longer exists
+func (cer *Repository) skipOrphanedTrigger(trigger *model.TriggerEntity, err error) error {
+	if repository.IsNotFoundError(err) {
+		cer.Logger.Warnf("Skipping trigger '%s' because its key or value no longer exists: %s", trigger, err)
+		return nil
+	}
+	return err
 }
 
 func (cer *Repository) DeleteValue(key, bucket string) error {
+	var invalidatedClusters []string
+
 	//bundle DB operations
-	dbOps := func() error {
+	dbOps := func(tx db.Connection) error {
+		txRepo := cer.withConnection(tx)
+		existingValue, err := txRepo.LatestValue(bucket, key)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return err
+		}
+		existingKey, err := txRepo.LatestKey(key)
+		if err != nil && !repository.IsNotFoundError(err) {
+			return err
+		}
+		encrypted := existingKey == nil || existingKey.Encrypted //mask the value if the key is unknown
+
 		//delete all cache entities which were using a value of this key in this bucket
-		if err := cer.CacheDep.Invalidate().WithKey(key).WithBucket(bucket).Exec(false); err != nil {
+		invalidation := txRepo.CacheDep.Invalidate().WithKey(key).WithBucket(bucket)
+		if err := invalidation.Exec(false); err != nil {
+			return err
+		}
+		invalidatedClusters = invalidation.Clusters()
+
+		//delete pending triggers of this key in this bucket
+		if err := txRepo.deleteTriggers(map[string]interface{}{"Key": key, "Bucket": bucket}); err != nil {
 			return err
 		}
 
 		//delete the values mapped to this key in this bucket
-		q, err := db.NewQuery(cer.Conn, &model.ValueEntity{})
+		q, err := db.NewQuery(txRepo.Conn, &model.ValueEntity{})
 		if err != nil {
 			return err
 		}
 		_, err = q.Delete().
 			Where(map[string]interface{}{"Key": key, "Bucket": bucket}).
 			Exec()
-		return err
+		if err != nil {
+			return err
+		}
+		return txRepo.audit(audit.ValueDelete, audit.EntityValue, valueID(bucket, key), "", valueSummary(existingValue, encrypted), nil)
 	}
 
-	return cer.Transactional(dbOps)
+	if err := cer.Transactional(dbOps); err != nil {
+		return err
+	}
+	cer.scheduleReconciliation(invalidatedClusters)
+	return nil
 }
 
 func (cer *Repository) Buckets() ([]*model.BucketEntity, error) {
@@ -459,23 +786,50 @@ func (cer *Repository) bucketNames() ([]string, error) {
 }
 
 func (cer *Repository) DeleteBucket(bucket string) error {
-	dbOps := func() error {
+	var invalidatedClusters []string
+
+	dbOps := func(tx db.Connection) error {
+		txRepo := cer.withConnection(tx)
+
 		//invalidate all cache entities which were using values from this bucket
-		if err := cer.CacheDep.Invalidate().WithBucket(bucket).Exec(false); err != nil {
+		invalidation := txRepo.CacheDep.Invalidate().WithBucket(bucket)
+		if err := invalidation.Exec(false); err != nil {
+			return err
+		}
+		invalidatedClusters = invalidation.Clusters()
+
+		//delete pending triggers of values in this bucket
+		if err := txRepo.deleteTriggers(map[string]interface{}{"Bucket": bucket}); err != nil {
 			return err
 		}
 
 		//delete the bucket
-		q, err := db.NewQuery(cer.Conn, &model.BucketEntity{})
+		q, err := db.NewQuery(txRepo.Conn, &model.BucketEntity{})
 		if err != nil {
 			return err
 		}
 		_, err = q.Delete().
 			Where(map[string]interface{}{"Bucket": bucket}).
 			Exec()
+		if err != nil {
+			return err
+		}
+		return txRepo.audit(audit.BucketDelete, audit.EntityBucket, bucket, "", nil, nil)
+	}
+	if err := cer.Transactional(dbOps); err != nil {
+		return err
+	}
+	cer.scheduleReconciliation(invalidatedClusters)
+	return nil
+}
+
+func (cer *Repository) deleteTriggers(whereCond map[string]interface{}) error {
+	q, err := db.NewQuery(cer.Conn, &model.TriggerEntity{})
+	if err != nil {
 		return err
 	}
-	return cer.Transactional(dbOps)
+	_, err = q.Delete().Where(whereCond).Exec()
+	return err
 }
 
 func (cer *Repository) Close() error {
@@ -496,3 +850,17 @@ func IsInvalidDataTypeError(err error) bool {
 	_, ok := err.(*InvalidDataTypeError)
 	return ok
 }
+
+type InvalidKeyError struct {
+	Key    *model.KeyEntity
+	Reason error
+}
+
+func (e *InvalidKeyError) Error() string {
+	return fmt.Sprintf("Key '%s' is invalid: %s", e.Key.Key, e.Reason)
+}
+
+func IsInvalidKeyError(err error) bool {
+	_, ok := err.(*InvalidKeyError)
+	return ok
+}
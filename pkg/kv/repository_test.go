@@ -55,6 +55,25 @@ func TestRepositoryKeys(t *testing.T) {
 		}
 	})
 
+	t.Run("Reject key with invalid validator or trigger", func(t *testing.T) {
+		keyEntity := &model.KeyEntity{
+			Key:       fmt.Sprintf("testKeyInvalid-%d", ts),
+			DataType:  model.String,
+			Username:  "abc",
+			Validator: `import "os"; os.Exit(1)`,
+		}
+		_, err := ceRepo.CreateKey(keyEntity)
+		require.True(t, IsInvalidKeyError(err))
+
+		keyEntity.Validator = ""
+		keyEntity.Trigger = "go func() {}()"
+		_, err = ceRepo.CreateKey(keyEntity)
+		require.True(t, IsInvalidKeyError(err))
+
+		_, err = ceRepo.LatestKey(keyEntity.Key)
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
 	t.Run("Get keys", func(t *testing.T) {
 		//at least 2 keys have to exist
 		keyEntities, err := ceRepo.Keys()
@@ -336,6 +355,275 @@ func TestRepositoryValues(t *testing.T) {
 	})
 }
 
+func TestRepositoryRestoreValue(t *testing.T) {
+	ceRepo := newKeyValueRepo(t)
+
+	bucket := "test-restore-bucket"
+	keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+		Key:       fmt.Sprintf("testRestoreKey%d", time.Now().UnixNano()),
+		DataType:  model.Integer,
+		Validator: `it > 5`,
+		Username:  "testUsername",
+	})
+	require.NoError(t, err)
+
+	//create value in 3 versions
+	var versions []int64
+	for _, val := range []string{"10", "20", "30"} {
+		value, err := ceRepo.CreateValue(&model.ValueEntity{
+			Key:        keyEntity.Key,
+			KeyVersion: keyEntity.Version,
+			Bucket:     bucket,
+			DataType:   model.Integer,
+			Value:      val,
+			Username:   "testUsername",
+		})
+		require.NoError(t, err)
+		versions = append(versions, value.Version)
+	}
+
+	//new key version with a stricter validator
+	keyEntity, err = ceRepo.CreateKey(&model.KeyEntity{
+		Key:       keyEntity.Key,
+		DataType:  model.Integer,
+		Validator: `it > 15`,
+		Username:  "testUsername",
+	})
+	require.NoError(t, err)
+
+	t.Run("Restore value", func(t *testing.T) {
+		restored, err := ceRepo.RestoreValue(bucket, keyEntity.Key, versions[1], "restoreUsername")
+		require.NoError(t, err)
+		require.Equal(t, "20", restored.Value)
+		require.Equal(t, "restoreUsername", restored.Username)
+		require.Equal(t, keyEntity.Version, restored.KeyVersion)
+		require.Greater(t, restored.Version, versions[2])
+
+		latest, err := ceRepo.LatestValue(bucket, keyEntity.Key)
+		require.NoError(t, err)
+		require.Equal(t, restored.Version, latest.Version)
+	})
+
+	t.Run("Restore value rejected by latest key validator", func(t *testing.T) {
+		_, err := ceRepo.RestoreValue(bucket, keyEntity.Key, versions[0], "restoreUsername")
+		require.Error(t, err)
+		require.True(t, model.IsInvalidValueError(err))
+	})
+
+	t.Run("Restore non-existing value", func(t *testing.T) {
+		_, err := ceRepo.RestoreValue(bucket, keyEntity.Key, -1, "restoreUsername")
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
+	require.NoError(t, ceRepo.DeleteKey(keyEntity.Key))
+}
+
+func TestRepositoryEncryptedValues(t *testing.T) {
+	ceRepo := newKeyValueRepo(t)
+
+	bucket := "test-encryption-bucket"
+	keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+		Key:       fmt.Sprintf("testEncryptedKey%d", time.Now().UnixNano()),
+		DataType:  model.String,
+		Encrypted: true,
+		Username:  "testUsername",
+	})
+	require.NoError(t, err)
+
+	valueEntity, err := ceRepo.CreateValue(&model.ValueEntity{
+		Key:        keyEntity.Key,
+		KeyVersion: keyEntity.Version,
+		Bucket:     bucket,
+		Value:      "secretValue",
+		Username:   "testUsername",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "secretValue", valueEntity.Value)
+	require.NotContains(t, valueEntity.String(), "secretValue")
+
+	//value is stored encrypted
+	rows, err := ceRepo.Conn.Query("SELECT value FROM config_values WHERE key=$1", keyEntity.Key)
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	var storedValue string
+	require.NoError(t, rows.Scan(&storedValue))
+	require.NoError(t, rows.Close())
+	require.NotEqual(t, "secretValue", storedValue)
+	require.True(t, ceRepo.Conn.Encryptor().Decryptable(storedValue))
+
+	//value is decrypted when read
+	latestValue, err := ceRepo.LatestValue(bucket, keyEntity.Key)
+	require.NoError(t, err)
+	require.Equal(t, "secretValue", latestValue.Value)
+	require.True(t, latestValue.Encrypted)
+
+	require.NoError(t, ceRepo.DeleteKey(keyEntity.Key))
+}
+
+func TestRepositoryTriggers(t *testing.T) {
+	ceRepo := newKeyValueRepo(t)
+
+	ts := time.Now().UnixNano()
+	bucket := "test-trigger-bucket"
+	cluster := fmt.Sprintf("triggerCluster%d", ts)
+
+	//create key with a post-reconciliation trigger
+	keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+		Key:          fmt.Sprintf("testTriggerKey%d", ts),
+		DataType:     model.Integer,
+		Username:     "testUsername",
+		Trigger:      `newValue > oldValue`,
+		TriggerPhase: model.TriggerPostReconciliation,
+	})
+	require.NoError(t, err)
+
+	//create initial value and a cache entry which depends on it
+	value, err := ceRepo.CreateValue(&model.ValueEntity{
+		Key:        keyEntity.Key,
+		KeyVersion: keyEntity.Version,
+		Bucket:     bucket,
+		Value:      "1",
+		Username:   "testUsername",
+	})
+	require.NoError(t, err)
+
+	addCacheEntry := func(t *testing.T, value *model.ValueEntity) {
+		cacheEntry := &model.CacheEntryEntity{
+			Label:   "triggerLabel",
+			Cluster: cluster,
+			Data:    value.Value,
+		}
+		q, err := db.NewQuery(ceRepo.Conn, cacheEntry)
+		require.NoError(t, err)
+		require.NoError(t, q.Insert().Exec())
+		require.NoError(t, ceRepo.CacheDep.Record(cacheEntry, []*model.ValueEntity{value}).Exec(true))
+	}
+	addCacheEntry(t, value)
+
+	t.Run("Record trigger for affected cluster", func(t *testing.T) {
+		newValue, err := ceRepo.CreateValue(&model.ValueEntity{
+			Key:        keyEntity.Key,
+			KeyVersion: keyEntity.Version,
+			Bucket:     bucket,
+			Value:      "2",
+			Username:   "testUsername",
+		})
+		require.NoError(t, err)
+
+		triggers, err := ceRepo.PendingTriggers(cluster, model.TriggerPostReconciliation)
+		require.NoError(t, err)
+		require.Len(t, triggers, 1)
+		require.Equal(t, keyEntity.Key, triggers[0].Key)
+		require.Equal(t, bucket, triggers[0].Bucket)
+		require.Equal(t, newValue.Version, triggers[0].ValueVersion)
+		require.Equal(t, value.Version, triggers[0].PreviousValueVersion)
+
+		//cache entry was invalidated by the value change
+		addCacheEntry(t, newValue)
+
+		triggers, err = ceRepo.PendingTriggers(cluster, model.TriggerPreReconciliation)
+		require.NoError(t, err)
+		require.Empty(t, triggers)
+	})
+
+	t.Run("Execute pending triggers", func(t *testing.T) {
+		require.NoError(t, ceRepo.ExecTriggers(cluster, model.TriggerPostReconciliation))
+
+		triggers, err := ceRepo.PendingTriggers(cluster, model.TriggerPostReconciliation)
+		require.NoError(t, err)
+		require.Empty(t, triggers)
+	})
+
+	t.Run("Delete key drops pending triggers", func(t *testing.T) {
+		_, err := ceRepo.CreateValue(&model.ValueEntity{
+			Key:        keyEntity.Key,
+			KeyVersion: keyEntity.Version,
+			Bucket:     bucket,
+			Value:      "3",
+			Username:   "testUsername",
+		})
+		require.NoError(t, err)
+
+		triggers, err := ceRepo.PendingTriggers(cluster, model.TriggerPostReconciliation)
+		require.NoError(t, err)
+		require.Len(t, triggers, 1)
+
+		require.NoError(t, ceRepo.DeleteKey(keyEntity.Key))
+
+		triggers, err = ceRepo.PendingTriggers(cluster, model.TriggerPostReconciliation)
+		require.NoError(t, err)
+		require.Empty(t, triggers)
+	})
+}
+
+type reconciliationSchedulerStub struct {
+	clusters []string
+}
+
+func (s *reconciliationSchedulerStub) MarkReconcilePending(cluster string) error {
+	s.clusters = append(s.clusters, cluster)
+	return nil
+}
+
+func TestRepositoryReconciliationScheduling(t *testing.T) {
+	schedulerStub := &reconciliationSchedulerStub{}
+	ceRepo := newKeyValueRepo(t).WithReconciliationScheduler(schedulerStub)
+
+	ts := time.Now().UnixNano()
+	bucket := "test-scheduling-bucket"
+	cluster := fmt.Sprintf("schedulingCluster%d", ts)
+
+	keyEntity, err := ceRepo.CreateKey(&model.KeyEntity{
+		Key:      fmt.Sprintf("testSchedulingKey%d", ts),
+		DataType: model.String,
+		Username: "testUsername",
+	})
+	require.NoError(t, err)
+
+	createValue := func(t *testing.T, value string) *model.ValueEntity {
+		valueEntity, err := ceRepo.CreateValue(&model.ValueEntity{
+			Key:        keyEntity.Key,
+			KeyVersion: keyEntity.Version,
+			Bucket:     bucket,
+			Value:      value,
+			Username:   "testUsername",
+		})
+		require.NoError(t, err)
+		return valueEntity
+	}
+
+	addCacheEntry := func(t *testing.T, value *model.ValueEntity) {
+		cacheEntry := &model.CacheEntryEntity{
+			Label:   "schedulingLabel",
+			Cluster: cluster,
+			Data:    value.Value,
+		}
+		q, err := db.NewQuery(ceRepo.Conn, cacheEntry)
+		require.NoError(t, err)
+		require.NoError(t, q.Insert().Exec())
+		require.NoError(t, ceRepo.CacheDep.Record(cacheEntry, []*model.ValueEntity{value}).Exec(true))
+	}
+
+	t.Run("Value without cache dependencies schedules no reconciliation", func(t *testing.T) {
+		addCacheEntry(t, createValue(t, "value1"))
+		require.Empty(t, schedulerStub.clusters)
+	})
+
+	t.Run("Changed value schedules reconciliation of affected cluster", func(t *testing.T) {
+		value := createValue(t, "value2")
+		require.Equal(t, []string{cluster}, schedulerStub.clusters)
+		addCacheEntry(t, value)
+	})
+
+	t.Run("Deleted value schedules reconciliation of affected cluster", func(t *testing.T) {
+		schedulerStub.clusters = nil
+		require.NoError(t, ceRepo.DeleteValue(keyEntity.Key, bucket))
+		require.Equal(t, []string{cluster}, schedulerStub.clusters)
+	})
+
+	require.NoError(t, ceRepo.DeleteKey(keyEntity.Key))
+}
+
 func newKeyValueRepo(t *testing.T) *Repository {
 	connFact, err := db.NewTestConnectionFactory()
 	require.NoError(t, err)
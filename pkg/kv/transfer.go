@@ -0,0 +1,315 @@
+package kv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/audit"
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/repository"
+	"sigs.k8s.io/yaml"
+)
+
+//ImportAction describes how an imported key or value is applied to the configuration management
+type ImportAction string
+
+const (
+	ImportCreate     ImportAction = "create"      //key or value doesn't exist yet
+	ImportNewVersion ImportAction = "new-version" //key or value exists but has changed
+	ImportUnchanged  ImportAction = "unchanged"   //key or value exists and is equal
+)
+
+//Document contains keys and bucket values in an exchangeable format (used to move
+//configuration between landscapes)
+type Document struct {
+	Keys    []*DocumentKey    `json:"keys,omitempty"`
+	Buckets []*DocumentBucket `json:"buckets,omitempty"`
+}
+
+type DocumentKey struct {
+	Key          string `json:"key"`
+	DataType     string `json:"dataType"`
+	Encrypted    bool   `json:"encrypted,omitempty"`
+	Validator    string `json:"validator,omitempty"`
+	Trigger      string `json:"trigger,omitempty"`
+	TriggerPhase string `json:"triggerPhase,omitempty"`
+}
+
+type DocumentBucket struct {
+	Bucket string           `json:"bucket"`
+	Values []*DocumentValue `json:"values,omitempty"`
+}
+
+type DocumentValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+//NewDocument parses a YAML or JSON document
+func NewDocument(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.UnmarshalStrict(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+//YAML returns the document as YAML
+func (d *Document) YAML() ([]byte, error) {
+	return yaml.Marshal(d)
+}
+
+type ImportedKey struct {
+	Key    *model.KeyEntity
+	Action ImportAction
+}
+
+type ImportedValue struct {
+	Value  *model.ValueEntity
+	Action ImportAction
+}
+
+//ImportResult lists the keys and values of an import and how they were (or would be) applied
+type ImportResult struct {
+	Keys   []*ImportedKey
+	Values []*ImportedValue
+}
+
+//ImportError contains all issues detected while verifying a document before its import
+type ImportError struct {
+	Issues []string
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("Import rejected because of %d issues:\n%s", len(e.Issues), strings.Join(e.Issues, "\n"))
+}
+
+func IsImportError(err error) bool {
+	_, ok := err.(*ImportError)
+	return ok
+}
+
+//Export returns the latest version of all keys and the latest values of the given buckets
+//(if no bucket is given, all buckets are exported)
+func (cer *Repository) Export(buckets ...string) (*Document, error) {
+	doc := &Document{}
+
+	keys, err := cer.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		doc.Keys = append(doc.Keys, &DocumentKey{
+			Key:          key.Key,
+			DataType:     string(key.DataType),
+			Encrypted:    key.Encrypted,
+			Validator:    key.Validator,
+			Trigger:      key.Trigger,
+			TriggerPhase: string(key.TriggerPhase),
+		})
+	}
+
+	if len(buckets) == 0 {
+		if buckets, err = cer.bucketNames(); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(buckets)
+	for _, bucket := range buckets {
+		values, err := cer.ValuesByBucket(bucket)
+		if err != nil {
+			return nil, err
+		}
+		docBucket := &DocumentBucket{Bucket: bucket}
+		for _, value := range values {
+			docBucket.Values = append(docBucket.Values, &DocumentValue{
+				Key:   value.Key,
+				Value: value.Value,
+			})
+		}
+		doc.Buckets = append(doc.Buckets, docBucket)
+	}
+
+	return doc, nil
+}
+
+//Import creates the keys and values of the document. Keys and values which differ from the existing
+//entities are created as new version. All values are validated before anything is written: if an issue
+//is detected, an ImportError is returned and nothing gets imported. Keys and values are written within
+//one transaction which is rolled back if any write fails. With dryRun, the import result
+//is calculated without writing anything.
+func (cer *Repository) Import(doc *Document, user string, dryRun bool) (*ImportResult, error) {
+	result, err := cer.planImport(doc, user)
+	if err != nil || dryRun {
+		return result, err
+	}
+
+	//all keys and values are written within one transaction: triggers and reconciliations are
+	//only executed after it was committed
+	var changes []*valueChange
+	dbOps := func(tx db.Connection) error {
+		txRepo := cer.withConnection(tx)
+		changes = nil
+
+		//keys have to be created first: values are mapped to the created key versions
+		keyVersions := make(map[string]int64, len(result.Keys))
+		for _, importedKey := range result.Keys {
+			if importedKey.Action != ImportUnchanged {
+				key, err := txRepo.CreateKey(importedKey.Key)
+				if err != nil {
+					return err
+				}
+				importedKey.Key = key
+			}
+			keyVersions[importedKey.Key.Key] = importedKey.Key.Version
+		}
+
+		for _, importedValue := range result.Values {
+			if importedValue.Action == ImportUnchanged {
+				continue
+			}
+			if version, ok := keyVersions[importedValue.Value.Key]; ok {
+				importedValue.Value.KeyVersion = version
+			}
+			value, change, err := txRepo.storeValue(importedValue.Value, audit.ValueCreate)
+			if err != nil {
+				return err
+			}
+			importedValue.Value = value
+			if change != nil {
+				changes = append(changes, change)
+			}
+		}
+		return nil
+	}
+	if err := cer.Transactional(dbOps); err != nil {
+		return result, err
+	}
+
+	var invalidatedClusters []string
+	scheduled := make(map[string]bool)
+	for _, change := range changes {
+		cer.runOnChangeTrigger(change)
+		for _, cluster := range change.invalidatedClusters {
+			if !scheduled[cluster] {
+				scheduled[cluster] = true
+				invalidatedClusters = append(invalidatedClusters, cluster)
+			}
+		}
+	}
+	cer.scheduleReconciliation(invalidatedClusters)
+
+	return result, nil
+}
+
+//planImport verifies the document and determines for each key and value how it has to be imported
+func (cer *Repository) planImport(doc *Document, user string) (*ImportResult, error) {
+	result := &ImportResult{}
+	importErr := &ImportError{}
+
+	//verify keys
+	keys := make(map[string]*model.KeyEntity, len(doc.Keys))
+	for _, docKey := range doc.Keys {
+		if _, ok := keys[docKey.Key]; ok {
+			importErr.Issues = append(importErr.Issues, fmt.Sprintf("Key '%s' is defined multiple times", docKey.Key))
+			continue
+		}
+		key, action, err := cer.planKey(docKey, user)
+		if err != nil {
+			importErr.Issues = append(importErr.Issues, fmt.Sprintf("Key '%s' is invalid: %s", docKey.Key, err))
+			continue
+		}
+		keys[key.Key] = key
+		result.Keys = append(result.Keys, &ImportedKey{Key: key, Action: action})
+	}
+
+	//verify values
+	for _, docBucket := range doc.Buckets {
+		if err := model.ValidateBucketName(docBucket.Bucket); err != nil {
+			importErr.Issues = append(importErr.Issues, err.Error())
+			continue
+		}
+		for _, docValue := range docBucket.Values {
+			key, ok := keys[docValue.Key]
+			if !ok { //key isn't part of the document: use the existing key
+				existingKey, err := cer.LatestKey(docValue.Key)
+				if err != nil {
+					importErr.Issues = append(importErr.Issues,
+						fmt.Sprintf("Key '%s' of value in bucket '%s' is undefined: %s", docValue.Key, docBucket.Bucket, err))
+					continue
+				}
+				key = existingKey
+			}
+			if err := key.Validate(docValue.Value); err != nil {
+				importErr.Issues = append(importErr.Issues,
+					fmt.Sprintf("Value of key '%s' in bucket '%s' is invalid: %s", docValue.Key, docBucket.Bucket, err))
+				continue
+			}
+			value, action, err := cer.planValue(docBucket.Bucket, key, docValue, user)
+			if err != nil {
+				return nil, err
+			}
+			result.Values = append(result.Values, &ImportedValue{Value: value, Action: action})
+		}
+	}
+
+	if len(importErr.Issues) > 0 {
+		return nil, importErr
+	}
+	return result, nil
+}
+
+func (cer *Repository) planKey(docKey *DocumentKey, user string) (*model.KeyEntity, ImportAction, error) {
+	dataType, err := model.NewDataType(docKey.DataType)
+	if err != nil {
+		return nil, "", err
+	}
+	triggerPhase, err := model.NewTriggerPhase(docKey.TriggerPhase)
+	if err != nil {
+		return nil, "", err
+	}
+	key := &model.KeyEntity{
+		Key:          docKey.Key,
+		DataType:     dataType,
+		Encrypted:    docKey.Encrypted,
+		Validator:    docKey.Validator,
+		Trigger:      docKey.Trigger,
+		TriggerPhase: triggerPhase,
+		Username:     user,
+	}
+	existingKey, err := cer.LatestKey(docKey.Key)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return key, ImportCreate, nil
+		}
+		return nil, "", err
+	}
+	if existingKey.Equal(key) {
+		return existingKey, ImportUnchanged, nil
+	}
+	return key, ImportNewVersion, nil
+}
+
+func (cer *Repository) planValue(bucket string, key *model.KeyEntity, docValue *DocumentValue, user string) (*model.ValueEntity, ImportAction, error) {
+	value := &model.ValueEntity{
+		Bucket:     bucket,
+		Key:        key.Key,
+		KeyVersion: key.Version, //is 0 if the key will be created by the import
+		DataType:   key.DataType,
+		Value:      docValue.Value,
+		Username:   user,
+	}
+	existingValue, err := cer.LatestValue(bucket, key.Key)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return value, ImportCreate, nil
+		}
+		return nil, "", err
+	}
+	if existingValue.Equal(value) {
+		return existingValue, ImportUnchanged, nil
+	}
+	return value, ImportNewVersion, nil
+}
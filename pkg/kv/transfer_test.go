@@ -0,0 +1,170 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryImportExport(t *testing.T) {
+	ceRepo := newKeyValueRepo(t)
+
+	ts := time.Now().UnixNano()
+	bucket := fmt.Sprintf("test-import-%d", ts)
+	intKey := fmt.Sprintf("testImportIntKey%d", ts)
+	strKey := fmt.Sprintf("testImportStrKey%d", ts)
+
+	doc, err := NewDocument([]byte(fmt.Sprintf(`
+keys:
+- key: %s
+  dataType: integer
+  validator: it > 5
+  triggerPhase: post-reconciliation
+- key: %s
+  dataType: string
+buckets:
+- bucket: %s
+  values:
+  - key: %s
+    value: "10"
+  - key: %s
+    value: abc
+`, intKey, strKey, bucket, intKey, strKey)))
+	require.NoError(t, err)
+
+	t.Run("Dry-run import", func(t *testing.T) {
+		result, err := ceRepo.Import(doc, "testUsername", true)
+		require.NoError(t, err)
+		require.Len(t, result.Keys, 2)
+		require.Len(t, result.Values, 2)
+		for _, key := range result.Keys {
+			require.Equal(t, ImportCreate, key.Action)
+		}
+		for _, value := range result.Values {
+			require.Equal(t, ImportCreate, value.Action)
+		}
+
+		//nothing was written
+		_, err = ceRepo.LatestKey(intKey)
+		require.Error(t, err)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		_, err := ceRepo.Import(doc, "testUsername", false)
+		require.NoError(t, err)
+
+		key, err := ceRepo.LatestKey(intKey)
+		require.NoError(t, err)
+		require.Equal(t, model.TriggerPostReconciliation, key.TriggerPhase)
+
+		value, err := ceRepo.LatestValue(bucket, intKey)
+		require.NoError(t, err)
+		require.Equal(t, "10", value.Value)
+		require.Equal(t, key.Version, value.KeyVersion)
+	})
+
+	t.Run("Import changed document", func(t *testing.T) {
+		doc.Keys[1].Validator = `len(it) > 1`
+		doc.Buckets[0].Values[0].Value = "20"
+
+		result, err := ceRepo.Import(doc, "testUsername", false)
+		require.NoError(t, err)
+		require.Equal(t, ImportUnchanged, result.Keys[0].Action)
+		require.Equal(t, ImportNewVersion, result.Keys[1].Action)
+		require.Equal(t, ImportNewVersion, result.Values[0].Action) //value changed
+		require.Equal(t, ImportNewVersion, result.Values[1].Action) //key version changed
+
+		key, err := ceRepo.LatestKey(strKey)
+		require.NoError(t, err)
+		value, err := ceRepo.LatestValue(bucket, strKey)
+		require.NoError(t, err)
+		require.Equal(t, key.Version, value.KeyVersion)
+
+		//re-import doesn't create new versions
+		result, err = ceRepo.Import(doc, "testUsername", false)
+		require.NoError(t, err)
+		for _, key := range result.Keys {
+			require.Equal(t, ImportUnchanged, key.Action)
+		}
+		for _, value := range result.Values {
+			require.Equal(t, ImportUnchanged, value.Action)
+		}
+	})
+
+	t.Run("Reject invalid document", func(t *testing.T) {
+		doc.Buckets[0].Values[0].Value = "3"   //fails validator
+		doc.Buckets[0].Values[1].Value = "new" //would be valid
+		doc.Buckets = append(doc.Buckets, &DocumentBucket{
+			Bucket: bucket,
+			Values: []*DocumentValue{{Key: "undefinedKey", Value: "abc"}},
+		})
+
+		_, err := ceRepo.Import(doc, "testUsername", false)
+		require.Error(t, err)
+		require.True(t, IsImportError(err))
+		require.Len(t, err.(*ImportError).Issues, 2)
+
+		//nothing was written
+		value, err := ceRepo.LatestValue(bucket, strKey)
+		require.NoError(t, err)
+		require.Equal(t, "abc", value.Value)
+	})
+
+	t.Run("Failed write rolls back the import", func(t *testing.T) {
+		if ceRepo.Conn.Type() != db.SQLite {
+			t.Skip("failure is injected with a SQLite trigger")
+		}
+		failKey := fmt.Sprintf("testImportFailKey%d", ts)
+		_, err := ceRepo.Conn.Exec(fmt.Sprintf(`CREATE TRIGGER test_import_failure BEFORE INSERT ON config_values
+			WHEN NEW."key" = '%s' BEGIN SELECT RAISE(ABORT, 'injected failure'); END`, failKey))
+		require.NoError(t, err)
+		defer func() {
+			_, err := ceRepo.Conn.Exec("DROP TRIGGER test_import_failure")
+			require.NoError(t, err)
+		}()
+
+		failDoc := &Document{
+			Keys:    []*DocumentKey{{Key: failKey, DataType: "string"}},
+			Buckets: []*DocumentBucket{{Bucket: bucket, Values: []*DocumentValue{{Key: failKey, Value: "abc"}}}},
+		}
+		_, err = ceRepo.Import(failDoc, "testUsername", false)
+		require.Error(t, err)
+
+		//key was created before the value insert failed
+		_, err = ceRepo.LatestKey(failKey)
+		require.True(t, repository.IsNotFoundError(err))
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		exported, err := ceRepo.Export(bucket)
+		require.NoError(t, err)
+
+		var keys []string
+		for _, key := range exported.Keys {
+			if key.Key == intKey || key.Key == strKey {
+				keys = append(keys, key.Key)
+			}
+		}
+		require.ElementsMatch(t, []string{intKey, strKey}, keys)
+
+		require.Len(t, exported.Buckets, 1)
+		require.Equal(t, bucket, exported.Buckets[0].Bucket)
+		require.Equal(t, []*DocumentValue{{Key: intKey, Value: "20"}, {Key: strKey, Value: "abc"}}, exported.Buckets[0].Values)
+
+		//exported document can be parsed again
+		data, err := exported.YAML()
+		require.NoError(t, err)
+		parsed, err := NewDocument(data)
+		require.NoError(t, err)
+		require.Equal(t, exported, parsed)
+	})
+
+	require.NoError(t, ceRepo.DeleteBucket(bucket))
+	require.NoError(t, ceRepo.DeleteKey(intKey))
+	require.NoError(t, ceRepo.DeleteKey(strKey))
+}
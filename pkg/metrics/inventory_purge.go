@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InventoryPurgeCollector provides the following metrics:
+// - reconciler_inventory_purged_rows_total{"table"}
+// This counter shows the amount of rows which were removed by the inventory retention job.
+type InventoryPurgeCollector struct {
+	purgedRowsCounter *prometheus.CounterVec
+}
+
+func NewInventoryPurgeCollector() *InventoryPurgeCollector {
+	collector := &InventoryPurgeCollector{
+		purgedRowsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: prometheusSubsystem,
+			Name:      "inventory_purged_rows_total",
+			Help:      "Amount of inventory rows removed by the retention job",
+		}, []string{"table"}),
+	}
+	prometheus.MustRegister(collector)
+	return collector
+}
+
+func (c *InventoryPurgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.purgedRowsCounter.Describe(ch)
+}
+
+func (c *InventoryPurgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.purgedRowsCounter.Collect(ch)
+}
+
+func (c *InventoryPurgeCollector) OnInventoryPurge(result *cluster.PurgeResult) {
+	c.purgedRowsCounter.WithLabelValues("inventory_clusters").Add(float64(result.Clusters))
+	c.purgedRowsCounter.WithLabelValues("inventory_cluster_configs").Add(float64(result.Configurations))
+	c.purgedRowsCounter.WithLabelValues("inventory_cluster_config_statuses").Add(float64(result.Statuses))
+	c.purgedRowsCounter.WithLabelValues("inventory_cluster_config_rollbacks").Add(float64(result.Rollbacks))
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+)
+
+const tblAuditLog string = "audit_log"
+
+type AuditEntity struct {
+	ID            int64     `db:"readOnly"`
+	Actor         string    `db:"notNull"`
+	Action        string    `db:"notNull"`
+	Entity        string    `db:"notNull"`
+	EntityID      string    `db:"notNull"`
+	BeforeSummary string    //summary of the entity before the change (empty if it was created)
+	AfterSummary  string    //summary of the entity after the change (empty if it was deleted)
+	Created       time.Time `db:"readOnly"`
+}
+
+func (a *AuditEntity) String() string {
+	return fmt.Sprintf("AuditEntity [Actor=%s,Action=%s,Entity=%s,EntityID=%s]",
+		a.Actor, a.Action, a.Entity, a.EntityID)
+}
+
+func (a *AuditEntity) New() db.DatabaseEntity {
+	return &AuditEntity{}
+}
+
+func (a *AuditEntity) Marshaller() *db.EntityMarshaller {
+	marshaller := db.NewEntityMarshaller(&a)
+	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	return marshaller
+}
+
+func (a *AuditEntity) Table() string {
+	return tblAuditLog
+}
+
+func (a *AuditEntity) Equal(other db.DatabaseEntity) bool {
+	if other == nil {
+		return false
+	}
+	otherAudit, ok := other.(*AuditEntity)
+	if ok {
+		return a.Actor == otherAudit.Actor &&
+			a.Action == otherAudit.Action &&
+			a.Entity == otherAudit.Entity &&
+			a.EntityID == otherAudit.EntityID &&
+			a.BeforeSummary == otherAudit.BeforeSummary &&
+			a.AfterSummary == otherAudit.AfterSummary
+	}
+	return false
+}
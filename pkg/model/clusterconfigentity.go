@@ -19,6 +19,7 @@ type ClusterConfigurationEntity struct {
 	Components     string `db:"notNull"`
 	Administrators string
 	Contract       int64     `db:"notNull"`
+	Urgent         bool      `db:"notNull"`
 	Deleted        bool      `db:"notNull"`
 	Created        time.Time `db:"readOnly"`
 }
@@ -35,6 +36,8 @@ func (c *ClusterConfigurationEntity) New() db.DatabaseEntity {
 func (c *ClusterConfigurationEntity) Marshaller() *db.EntityMarshaller {
 	marshaller := db.NewEntityMarshaller(&c)
 	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	marshaller.AddEncryptionMarshaller("Components", encryptSecretConfiguration)
+	marshaller.AddEncryptionUnmarshaller("Components", decryptSecretConfiguration)
 	return marshaller
 }
 
@@ -54,7 +57,8 @@ func (c *ClusterConfigurationEntity) Equal(other db.DatabaseEntity) bool {
 			c.KymaProfile == otherClProp.KymaProfile &&
 			c.Components == otherClProp.Components &&
 			c.Administrators == otherClProp.Administrators &&
-			c.Contract == otherClProp.Contract
+			c.Contract == otherClProp.Contract &&
+			c.Urgent == otherClProp.Urgent
 	}
 	return false
 }
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+)
+
+const tblConfigRollbacks string = "inventory_cluster_config_rollbacks"
+
+type ClusterConfigRollbackEntity struct {
+	ID                  int64     `db:"readOnly"`
+	Cluster             string    `db:"notNull"`
+	ConfigVersion       int64     `db:"notNull"`
+	SourceConfigVersion int64     `db:"notNull"`
+	Username            string    `db:"notNull"`
+	Created             time.Time `db:"readOnly"`
+}
+
+func (c *ClusterConfigRollbackEntity) String() string {
+	return fmt.Sprintf("ClusterConfigRollbackEntity [Cluster=%s,ConfigVersion=%d,SourceConfigVersion=%d,User=%s]",
+		c.Cluster, c.ConfigVersion, c.SourceConfigVersion, c.Username)
+}
+
+func (c *ClusterConfigRollbackEntity) New() db.DatabaseEntity {
+	return &ClusterConfigRollbackEntity{}
+}
+
+func (c *ClusterConfigRollbackEntity) Marshaller() *db.EntityMarshaller {
+	marshaller := db.NewEntityMarshaller(&c)
+	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	return marshaller
+}
+
+func (c *ClusterConfigRollbackEntity) Table() string {
+	return tblConfigRollbacks
+}
+
+func (c *ClusterConfigRollbackEntity) Equal(other db.DatabaseEntity) bool {
+	if other == nil {
+		return false
+	}
+	otherRollback, ok := other.(*ClusterConfigRollbackEntity)
+	if ok {
+		return c.Cluster == otherRollback.Cluster &&
+			c.ConfigVersion == otherRollback.ConfigVersion &&
+			c.SourceConfigVersion == otherRollback.SourceConfigVersion &&
+			c.Username == otherRollback.Username
+	}
+	return false
+}
@@ -11,14 +11,16 @@ import (
 const tblCluster string = "inventory_clusters"
 
 type ClusterEntity struct {
-	Version    int64     `db:"readOnly"`
-	Cluster    string    `db:"notNull"`
-	Runtime    string    `db:"notNull"`
-	Metadata   string    `db:"notNull"`
-	Kubeconfig string    `db:"notNull,encrypt"`
-	Contract   int64     `db:"notNull"`
-	Deleted    bool      `db:"notNull"`
-	Created    time.Time `db:"readOnly"`
+	Version     int64     `db:"readOnly"`
+	Cluster     string    `db:"notNull"`
+	Runtime     string    `db:"notNull"`
+	Metadata    string    `db:"notNull"`
+	Kubeconfig  string    `db:"notNull,encrypt"`
+	Contract    int64     `db:"notNull"`
+	Deleted     bool      `db:"notNull"`
+	Created     time.Time `db:"readOnly"`
+	Labels      string    //JSON encoded, empty if the cluster has no labels (since contract version 2)
+	Annotations string    //JSON encoded, empty if the cluster has no annotations (since contract version 2)
 }
 
 func (c *ClusterEntity) String() string {
@@ -49,7 +51,9 @@ func (c *ClusterEntity) Equal(other db.DatabaseEntity) bool {
 		return c.Cluster == otherClProp.Cluster &&
 			c.Runtime == otherClProp.Runtime &&
 			c.Metadata == otherClProp.Metadata &&
-			c.Contract == otherClProp.Contract
+			c.Contract == otherClProp.Contract &&
+			c.Labels == otherClProp.Labels &&
+			c.Annotations == otherClProp.Annotations
 	}
 	return false
 }
@@ -67,3 +71,17 @@ func (c *ClusterEntity) GetMetadata() (*keb.Metadata, error) {
 	}
 	return keb.NewModelFactory(c.Contract).Metadata([]byte(c.Metadata))
 }
+
+func (c *ClusterEntity) GetLabels() (map[string]string, error) {
+	if c.Labels == "" {
+		return map[string]string{}, nil
+	}
+	return keb.NewModelFactory(c.Contract).Labels([]byte(c.Labels))
+}
+
+func (c *ClusterEntity) GetAnnotations() (map[string]string, error) {
+	if c.Annotations == "" {
+		return map[string]string{}, nil
+	}
+	return keb.NewModelFactory(c.Contract).Annotations([]byte(c.Annotations))
+}
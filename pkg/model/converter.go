@@ -1,9 +1,13 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
 )
 
 //convertTimestampToTime is converting the value of timestamp db-column to a Time instance
@@ -18,3 +22,76 @@ func convertTimestampToTime(value interface{}) (interface{}, error) {
 	return nil, fmt.Errorf("Failed to convert value '%s' (kind: %s) for field 'Created' to Time struct",
 		value, reflect.TypeOf(value).Kind())
 }
+
+//encryptSecretConfiguration encrypts the values of all component configuration entries flagged as secret
+func encryptSecretConfiguration(value interface{}, encryptor *db.Encryptor) (interface{}, error) {
+	return convertSecretConfiguration(value, func(cfg *keb.Configuration) error {
+		if encryptor.Decryptable(cfg.Value) { //value is already encrypted
+			return nil
+		}
+		encValue, err := encryptor.Encrypt(cfg.Value)
+		if err != nil {
+			return err
+		}
+		cfg.Value = encValue
+		return nil
+	})
+}
+
+//decryptSecretConfiguration decrypts the values of all component configuration entries flagged as secret
+//(values which are not encrypted, e.g. stored before encryption was introduced, are returned unchanged)
+func decryptSecretConfiguration(value interface{}, encryptor *db.Encryptor) (interface{}, error) {
+	return convertSecretConfiguration(value, func(cfg *keb.Configuration) error {
+		if !encryptor.Decryptable(cfg.Value) {
+			return nil
+		}
+		decValue, err := encryptor.Decrypt(cfg.Value)
+		if err != nil {
+			return err
+		}
+		cfg.Value = decValue
+		return nil
+	})
+}
+
+func convertSecretConfiguration(value interface{}, convert func(cfg *keb.Configuration) error) (interface{}, error) {
+	var components string
+	switch v := value.(type) {
+	case string:
+		components = v
+	case []byte:
+		components = string(v)
+	default:
+		return nil, fmt.Errorf("Failed to convert value '%v' (kind: %s) for field 'Components' to string",
+			value, reflect.TypeOf(value).Kind())
+	}
+	if components == "" {
+		return components, nil
+	}
+
+	var comps []*keb.Components
+	if err := json.Unmarshal([]byte(components), &comps); err != nil {
+		return nil, err
+	}
+	var converted bool
+	for _, comp := range comps {
+		for idx := range comp.Configuration {
+			if !comp.Configuration[idx].Secret {
+				continue
+			}
+			if err := convert(&comp.Configuration[idx]); err != nil {
+				return nil, err
+			}
+			converted = true
+		}
+	}
+	if !converted { //keep the original value if no secrets exist
+		return components, nil
+	}
+
+	result, err := json.Marshal(comps)
+	if err != nil {
+		return nil, err
+	}
+	return string(result), nil
+}
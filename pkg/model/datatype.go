@@ -1,15 +1,23 @@
 package model
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	String  DataType = "string"
 	Integer DataType = "integer"
 	Boolean DataType = "boolean"
+	List    DataType = "list" //list defined as JSON or YAML array
+	Map     DataType = "map"  //map defined as JSON or YAML object
+	JSON    DataType = "json" //any JSON document
+	YAML    DataType = "yaml" //any YAML document
 )
 
 type DataType string
@@ -22,11 +30,29 @@ func NewDataType(dataType string) (DataType, error) {
 		return Integer, nil
 	case string(Boolean):
 		return Boolean, nil
+	case string(List):
+		return List, nil
+	case string(Map):
+		return Map, nil
+	case string(JSON):
+		return JSON, nil
+	case string(YAML):
+		return YAML, nil
 	default:
 		return "", fmt.Errorf("DataType '%s' is not supported", dataType)
 	}
 }
 
+//Structured returns true if values of the data type are lists or maps instead of scalar values
+func (dt DataType) Structured() bool {
+	switch dt {
+	case List, Map, JSON, YAML:
+		return true
+	default:
+		return false
+	}
+}
+
 func (dt DataType) Get(value string) (interface{}, error) {
 	var err error
 	var typedValue interface{}
@@ -41,12 +67,90 @@ func (dt DataType) Get(value string) (interface{}, error) {
 		if err != nil {
 			return typedValue, dt.fireParseError(value)
 		}
+	case List, Map, JSON, YAML:
+		typedValue, err = dt.parseStructure(value)
+		if err != nil {
+			return typedValue, dt.fireParseError(value)
+		}
 	default:
 		typedValue = value
 	}
 	return typedValue, nil
 }
 
+//parseStructure converts a JSON or YAML document into lists, maps and scalar values
+func (dt DataType) parseStructure(value string) (interface{}, error) {
+	data := []byte(value)
+	if dt != JSON { //YAML is a superset of JSON: convert it to JSON first
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("value contains more than one document")
+	}
+	result = convertNumbers(result)
+
+	switch dt {
+	case List:
+		if _, ok := result.([]interface{}); !ok {
+			return nil, fmt.Errorf("value is not a list")
+		}
+	case Map:
+		if _, ok := result.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("value is not a map")
+		}
+	}
+	return result, nil
+}
+
+//convertNumbers replaces JSON numbers by int64 or float64 values
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if intValue, err := v.Int64(); err == nil {
+			return intValue
+		}
+		floatValue, _ := v.Float64()
+		return floatValue
+	case []interface{}:
+		for idx := range v {
+			v[idx] = convertNumbers(v[idx])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = convertNumbers(v[key])
+		}
+	}
+	return value
+}
+
+//Zero returns the zero value of the data type
+func (dt DataType) Zero() interface{} {
+	switch dt {
+	case Boolean:
+		return false
+	case Integer:
+		return int64(0)
+	case List:
+		return []interface{}{}
+	case Map:
+		return map[string]interface{}{}
+	case JSON, YAML:
+		return nil
+	default:
+		return ""
+	}
+}
+
 func (dt DataType) fireParseError(value string) error {
 	return fmt.Errorf("Value '%s' is not compatible with DataType '%s'", value, dt)
 }
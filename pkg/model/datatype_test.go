@@ -22,6 +22,56 @@ func TestDataType(t *testing.T) {
 		dt, err = NewDataType("string")
 		require.NoError(t, err)
 		require.Equal(t, dt, String)
+
+		for _, structured := range []DataType{List, Map, JSON, YAML} {
+			dt, err = NewDataType(string(structured))
+			require.NoError(t, err)
+			require.Equal(t, dt, structured)
+			require.True(t, dt.Structured())
+		}
+	})
+
+	t.Run("Get list", func(t *testing.T) {
+		value, err := List.Get(`["a", 1, 1.5, true]`)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"a", int64(1), 1.5, true}, value)
+
+		value, err = List.Get("- a\n- b")
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"a", "b"}, value)
+
+		_, err = List.Get(`{"a": 1}`)
+		require.Error(t, err)
+	})
+
+	t.Run("Get map", func(t *testing.T) {
+		value, err := Map.Get(`{"a": {"b": [1, 2]}}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{int64(1), int64(2)}}}, value)
+
+		_, err = Map.Get(`["a"]`)
+		require.Error(t, err)
+	})
+
+	t.Run("Get JSON", func(t *testing.T) {
+		value, err := JSON.Get(`{"a": "b"}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": "b"}, value)
+
+		_, err = JSON.Get("a: b")
+		require.Error(t, err)
+
+		_, err = JSON.Get(`{"a": "b"} {"c": "d"}`)
+		require.Error(t, err)
+	})
+
+	t.Run("Get YAML", func(t *testing.T) {
+		value, err := YAML.Get("a:\n  b: 1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}}, value)
+
+		_, err = YAML.Get("a: [b")
+		require.Error(t, err)
 	})
 
 }
@@ -0,0 +1,12 @@
+package model
+
+import "github.com/kyma-incubator/reconciler/pkg/db"
+
+//EncryptedFields returns all entity fields which can contain encrypted data
+func EncryptedFields() []*db.EncryptedField {
+	return []*db.EncryptedField{
+		{Entity: &ClusterEntity{}, Field: "Kubeconfig"},
+		{Entity: &ClusterConfigurationEntity{}, Field: "Components"}, //values of secret configuration entries
+		{Entity: &ValueEntity{}, Field: "Value"},
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,18 +11,31 @@ import (
 
 const tblKeys string = "config_keys"
 
+//maxCachedPrograms limits the number of cached validators and triggers
+const maxCachedPrograms = 1000
+
+//programs caches the prepared validators and triggers of the latest key versions
+var programs = interpreter.NewPreparedProgramCache(maxCachedPrograms)
+
 type KeyEntity struct {
-	Key       string   `db:"notNull"`
-	Version   int64    `db:"readOnly"`
-	DataType  DataType `db:"notNull"`
-	Encrypted bool
-	Created   time.Time `db:"readOnly"`
-	Username  string    `db:"notNull"`
-	Validator string
-	Trigger   string
+	Key          string   `db:"notNull"`
+	Version      int64    `db:"readOnly"`
+	DataType     DataType `db:"notNull"`
+	Encrypted    bool
+	Created      time.Time `db:"readOnly"`
+	Username     string    `db:"notNull"`
+	Validator    string
+	Trigger      string
+	TriggerPhase TriggerPhase
 }
 
 func (ke *KeyEntity) Validate(value string) error {
+	return ke.ValidateWithContext(context.Background(), value)
+}
+
+//ValidateWithContext verifies the value: the validator execution is stopped if the context gets cancelled
+//or the execution limits of the interpreter are exceeded
+func (ke *KeyEntity) ValidateWithContext(ctx context.Context, value string) error {
 	//ensure data type
 	typedValue, err := ke.DataType.Get(value)
 	if err != nil {
@@ -30,7 +44,11 @@ func (ke *KeyEntity) Validate(value string) error {
 
 	//run validator logic for value
 	if ke.Validator != "" {
-		interp := interpreter.NewGolangInterpreter(ke.Validator).WithBindings(
+		program, err := programs.Get(ke.Key+"/validator", ke.Version, ke.Validator)
+		if err != nil {
+			return err
+		}
+		interp := program.Interpreter().WithContext(ctx).WithBindings(
 			map[string]interface{}{"it": typedValue, "value": typedValue})
 		result, err := interp.EvalBool()
 		if err != nil {
@@ -49,6 +67,38 @@ func (ke *KeyEntity) Validate(value string) error {
 	return nil
 }
 
+//RunTrigger evaluates the trigger function of the key for a created/changed value
+//(previousValue is nil if no value existed before)
+func (ke *KeyEntity) RunTrigger(value, previousValue *ValueEntity) (string, error) {
+	if ke.Trigger == "" {
+		return "", nil
+	}
+
+	newTypedValue, err := value.Get()
+	if err != nil {
+		return "", err
+	}
+	oldTypedValue := ke.DataType.Zero()
+	if previousValue != nil {
+		if oldTypedValue, err = previousValue.Get(); err != nil {
+			return "", err
+		}
+	}
+
+	program, err := programs.Get(ke.Key+"/trigger", ke.Version, ke.Trigger)
+	if err != nil {
+		return "", err
+	}
+	interp := program.Interpreter().WithBindings(
+		map[string]interface{}{
+			"key":      value.Key,
+			"bucket":   value.Bucket,
+			"newValue": newTypedValue,
+			"oldValue": oldTypedValue,
+		})
+	return interp.EvalString()
+}
+
 func (ke *KeyEntity) String() string {
 	return fmt.Sprintf("KeyEntity [Key=%s,Version=%d,DataType=%s,Encrypted=%t,User=%s]",
 		ke.Key, ke.Version, ke.DataType, ke.Encrypted, ke.Username)
@@ -61,7 +111,9 @@ func (ke *KeyEntity) New() db.DatabaseEntity {
 func (ke *KeyEntity) Marshaller() *db.EntityMarshaller {
 	marshaller := db.NewEntityMarshaller(&ke)
 	marshaller.AddUnmarshaller("DataType", convertStringToDataType)
+	marshaller.AddUnmarshaller("TriggerPhase", convertStringToTriggerPhase)
 	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	marshaller.AddMarshaller("TriggerPhase", convertStringToTriggerPhase)
 	return marshaller
 }
 
@@ -79,11 +131,19 @@ func (ke *KeyEntity) Equal(other db.DatabaseEntity) bool {
 			ke.DataType == otherKey.DataType &&
 			ke.Encrypted == otherKey.Encrypted &&
 			ke.Validator == otherKey.Validator &&
-			ke.Trigger == otherKey.Trigger
+			ke.Trigger == otherKey.Trigger &&
+			ke.triggerPhase() == otherKey.triggerPhase()
 	}
 	return false
 }
 
+func (ke *KeyEntity) triggerPhase() TriggerPhase {
+	if ke.TriggerPhase == "" {
+		return TriggerOnChange
+	}
+	return ke.TriggerPhase
+}
+
 type InvalidValueError struct {
 	Validator string
 	Result    interface{}
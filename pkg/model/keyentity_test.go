@@ -1,12 +1,61 @@
 package model
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestKeyEntity(t *testing.T) {
+	t.Run("Validate with multi-line validator", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:      "Mock",
+			DataType: List,
+			Validator: `
+func unique(list []interface{}) bool {
+	seen := make(map[string]bool)
+	for _, item := range list {
+		if seen[item.(string)] {
+			return false
+		}
+		seen[item.(string)] = true
+	}
+	return true
+}
+unique(it)`,
+		}
+		require.NoError(t, key.Validate(`["a", "b"]`))
+		require.True(t, IsInvalidValueError(key.Validate(`["a", "a"]`)))
+	})
+
+	t.Run("Validate with endless validator", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:       "Mock",
+			DataType:  String,
+			Validator: "for {\n}",
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := key.ValidateWithContext(ctx, "abc")
+		require.Error(t, err)
+		require.False(t, IsInvalidValueError(err))
+	})
+
+	t.Run("Validate structured value", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:       "Mock",
+			DataType:  Map,
+			Validator: `len(it["hosts"].([]interface{})) > 1 && it["port"].(int64) < 1024`,
+		}
+		require.NoError(t, key.Validate(`{"hosts": ["a", "b"], "port": 80}`))
+
+		err := key.Validate(`{"hosts": ["a"], "port": 80}`)
+		require.Error(t, err)
+		require.True(t, IsInvalidValueError(err))
+	})
+
 	t.Run("Validate valid string", func(t *testing.T) {
 		key := &KeyEntity{
 			Key:       "Mock",
@@ -69,4 +118,42 @@ func TestKeyEntity(t *testing.T) {
 		require.Error(t, err)
 		require.False(t, IsInvalidValueError(err)) //is code error
 	})
+
+	t.Run("Run trigger with bindings", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:      "Mock",
+			DataType: Integer,
+			Trigger: `import "fmt"
+fmt.Sprintf("%s/%s: %d -> %d", bucket, key, oldValue, newValue)`,
+		}
+		result, err := key.RunTrigger(
+			&ValueEntity{Key: "Mock", Bucket: "default", DataType: Integer, Value: "2"},
+			&ValueEntity{Key: "Mock", Bucket: "default", DataType: Integer, Value: "1"})
+		require.NoError(t, err)
+		require.Equal(t, "default/Mock: 1 -> 2", result)
+	})
+
+	t.Run("Run trigger without previous value", func(t *testing.T) {
+		key := &KeyEntity{
+			Key:      "Mock",
+			DataType: String,
+			Trigger:  `oldValue == "" && newValue == "a \"quoted\" value"`,
+		}
+		result, err := key.RunTrigger(&ValueEntity{Key: "Mock", Bucket: "default", DataType: String, Value: `a "quoted" value`}, nil)
+		require.NoError(t, err)
+		require.Equal(t, "true", result)
+	})
+
+	t.Run("Trigger phase", func(t *testing.T) {
+		phase, err := NewTriggerPhase("")
+		require.NoError(t, err)
+		require.Equal(t, TriggerOnChange, phase)
+
+		phase, err = NewTriggerPhase("Post-Reconciliation")
+		require.NoError(t, err)
+		require.Equal(t, TriggerPostReconciliation, phase)
+
+		_, err = NewTriggerPhase("sometimes")
+		require.Error(t, err)
+	})
 }
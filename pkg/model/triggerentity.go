@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/db"
+)
+
+const (
+	tblTriggers string = "config_triggers"
+
+	TriggerOnChange           TriggerPhase = "change"              //trigger runs directly after a value was created/changed
+	TriggerPreReconciliation  TriggerPhase = "pre-reconciliation"  //trigger runs before the affected clusters get reconciled
+	TriggerPostReconciliation TriggerPhase = "post-reconciliation" //trigger runs after the affected clusters were reconciled
+)
+
+//TriggerPhase defines when the trigger function of a configuration key is executed
+type TriggerPhase string
+
+func NewTriggerPhase(phase string) (TriggerPhase, error) {
+	switch strings.ToLower(phase) {
+	case "", string(TriggerOnChange):
+		return TriggerOnChange, nil
+	case string(TriggerPreReconciliation):
+		return TriggerPreReconciliation, nil
+	case string(TriggerPostReconciliation):
+		return TriggerPostReconciliation, nil
+	default:
+		return "", fmt.Errorf("TriggerPhase '%s' is not supported", phase)
+	}
+}
+
+//TriggerEntity is a trigger which is waiting for the reconciliation of a cluster
+type TriggerEntity struct {
+	ID                   int64        `db:"readOnly"`
+	Cluster              string       `db:"notNull"`
+	Bucket               string       `db:"notNull"`
+	Key                  string       `db:"notNull"`
+	KeyVersion           int64        `db:"notNull"`
+	Phase                TriggerPhase `db:"notNull"`
+	ValueVersion         int64        `db:"notNull"`
+	PreviousValueVersion int64        //is 0 if no previous value existed
+	Created              time.Time    `db:"readOnly"`
+}
+
+func (te *TriggerEntity) String() string {
+	return fmt.Sprintf("TriggerEntity [ID=%d,Cluster=%s,Bucket=%s,Key=%s,KeyVersion=%d,Phase=%s,ValueVersion=%d,PreviousValueVersion=%d]",
+		te.ID, te.Cluster, te.Bucket, te.Key, te.KeyVersion, te.Phase, te.ValueVersion, te.PreviousValueVersion)
+}
+
+func (te *TriggerEntity) New() db.DatabaseEntity {
+	return &TriggerEntity{}
+}
+
+func (te *TriggerEntity) Marshaller() *db.EntityMarshaller {
+	marshaller := db.NewEntityMarshaller(&te)
+	marshaller.AddUnmarshaller("Phase", convertStringToTriggerPhase)
+	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
+	return marshaller
+}
+
+func (te *TriggerEntity) Table() string {
+	return tblTriggers
+}
+
+func (te *TriggerEntity) Equal(other db.DatabaseEntity) bool {
+	if other == nil {
+		return false
+	}
+	otherTrigger, ok := other.(*TriggerEntity)
+	if ok {
+		return te.Cluster == otherTrigger.Cluster &&
+			te.Bucket == otherTrigger.Bucket &&
+			te.Key == otherTrigger.Key &&
+			te.KeyVersion == otherTrigger.KeyVersion &&
+			te.Phase == otherTrigger.Phase &&
+			te.ValueVersion == otherTrigger.ValueVersion &&
+			te.PreviousValueVersion == otherTrigger.PreviousValueVersion
+	}
+	return false
+}
+
+func convertStringToTriggerPhase(value interface{}) (interface{}, error) {
+	if value == nil {
+		return TriggerOnChange, nil
+	}
+	return NewTriggerPhase(fmt.Sprintf("%s", value))
+}
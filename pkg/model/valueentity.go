@@ -7,7 +7,10 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/db"
 )
 
-const tblValues string = "config_values"
+const (
+	tblValues   string = "config_values"
+	maskedValue string = "***"
+)
 
 type ValueEntity struct {
 	Key        string    `db:"notNull"`
@@ -18,11 +21,16 @@ type ValueEntity struct {
 	DataType   DataType  `db:"notNull"`
 	Created    time.Time `db:"readOnly"`
 	Username   string    `db:"notNull"`
+	Encrypted  bool      //value belongs to an encrypted key
 }
 
 func (ve *ValueEntity) String() string {
+	value := ve.Value
+	if ve.Encrypted {
+		value = maskedValue
+	}
 	return fmt.Sprintf("ValueEntity [Key=%s,KeyVersion=%d,Value=%s,Version=%d,Bucket=%s,DataType=%s,User=%s]",
-		ve.Key, ve.KeyVersion, ve.Value, ve.Version, ve.Bucket, ve.DataType, ve.Username)
+		ve.Key, ve.KeyVersion, value, ve.Version, ve.Bucket, ve.DataType, ve.Username)
 }
 
 func (ve *ValueEntity) New() db.DatabaseEntity {
@@ -34,6 +42,10 @@ func (ve *ValueEntity) Marshaller() *db.EntityMarshaller {
 	marshaller.AddUnmarshaller("DataType", convertStringToDataType)
 	marshaller.AddUnmarshaller("Created", convertTimestampToTime)
 	marshaller.AddMarshaller("Bucket", requireValidBucketName)
+	marshaller.AddEncryptionMarshaller("Value", func(value interface{}, encryptor *db.Encryptor) (interface{}, error) {
+		return encryptValue(value, ve.Encrypted, encryptor)
+	})
+	marshaller.AddEncryptionUnmarshaller("Value", decryptValue)
 	return marshaller
 }
 
@@ -63,6 +75,25 @@ func convertStringToDataType(value interface{}) (interface{}, error) {
 	return NewDataType(value.(string))
 }
 
+//encryptValue encrypts the value if it belongs to an encrypted key
+func encryptValue(value interface{}, encrypted bool, encryptor *db.Encryptor) (interface{}, error) {
+	data := fmt.Sprintf("%v", value)
+	if !encrypted || encryptor.Decryptable(data) { //value is not secret or already encrypted
+		return value, nil
+	}
+	return encryptor.Encrypt(data)
+}
+
+//decryptValue decrypts the value if it was encrypted (values of keys which are not encrypted
+//are returned unchanged)
+func decryptValue(value interface{}, encryptor *db.Encryptor) (interface{}, error) {
+	data, ok := value.(string)
+	if !ok || !encryptor.Decryptable(data) {
+		return value, nil
+	}
+	return encryptor.Decrypt(data)
+}
+
 func requireValidBucketName(value interface{}) (interface{}, error) {
 	bucketName := fmt.Sprintf("%s", value)
 	if bucketName != "" {
@@ -0,0 +1,284 @@
+// Code generated by pkg/openapi/gen from the OpenAPI specification of the mothership API. DO NOT EDIT.
+
+package client
+
+import (
+	"time"
+)
+
+type AuditEntry struct {
+	Action   string    `json:"action"`
+	Actor    string    `json:"actor"`
+	After    string    `json:"after,omitempty"`  //Summary of the entity after the change
+	Before   string    `json:"before,omitempty"` //Summary of the entity before the change
+	Created  time.Time `json:"created"`
+	Entity   string    `json:"entity"`
+	EntityID string    `json:"entityId"`
+	ID       int64     `json:"id"`
+}
+
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+	Next    int64        `json:"next,omitempty"` //Cursor of the next page (only set if further entries can exist)
+}
+
+type Bucket struct {
+	Bucket  string    `json:"bucket"`
+	Created time.Time `json:"created"`
+	User    string    `json:"user"`
+}
+
+type Buckets struct {
+	Buckets []Bucket `json:"buckets"`
+}
+
+type CallbackMessage struct {
+	Status CallbackStatus `json:"status"`
+}
+
+type CallbackStatus string
+
+const (
+	CallbackStatusNotstarted CallbackStatus = "notstarted"
+	CallbackStatusRunning    CallbackStatus = "running"
+	CallbackStatusSuccess    CallbackStatus = "success"
+	CallbackStatusError      CallbackStatus = "error"
+	CallbackStatusFailed     CallbackStatus = "failed"
+)
+
+type ChangeType string
+
+const (
+	ChangeTypeAdded   ChangeType = "added"
+	ChangeTypeRemoved ChangeType = "removed"
+	ChangeTypeChanged ChangeType = "changed"
+)
+
+// Cluster runtime registered by the KEB (contract version 1)
+type Cluster struct {
+	Kubeconfig   string        `json:"kubeconfig"`
+	KymaConfig   KymaConfig    `json:"kymaConfig"`
+	Metadata     *Metadata     `json:"metadata,omitempty"`
+	RuntimeID    string        `json:"runtimeID"`
+	RuntimeInput *RuntimeInput `json:"runtimeInput,omitempty"`
+}
+
+type ClusterStatus struct {
+	Cluster              string             `json:"cluster"`
+	ClusterVersion       int64              `json:"clusterVersion"`
+	ConfigurationVersion int64              `json:"configurationVersion"`
+	Status               ClusterStatusValue `json:"status"`
+	StatusURL            string             `json:"statusUrl,omitempty"`
+}
+
+type ClusterStatusValue string
+
+const (
+	ClusterStatusValueReconcilePending ClusterStatusValue = "reconcile_pending"
+	ClusterStatusValueReconcileFailed  ClusterStatusValue = "reconcile_failed"
+	ClusterStatusValueReconciling      ClusterStatusValue = "reconciling"
+	ClusterStatusValueError            ClusterStatusValue = "error"
+	ClusterStatusValueReady            ClusterStatusValue = "ready"
+)
+
+// ClusterV2 runtime registered by the KEB (contract version 2)
+type ClusterV2 struct {
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Kubeconfig   string            `json:"kubeconfig"`
+	KymaConfig   KymaConfigV2      `json:"kymaConfig"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Metadata     *Metadata         `json:"metadata,omitempty"`
+	RuntimeID    string            `json:"runtimeID"`
+	RuntimeInput *RuntimeInput     `json:"runtimeInput,omitempty"`
+}
+
+type Component struct {
+	Component     string          `json:"component"`
+	Configuration []Configuration `json:"configuration,omitempty"`
+	Namespace     string          `json:"namespace,omitempty"`
+}
+
+type ComponentDiff struct {
+	Component     string                `json:"component"`
+	Configuration []ConfigurationChange `json:"configuration"`
+	Dependencies  *ValueChange          `json:"dependencies,omitempty"`
+	Namespace     *ValueChange          `json:"namespace,omitempty"`
+	URL           *ValueChange          `json:"url,omitempty"`
+	Version       *ValueChange          `json:"version,omitempty"`
+}
+
+type ComponentV2 struct {
+	Component     string          `json:"component"`
+	Configuration []Configuration `json:"configuration,omitempty"`
+	Dependencies  []string        `json:"dependencies,omitempty"` //Components which have to be reconciled before this component
+	Namespace     string          `json:"namespace,omitempty"`
+	URL           string          `json:"url,omitempty"`     //URL of the component chart
+	Version       string          `json:"version,omitempty"` //Version of the component (overrides the Kyma version)
+}
+
+type Configuration struct {
+	DataType string `json:"dataType,omitempty"`
+	Key      string `json:"key"`
+	Secret   bool   `json:"secret,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+// ConfigurationChange change of a component configuration entry (values of secrets are masked)
+type ConfigurationChange struct {
+	Change ChangeType `json:"change"`
+	From   string     `json:"from,omitempty"`
+	Key    string     `json:"key"`
+	Secret bool       `json:"secret"`
+	To     string     `json:"to,omitempty"`
+}
+
+type ConfigurationDiff struct {
+	AddedComponents   []string        `json:"addedComponents"`
+	ChangedComponents []ComponentDiff `json:"changedComponents"`
+	Cluster           string          `json:"cluster"`
+	FromVersion       int64           `json:"fromVersion"`
+	KymaProfile       *ValueChange    `json:"kymaProfile,omitempty"`
+	KymaVersion       *ValueChange    `json:"kymaVersion,omitempty"`
+	RemovedComponents []string        `json:"removedComponents"`
+	ToVersion         int64           `json:"toVersion"`
+}
+
+type CreateKeyRequest struct {
+	DataType     string `json:"dataType,omitempty"` //Data type of the values (string, integer, boolean, list, map, json or yaml)
+	Encrypted    bool   `json:"encrypted,omitempty"`
+	Key          string `json:"key"`
+	Trigger      string `json:"trigger,omitempty"`      //Expression which is executed if a value changes
+	TriggerPhase string `json:"triggerPhase,omitempty"` //Phase in which the trigger runs (change, pre-reconciliation or post-reconciliation)
+	User         string `json:"user,omitempty"`         //User who creates the key (required if authentication is disabled, otherwise the authenticated principal is used)
+	Validator    string `json:"validator,omitempty"`    //Expression which has to be fulfilled by the values
+}
+
+type CreateValueRequest struct {
+	KeyVersion int64  `json:"keyVersion,omitempty"` //Version of the key the value belongs to (latest key version is used if undefined)
+	User       string `json:"user,omitempty"`       //User who creates the value (required if authentication is disabled, otherwise the authenticated principal is used)
+	Value      string `json:"value"`
+}
+
+type FieldError struct {
+	Description string `json:"description"`
+	Field       string `json:"field"` //Path of the invalid field in the request body (e.g. "kymaConfig.version")
+}
+
+type InvalidKeyError struct {
+	Error     string `json:"error"`
+	Key       string `json:"key"`
+	Trigger   string `json:"trigger,omitempty"`
+	Validator string `json:"validator,omitempty"`
+}
+
+type InvalidValueError struct {
+	DataType  string      `json:"dataType,omitempty"`
+	Error     string      `json:"error"`
+	Key       string      `json:"key"`
+	Result    interface{} `json:"result,omitempty"` //Result of the validator expression
+	Validator string      `json:"validator,omitempty"`
+	Value     string      `json:"value"`
+}
+
+type Key struct {
+	Created      time.Time `json:"created"`
+	DataType     string    `json:"dataType"`
+	Encrypted    bool      `json:"encrypted"`
+	Key          string    `json:"key"`
+	Trigger      string    `json:"trigger,omitempty"`
+	TriggerPhase string    `json:"triggerPhase,omitempty"`
+	User         string    `json:"user"`
+	Validator    string    `json:"validator,omitempty"`
+	Version      int64     `json:"version"`
+}
+
+type Keys struct {
+	Keys []Key `json:"keys"`
+}
+
+type KymaConfig struct {
+	Administrators []string    `json:"administrators,omitempty"`
+	Components     []Component `json:"components"`
+	Profile        string      `json:"profile,omitempty"`
+	Urgent         bool        `json:"urgent,omitempty"` //Urgent configuration changes are applied outside of maintenance windows
+	Version        string      `json:"version"`
+}
+
+type KymaConfigV2 struct {
+	Administrators []string      `json:"administrators,omitempty"`
+	Components     []ComponentV2 `json:"components"`
+	Profile        string        `json:"profile,omitempty"`
+	Urgent         bool          `json:"urgent,omitempty"` //Urgent configuration changes are applied outside of maintenance windows
+	Version        string        `json:"version"`
+}
+
+// MaintenanceWindow recurring time range in which non-urgent reconciliations are allowed
+type MaintenanceWindow struct {
+	Begin    string   `json:"begin"`              //Begin of the time range in format "HH:MM"
+	Days     []string `json:"days,omitempty"`     //Weekdays (e.g. "monday" or "mon"), an empty list matches every day
+	End      string   `json:"end"`                //End of the time range in format "HH:MM" (if smaller than begin, range ends next day)
+	Timezone string   `json:"timezone,omitempty"` //IANA timezone name (e.g. "Europe/Berlin"), UTC is used if undefined
+}
+
+type Metadata struct {
+	GlobalAccountID    string              `json:"globalAccountID,omitempty"`
+	InstanceID         string              `json:"instanceID,omitempty"`
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	ServiceID          string              `json:"serviceID,omitempty"`
+	ServicePlanID      string              `json:"servicePlanID,omitempty"`
+	ShootName          string              `json:"shootName,omitempty"`
+	SubAccountID       string              `json:"subAccountID,omitempty"`
+}
+
+type Rollback struct {
+	ConfigurationVersion       int64     `json:"configurationVersion"`
+	Created                    time.Time `json:"created"`
+	SourceConfigurationVersion int64     `json:"sourceConfigurationVersion"`
+	User                       string    `json:"user"`
+}
+
+type RollbackRequest struct {
+	User string `json:"user,omitempty"` //User who triggers the rollback (required if authentication is disabled, otherwise the authenticated principal is used)
+}
+
+type Rollbacks struct {
+	Cluster   string     `json:"cluster"`
+	Rollbacks []Rollback `json:"rollbacks"`
+}
+
+type RuntimeInput struct {
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type StatusChange struct {
+	Duration string             `json:"Duration"`
+	Status   ClusterStatusValue `json:"Status"`
+}
+
+type ValidationError struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+type Value struct {
+	Bucket     string    `json:"bucket"`
+	Created    time.Time `json:"created"`
+	DataType   string    `json:"dataType"`
+	Key        string    `json:"key"`
+	KeyVersion int64     `json:"keyVersion"`
+	User       string    `json:"user"`
+	Value      string    `json:"value"` //Value (masked if the key is encrypted and the client is not allowed to change configuration entries)
+	Version    int64     `json:"version"`
+}
+
+type ValueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Values struct {
+	Bucket string  `json:"bucket"`
+	Key    string  `json:"key,omitempty"`
+	Values []Value `json:"values"`
+}
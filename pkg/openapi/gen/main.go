@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kyma-incubator/reconciler/pkg/openapi"
+)
+
+//Generates the Go types of the OpenAPI specification into the file passed as argument
+//(the package name is the name of the target directory)
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: gen <target file>")
+		os.Exit(1)
+	}
+	target := os.Args[1]
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		exit(err)
+	}
+	src, err := openapi.GenerateTypes(filepath.Base(filepath.Dir(absTarget)))
+	if err != nil {
+		exit(err)
+	}
+	if err := ioutil.WriteFile(target, src, 0600); err != nil {
+		exit(err)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "Failed to generate types: %s\n", err)
+	os.Exit(1)
+}
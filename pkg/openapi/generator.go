@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+//initialisms are written in upper case if they are the suffix of a Go field name (e.g. "entityId" => "EntityID")
+var initialisms = []string{"Id", "Url"}
+
+//GenerateTypes returns the Go source code of the types defined by the component schemas of the OpenAPI specification
+func GenerateTypes(packageName string) ([]byte, error) {
+	spec, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	gen := &typeGenerator{schemas: spec.Components.Schemas}
+	return gen.generate(packageName)
+}
+
+type typeGenerator struct {
+	schemas map[string]*Schema
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *typeGenerator) generate(packageName string) ([]byte, error) {
+	g.imports = make(map[string]bool)
+
+	names := make([]string, 0, len(g.schemas))
+	for name := range g.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := g.writeType(name, g.schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by pkg/openapi/gen from the OpenAPI specification of the mothership API. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", packageName)
+	if len(g.imports) > 0 {
+		src.WriteString("import (\n")
+		for _, imp := range sortedKeys(g.imports) {
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(g.buf.Bytes())
+
+	result, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated source code is invalid: %s", err)
+	}
+	return result, nil
+}
+
+func (g *typeGenerator) writeType(name string, schema *Schema) error {
+	if schema.Description != "" {
+		fmt.Fprintf(&g.buf, "//%s %s\n", name, lowerFirst(schema.Description))
+	}
+	switch {
+	case schema.Type == "string" && len(schema.Enum) > 0:
+		fmt.Fprintf(&g.buf, "type %s string\n\nconst (\n", name)
+		for _, value := range schema.Enum {
+			fmt.Fprintf(&g.buf, "\t%s%s %s = %q\n", name, goName(value), name, value)
+		}
+		g.buf.WriteString(")\n\n")
+	case schema.Type == "object":
+		fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+		for _, property := range sortedKeys(schema.Properties) {
+			goType, err := g.goType(schema.Properties[property], schema.IsRequired(property))
+			if err != nil {
+				return fmt.Errorf("property '%s' of schema '%s': %s", property, name, err)
+			}
+			tag := property
+			if !schema.IsRequired(property) {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`", goName(property), goType, tag)
+			if description := schema.Properties[property].Description; description != "" {
+				fmt.Fprintf(&g.buf, " //%s", strings.ReplaceAll(description, "\n", " "))
+			}
+			g.buf.WriteString("\n")
+		}
+		g.buf.WriteString("}\n\n")
+	default:
+		goType, err := g.goType(schema, true)
+		if err != nil {
+			return fmt.Errorf("schema '%s': %s", name, err)
+		}
+		fmt.Fprintf(&g.buf, "type %s %s\n\n", name, goType)
+	}
+	return nil
+}
+
+//goType returns the Go type of a schema: optional objects are referenced by pointer
+func (g *typeGenerator) goType(schema *Schema, required bool) (string, error) {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		target, ok := g.schemas[name]
+		if !ok {
+			return "", fmt.Errorf("referenced schema '%s' is undefined", schema.Ref)
+		}
+		if target.Type == "object" && !required {
+			return "*" + name, nil
+		}
+		return name, nil
+	}
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if schema.Items == nil {
+			return "", fmt.Errorf("array without items schema")
+		}
+		itemType, err := g.goType(schema.Items, true)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case "object":
+		if schema.AdditionalProperties != nil {
+			valueType, err := g.goType(schema.AdditionalProperties, true)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + valueType, nil
+		}
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	default:
+		return "", fmt.Errorf("type '%s' is not supported", schema.Type)
+	}
+}
+
+//goName converts a JSON property or enum value to an exported Go identifier
+func goName(value string) string {
+	var name strings.Builder
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+	result := name.String()
+	for _, initialism := range initialisms {
+		if strings.HasSuffix(result, initialism) {
+			result = strings.TrimSuffix(result, initialism) + strings.ToUpper(initialism)
+		}
+	}
+	return result
+}
+
+func lowerFirst(value string) string {
+	if value == "" {
+		return value
+	}
+	runes := []rune(value)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch typedMap := m.(type) {
+	case map[string]bool:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	case map[string]*Schema:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
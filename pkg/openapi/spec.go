@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	_ "embed" //required to embed the specification
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+//go:generate go run ./gen ./client/types.go
+
+//go:embed mothership.yaml
+var specYAML []byte
+
+//Spec is the parsed OpenAPI specification of the mothership API (only the parts used by the validator and
+//the type generator are modelled)
+type Spec struct {
+	Paths      map[string]*PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+//PathItem contains the operations of a path
+type PathItem struct {
+	Get    *Operation `json:"get"`
+	Put    *Operation `json:"put"`
+	Post   *Operation `json:"post"`
+	Delete *Operation `json:"delete"`
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	return map[string]*Operation{
+		"GET":    p.Get,
+		"PUT":    p.Put,
+		"POST":   p.Post,
+		"DELETE": p.Delete,
+	}
+}
+
+//Operation is a HTTP method of a path
+type Operation struct {
+	OperationID string `json:"operationId"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema json.RawMessage `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+//BodySchema returns the JSON schema of the request body or nil if the operation expects no body
+func (o *Operation) BodySchema() json.RawMessage {
+	if o.RequestBody == nil {
+		return nil
+	}
+	if content, ok := o.RequestBody.Content["application/json"]; ok {
+		return content.Schema
+	}
+	return nil
+}
+
+//Schema is a (reduced) OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties *Schema            `json:"additionalProperties"` //schema of the map values (boolean values are not supported)
+	Enum                 []string           `json:"enum"`
+}
+
+//IsRequired returns true if the property is mandatory
+func (s *Schema) IsRequired(property string) bool {
+	for _, required := range s.Required {
+		if required == property {
+			return true
+		}
+	}
+	return false
+}
+
+//JSON returns the OpenAPI specification as JSON document
+func JSON() ([]byte, error) {
+	return yaml.YAMLToJSON(specYAML)
+}
+
+//Load parses the OpenAPI specification
+func Load() (*Spec, error) {
+	data, err := JSON()
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI specification: %s", err)
+	}
+	return spec, nil
+}
+
+//Operations returns all operations of the specification by their operation ID
+func (s *Spec) Operations() (map[string]*Operation, error) {
+	result := make(map[string]*Operation)
+	for path, pathItem := range s.Paths {
+		for method, operation := range pathItem.operations() {
+			if operation == nil {
+				continue
+			}
+			if operation.OperationID == "" {
+				return nil, fmt.Errorf("operation %s %s has no operation ID", method, path)
+			}
+			if _, ok := result[operation.OperationID]; ok {
+				return nil, fmt.Errorf("operation ID '%s' is not unique", operation.OperationID)
+			}
+			result[operation.OperationID] = operation
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"go.uber.org/zap"
+)
+
+const rootField = "(root)"
+
+//legacyFieldNames maps field names which clients sent before the OpenAPI specification was introduced to
+//the specified field names (the JSON decoder of the handlers matches field names case-insensitively)
+var legacyFieldNames = map[string]string{
+	"kubeConfig": "kubeconfig",
+}
+
+//FieldError describes an invalid field of a request body
+type FieldError struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+func (e *FieldError) String() string {
+	if e.Field == "" {
+		return e.Description
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+//ValidationError is returned to clients if the request body does not match the OpenAPI specification
+type ValidationError struct {
+	Message string        `json:"error"`
+	Fields  []*FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		fields = append(fields, field.String())
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(fields, ", "))
+}
+
+func IsValidationError(err error) bool {
+	_, ok := err.(*ValidationError)
+	return ok
+}
+
+type bodySchema struct {
+	required bool
+	schema   *gojsonschema.Schema
+}
+
+//Validator verifies request bodies against the schemas of the OpenAPI specification
+type Validator struct {
+	bodies map[string]*bodySchema //body schemas by operation ID (nil for operations without body)
+	logger *zap.SugaredLogger
+}
+
+func NewValidator(logger *zap.SugaredLogger) (*Validator, error) {
+	spec, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	operations, err := spec.Operations()
+	if err != nil {
+		return nil, err
+	}
+	components, err := specComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		bodies: make(map[string]*bodySchema, len(operations)),
+		logger: logger,
+	}
+	for operationID, operation := range operations {
+		rawSchema := operation.BodySchema()
+		if rawSchema == nil {
+			v.bodies[operationID] = nil
+			continue
+		}
+		//wrap the body schema to resolve references to the component schemas
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(map[string]interface{}{
+			"allOf":      []json.RawMessage{rawSchema},
+			"components": components,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("request body schema of operation '%s' is invalid: %s", operationID, err)
+		}
+		v.bodies[operationID] = &bodySchema{
+			required: operation.RequestBody.Required,
+			schema:   schema,
+		}
+	}
+	return v, nil
+}
+
+func specComponents() (json.RawMessage, error) {
+	data, err := JSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Components json.RawMessage `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Components, nil
+}
+
+//Validate returns a handler which rejects requests whose body does not match the request body schema
+//of the operation with status 400. The operation has to be defined in the OpenAPI specification.
+func (v *Validator) Validate(operationID string, handler http.HandlerFunc) http.HandlerFunc {
+	body, ok := v.bodies[operationID]
+	if !ok {
+		panic(fmt.Sprintf("operation '%s' is not defined in the OpenAPI specification", operationID))
+	}
+	if body == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s\n\n%s", http.StatusText(http.StatusInternalServerError), err), http.StatusInternalServerError)
+			return
+		}
+		if err := body.validate(payload); err != nil {
+			v.logger.Debugf("Rejecting request %s %s: %s", r.Method, r.URL.Path, err)
+			sendValidationError(w, err)
+			return
+		}
+		//handler has to be able to read the body again
+		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
+		handler(w, r)
+	}
+}
+
+//ValidateBody verifies the request body of an operation
+func (v *Validator) ValidateBody(operationID string, payload []byte) error {
+	body, ok := v.bodies[operationID]
+	if !ok {
+		return fmt.Errorf("operation '%s' is not defined in the OpenAPI specification", operationID)
+	}
+	if body == nil {
+		return nil
+	}
+	return body.validate(payload)
+}
+
+func (b *bodySchema) validate(payload []byte) error {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		if b.required {
+			return &ValidationError{
+				Message: "Request body is missing",
+				Fields:  []*FieldError{{Description: "JSON payload is required"}},
+			}
+		}
+		return nil
+	}
+	result, err := b.schema.Validate(gojsonschema.NewBytesLoader(normalizeFieldNames(payload)))
+	if err != nil {
+		return &ValidationError{
+			Message: "Request body is not valid JSON",
+			Fields:  []*FieldError{{Description: err.Error()}},
+		}
+	}
+	if result.Valid() {
+		return nil
+	}
+	validationErr := &ValidationError{Message: "Request body is invalid"}
+	for _, resultErr := range result.Errors() {
+		if resultErr.Type() == "number_all_of" {
+			continue //caused by the wrapping schema, the causing errors are reported separately
+		}
+		validationErr.Fields = append(validationErr.Fields, &FieldError{
+			Field:       fieldPath(resultErr),
+			Description: resultErr.Description(),
+		})
+	}
+	return validationErr
+}
+
+//normalizeFieldNames renames legacy fields of a JSON object to the field names of the specification
+func normalizeFieldNames(payload []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload //not a JSON object: the schema validation reports the error
+	}
+	renamed := false
+	for legacyName, name := range legacyFieldNames {
+		value, ok := fields[legacyName]
+		if !ok {
+			continue
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+		delete(fields, legacyName)
+		renamed = true
+	}
+	if !renamed {
+		return payload
+	}
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return result
+}
+
+//fieldPath returns the path of the invalid field (missing properties are part of the path)
+func fieldPath(resultErr gojsonschema.ResultError) string {
+	field := resultErr.Field()
+	if resultErr.Type() == "required" {
+		property := fmt.Sprintf("%v", resultErr.Details()["property"])
+		if field == rootField {
+			return property
+		}
+		return fmt.Sprintf("%s.%s", field, property)
+	}
+	if field == rootField {
+		return ""
+	}
+	return field
+}
+
+func sendValidationError(w http.ResponseWriter, err error) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if encErr := json.NewEncoder(w).Encode(err); encErr != nil {
+		http.Error(w, fmt.Sprintf("%s\n\n%s", http.StatusText(http.StatusBadRequest), err), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidator(t *testing.T) {
+	validator, err := NewValidator(zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	t.Run("Valid cluster", func(t *testing.T) {
+		require.NoError(t, validator.ValidateBody("createOrUpdateCluster", []byte(`{
+			"runtimeID": "runtime1",
+			"kubeconfig": "apiVersion: v1",
+			"kymaConfig": {
+				"version": "2.0.0",
+				"components": [{"component": "istio", "configuration": [{"key": "a", "value": "b"}]}]
+			},
+			"metadata": {"maintenanceWindows": [{"begin": "22:00", "end": "04:00"}]}
+		}`)))
+	})
+
+	t.Run("Missing fields of cluster", func(t *testing.T) {
+		err := validator.ValidateBody("createOrUpdateCluster", []byte(`{
+			"runtimeID": "",
+			"kymaConfig": {"version": "2.0.0", "components": [{"namespace": "istio-system"}]}
+		}`))
+		require.True(t, IsValidationError(err))
+		require.ElementsMatch(t, []string{"runtimeID", "kubeconfig", "kymaConfig.components.0.component"},
+			fields(err.(*ValidationError)))
+	})
+
+	t.Run("Cluster of contract version 2", func(t *testing.T) {
+		require.NoError(t, validator.ValidateBody("createOrUpdateClusterV2", []byte(`{
+			"runtimeID": "runtime1",
+			"kubeconfig": "apiVersion: v1",
+			"labels": {"region": "eu"},
+			"kymaConfig": {
+				"version": "2.0.0",
+				"components": [
+					{"component": "istio", "version": "1.11.0", "url": "https://example.com/istio.tgz"},
+					{"component": "logging", "dependencies": ["istio"]}
+				]
+			}
+		}`)))
+
+		err := validator.ValidateBody("createOrUpdateClusterV2", []byte(`{
+			"runtimeID": "runtime1",
+			"kubeconfig": "apiVersion: v1",
+			"labels": {"region": 1},
+			"kymaConfig": {"version": "2.0.0", "components": [{"component": "istio", "dependencies": [""]}]}
+		}`))
+		require.True(t, IsValidationError(err))
+		require.ElementsMatch(t, []string{"labels.region", "kymaConfig.components.0.dependencies.0"},
+			fields(err.(*ValidationError)))
+	})
+
+	t.Run("Legacy field names of cluster", func(t *testing.T) {
+		require.NoError(t, validator.ValidateBody("createOrUpdateCluster", []byte(`{
+			"runtimeID": "runtime1",
+			"kubeConfig": "apiVersion: v1",
+			"kymaConfig": {"version": "2.0.0", "components": [{"component": "istio"}]}
+		}`)))
+	})
+
+	t.Run("E2E test requests", func(t *testing.T) {
+		for _, file := range []string{"correct_simple_request.json", "wrong_kubeconfig.json"} {
+			payload, err := ioutil.ReadFile(filepath.Join("..", "..", "cmd", "e2e", "request", file))
+			require.NoError(t, err)
+			require.NoError(t, validator.ValidateBody("createOrUpdateCluster", payload), file)
+		}
+	})
+
+	t.Run("Invalid enum value", func(t *testing.T) {
+		err := validator.ValidateBody("operationCallback", []byte(`{"status": "unknown"}`))
+		require.True(t, IsValidationError(err))
+		require.Equal(t, []string{"status"}, fields(err.(*ValidationError)))
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		err := validator.ValidateBody("createKey", []byte(`{"key":`))
+		require.True(t, IsValidationError(err))
+	})
+
+	t.Run("Missing body", func(t *testing.T) {
+		err := validator.ValidateBody("rollbackConfig", nil)
+		require.True(t, IsValidationError(err))
+	})
+
+	t.Run("Operation without body", func(t *testing.T) {
+		require.NoError(t, validator.ValidateBody("getKeys", nil))
+	})
+
+	t.Run("Unknown operation", func(t *testing.T) {
+		err := validator.ValidateBody("doesNotExist", nil)
+		require.Error(t, err)
+		require.False(t, IsValidationError(err))
+		require.Panics(t, func() {
+			validator.Validate("doesNotExist", func(w http.ResponseWriter, r *http.Request) {})
+		})
+	})
+
+	t.Run("Middleware", func(t *testing.T) {
+		var received string
+		handler := validator.Validate("createValue", func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			received = string(body)
+		})
+
+		//valid body is passed to handler
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPut, "/v1/config/buckets/default/values/key", strings.NewReader(`{"value":"1","user":"me"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `{"value":"1","user":"me"}`, received)
+
+		//invalid body is rejected with field errors
+		received = ""
+		w = httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPut, "/v1/config/buckets/default/values/key", strings.NewReader(`{"value":1}`)))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Empty(t, received)
+		validationErr := &ValidationError{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), validationErr))
+		require.Equal(t, []string{"value"}, fields(validationErr))
+	})
+}
+
+func TestSpec(t *testing.T) {
+	spec, err := Load()
+	require.NoError(t, err)
+	operations, err := spec.Operations()
+	require.NoError(t, err)
+	require.NotEmpty(t, operations)
+
+	data, err := JSON()
+	require.NoError(t, err)
+	require.True(t, json.Valid(data))
+}
+
+func TestGeneratedTypes(t *testing.T) {
+	expected, err := GenerateTypes("client")
+	require.NoError(t, err)
+	actual, err := ioutil.ReadFile("client/types.go")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual),
+		"generated types are outdated: run 'go generate ./pkg/openapi/...'")
+}
+
+func fields(err *ValidationError) []string {
+	var result []string
+	for _, field := range err.Fields {
+		result = append(result, field.Field)
+	}
+	return result
+}
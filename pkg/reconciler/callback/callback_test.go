@@ -16,13 +16,13 @@ func TestRemoteCallbackHandler(t *testing.T) {
 	logger := log.NewOptionalLogger(true)
 
 	t.Run("Test successful remote status update", func(t *testing.T) {
-		rcb, err := NewRemoteCallbackHandler("https://httpbin.org/status/200", logger)
+		rcb, err := NewRemoteCallbackHandler("https://httpbin.org/status/200", "", logger)
 		require.NoError(t, err)
 		require.NoError(t, rcb.Callback(reconciler.Running))
 	})
 
 	t.Run("Test failed remote status update", func(t *testing.T) {
-		rcb, err := NewRemoteCallbackHandler("https://httpbin.org/status/400", logger)
+		rcb, err := NewRemoteCallbackHandler("https://httpbin.org/status/400", "", logger)
 		require.NoError(t, err)
 		require.Error(t, rcb.Callback(reconciler.Running))
 	})
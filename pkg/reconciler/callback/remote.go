@@ -13,11 +13,12 @@ import (
 )
 
 type RemoteCallbackHandler struct {
-	logger      *zap.SugaredLogger
-	callbackURL string
+	logger        *zap.SugaredLogger
+	callbackURL   string
+	callbackToken string
 }
 
-func NewRemoteCallbackHandler(callbackURL string, logger *zap.SugaredLogger) (Handler, error) {
+func NewRemoteCallbackHandler(callbackURL, callbackToken string, logger *zap.SugaredLogger) (Handler, error) {
 	//validate URL
 	if callbackURL != "" { //empty URLs are allowed (used in some test cases)
 		if _, err := url.ParseRequestURI(callbackURL); err != nil {
@@ -27,8 +28,9 @@ func NewRemoteCallbackHandler(callbackURL string, logger *zap.SugaredLogger) (Ha
 
 	//return new remote callback
 	return &RemoteCallbackHandler{
-		logger:      logger,
-		callbackURL: callbackURL,
+		logger:        logger,
+		callbackURL:   callbackURL,
+		callbackToken: callbackToken,
 	}, nil
 }
 
@@ -45,7 +47,15 @@ func (cb *RemoteCallbackHandler) Callback(status reconciler.Status) error {
 		return err
 	}
 
-	resp, err := http.Post(cb.callbackURL, "application/json", bytes.NewBuffer(requestBody))
+	req, err := http.NewRequest(http.MethodPost, cb.callbackURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cb.callbackToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cb.callbackToken))
+	}
+	resp, err := http.DefaultClient.Do(req)
 
 	//dump request for debugging purposes
 	dumpResp, dumpErr := httputil.DumpResponse(resp, true)
@@ -1,9 +1,13 @@
 package chart
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/imdario/mergo"
+	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
-	"strings"
+	"github.com/pkg/errors"
 )
 
 type Component struct {
@@ -12,11 +16,16 @@ type Component struct {
 	profile       string
 	namespace     string
 	configuration map[string]interface{}
+	dataTypes     map[string]model.DataType
 }
 
 func (c *Component) Configuration() (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	for key, value := range c.configuration {
+		value, err := c.typedValue(key, value)
+		if err != nil {
+			return nil, err
+		}
 		if err := mergo.Merge(&result, c.convertToNestedMap(key, value), mergo.WithOverride); err != nil {
 			return nil, err
 		}
@@ -24,6 +33,20 @@ func (c *Component) Configuration() (map[string]interface{}, error) {
 	return result, nil
 }
 
+//typedValue converts values of structured data types (e.g. lists or maps) into their typed structure
+//which gets nested into the chart values
+func (c *Component) typedValue(key string, value interface{}) (interface{}, error) {
+	dataType, ok := c.dataTypes[key]
+	if !ok || !dataType.Structured() {
+		return value, nil
+	}
+	typedValue, err := dataType.Get(fmt.Sprintf("%v", value))
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Failed to convert value of configuration key '%s'", key))
+	}
+	return typedValue, nil
+}
+
 //convertToNestedMap converts a key with dot-notation into a nested map (e.g. a.b.c=value become [a:[b:[c:value]]])
 func (c *Component) convertToNestedMap(key string, value interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -51,6 +74,7 @@ func NewComponentBuilder(version, name string) *ComponentBuilder {
 			version:       version,
 			name:          name,
 			configuration: make(map[string]interface{}),
+			dataTypes:     make(map[string]model.DataType),
 		},
 	}
 }
@@ -68,6 +92,9 @@ func (cb *ComponentBuilder) WithNamespace(namespace string) *ComponentBuilder {
 func (cb *ComponentBuilder) WithConfiguration(config []reconciler.Configuration) *ComponentBuilder {
 	for _, kvEntry := range config {
 		cb.component.configuration[kvEntry.Key] = kvEntry.Value
+		if dataType, err := model.NewDataType(kvEntry.DataType); err == nil { //unknown data types are handled as string
+			cb.component.dataTypes[kvEntry.Key] = dataType
+		}
 	}
 	return cb
 }
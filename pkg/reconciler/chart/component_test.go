@@ -73,4 +73,63 @@ func TestComponent(t *testing.T) {
 		require.Equal(t, expected, got)
 	})
 
+	t.Run("Test chart configuration processing with structured data types", func(t *testing.T) {
+		component := NewComponentBuilder("main", "unittest-kyma").
+			WithConfiguration([]reconciler.Configuration{
+				{
+					Key:      "test.list",
+					Value:    `["a", "b"]`,
+					DataType: "list",
+				},
+				{
+					Key:      "test.map",
+					Value:    "key1: value1\nkey2:\n  subkey: 2",
+					DataType: "yaml",
+				},
+				{
+					Key:   "test.map.key3",
+					Value: "value3",
+				},
+				{
+					Key:      "test.string",
+					Value:    `["not", "a", "list"]`,
+					DataType: "string",
+				},
+			}).
+			Build()
+
+		expected := map[string]interface{}{
+			"test": map[string]interface{}{
+				"list": []interface{}{"a", "b"},
+				"map": map[string]interface{}{
+					"key1": "value1",
+					"key2": map[string]interface{}{
+						"subkey": int64(2),
+					},
+					"key3": "value3",
+				},
+				"string": `["not", "a", "list"]`,
+			},
+		}
+
+		got, err := component.Configuration()
+		require.NoError(t, err)
+
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("Invalid value of structured data type", func(t *testing.T) {
+		component := NewComponentBuilder("main", "unittest-kyma").
+			WithConfiguration([]reconciler.Configuration{
+				{
+					Key:      "test.list",
+					Value:    `{"a": "b"}`,
+					DataType: "list",
+				},
+			}).
+			Build()
+		_, err := component.Configuration()
+		require.Error(t, err)
+	})
+
 }
@@ -3,6 +3,11 @@ package chart
 import (
 	"bytes"
 	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 type ManifestType string
@@ -10,6 +15,7 @@ type ManifestType string
 const (
 	CRD       ManifestType = "crd"
 	HelmChart ManifestType = "helmChart"
+	Secret    ManifestType = "secret"
 )
 
 type Manifest struct {
@@ -28,3 +34,33 @@ func MergeManifests(manifests ...*Manifest) string {
 	}
 	return buffer.String()
 }
+
+//NewSecretManifest renders a Kubernetes Secret which contains all secret configuration entries
+func NewSecretManifest(name, namespace string, configuration []reconciler.Configuration) (*Manifest, error) {
+	secret := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type:       v1.SecretTypeOpaque,
+		StringData: make(map[string]string),
+	}
+	for _, cfg := range configuration {
+		if cfg.Secret {
+			secret.StringData[cfg.Key] = cfg.Value
+		}
+	}
+	manifest, err := yaml.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{
+		Type:     Secret,
+		Name:     name,
+		Manifest: string(manifest),
+	}, nil
+}
@@ -0,0 +1,26 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSecretManifest(t *testing.T) {
+	manifest, err := NewSecretManifest("comp-secrets", "kyma-system", []reconciler.Configuration{
+		{Key: "user", Value: "admin"},
+		{Key: "db.password", Value: "topSecret", Secret: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Secret, manifest.Type)
+
+	secret := &v1.Secret{}
+	require.NoError(t, yaml.Unmarshal([]byte(manifest.Manifest), secret))
+	require.Equal(t, "Secret", secret.Kind)
+	require.Equal(t, "comp-secrets", secret.Name)
+	require.Equal(t, "kyma-system", secret.Namespace)
+	require.Equal(t, map[string]string{"db.password": "topSecret"}, secret.StringData) //only secrets are included
+}
@@ -1,13 +1,27 @@
 package reconciler
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+const maskedValue = "***"
+
 type Configuration struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Secret   bool   `json:"secret,omitempty"`
+	DataType string `json:"dataType,omitempty"` //structured data types (e.g. list or map) are nested into the chart values
+}
+
+//String masks the value of secret configuration entries
+func (c Configuration) String() string {
+	value := c.Value
+	if c.Secret {
+		value = maskedValue
+	}
+	return fmt.Sprintf("Configuration [Key=%s,Value=%s,Secret=%t]", c.Key, value, c.Secret)
 }
 
 type Status string
@@ -37,6 +51,7 @@ type Reconciliation struct {
 	CallbackURL     string          `json:"callbackURL"` //CallbackURL is mandatory when component-reconciler runs in separate process
 	InstallCRD      bool            `json:"installCRD"`
 	CorrelationID   string          `json:"correlationID"`
+	CallbackToken   string          `json:"callbackToken,omitempty"` //CallbackToken is sent as bearer token with each callback (if mothership requires authentication)
 
 	//These fields are not part of HTTP request coming from reconciler-controller:
 	CallbackFunc func(status Status) error `json:"-"` //CallbackFunc is mandatory when component-reconciler runs embedded in another process
@@ -47,6 +62,24 @@ func (r *Reconciliation) String() string {
 		r.Component, r.Version, r.Namespace, r.Profile)
 }
 
+//MaskSecrets replaces all secret configuration values which are part of the error message
+func (r *Reconciliation) MaskSecrets(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	masked := msg
+	for _, cfg := range r.Configuration {
+		if cfg.Secret && cfg.Value != "" {
+			masked = strings.ReplaceAll(masked, cfg.Value, maskedValue)
+		}
+	}
+	if masked == msg {
+		return err
+	}
+	return errors.New(masked)
+}
+
 func (r *Reconciliation) Validate() error {
 	//check mandatory fields are defined
 	var errFields []string
@@ -107,8 +140,10 @@ type ComponentReconciler struct {
 type ComponentReconcilersConfig map[string]*ComponentReconciler
 
 type MothershipReconcilerConfig struct {
+	Scheme        string //scheme of the callback URL (http or https), https is required if a callback token is used
 	Host          string
 	Port          int
 	CrdComponents []string
 	PreComponents []string
+	CallbackToken string //token used by component reconcilers to authenticate their callbacks
 }
@@ -0,0 +1,33 @@
+package reconciler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliation(t *testing.T) {
+	model := &Reconciliation{
+		Configuration: []Configuration{
+			{Key: "user", Value: "admin"},
+			{Key: "password", Value: "topSecret", Secret: true},
+		},
+	}
+
+	t.Run("Mask secrets in errors", func(t *testing.T) {
+		require.NoError(t, model.MaskSecrets(nil))
+
+		err := fmt.Errorf("login of user admin failed")
+		require.Equal(t, err, model.MaskSecrets(err))
+
+		err = fmt.Errorf("login of user admin with password topSecret failed")
+		require.EqualError(t, model.MaskSecrets(err), "login of user admin with password *** failed")
+	})
+
+	t.Run("Mask secrets in configuration string", func(t *testing.T) {
+		require.Contains(t, fmt.Sprintf("%s", model.Configuration), "admin")
+		require.NotContains(t, fmt.Sprintf("%s", model.Configuration), "topSecret")
+		require.NotContains(t, fmt.Sprintf("%v", model.Configuration), "topSecret")
+	})
+}
@@ -63,6 +63,8 @@ type ComponentReconciler struct {
 	preReconcileAction  Action
 	reconcileAction     Action
 	postReconcileAction Action
+	//secret configuration entries are deployed as Kubernetes Secret instead of Helm values:
+	kubernetesSecrets bool
 	//retry:
 	maxRetries int
 	retryDelay time.Duration
@@ -241,6 +243,13 @@ func (r *ComponentReconciler) WithPostReconcileAction(postReconcileAction Action
 	return r
 }
 
+//WithKubernetesSecrets deploys secret configuration entries as Kubernetes Secret (named '<component>-secrets')
+//instead of passing them as Helm values to the component chart
+func (r *ComponentReconciler) WithKubernetesSecrets(enabled bool) *ComponentReconciler {
+	r.kubernetesSecrets = enabled
+	return r
+}
+
 func (r *ComponentReconciler) WithStatusUpdaterConfig(interval, timeout time.Duration) *ComponentReconciler {
 	r.statusUpdaterConfig.interval = interval
 	r.statusUpdaterConfig.timeout = timeout
@@ -352,7 +361,7 @@ func (r *ComponentReconciler) newRouter(ctx context.Context, workerPool *ants.Po
 			r.logger = loggerNew.With(zap.Field{Key: "correlation-id", Type: zapcore.StringType, String: model.CorrelationID}, zap.Field{Key: "component-name", Type: zapcore.StringType, String: model.Component})
 
 			//create callback handler
-			remoteCbh, err := callback.NewRemoteCallbackHandler(model.CallbackURL, r.logger)
+			remoteCbh, err := callback.NewRemoteCallbackHandler(model.CallbackURL, model.CallbackToken, r.logger)
 			if err != nil {
 				r.logger.Warnf("Could not create remote callback handler: %s", err)
 				r.sendResponse(w, http.StatusInternalServerError, err)
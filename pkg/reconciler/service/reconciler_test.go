@@ -97,6 +97,9 @@ func TestReconciler(t *testing.T) {
 		recon.WithWorkers(888, 999*time.Second)
 		require.Equal(t, 888, recon.workers)
 		require.Equal(t, 999*time.Second, recon.timeout)
+
+		recon.WithKubernetesSecrets(true)
+		require.True(t, recon.kubernetesSecrets)
 	})
 
 	t.Run("Filter missing component dependencies", func(t *testing.T) {
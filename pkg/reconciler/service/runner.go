@@ -32,7 +32,7 @@ func (r *runner) Run(ctx context.Context, model *reconciler.Reconciliation, call
 				r.logger.Warnf("Failed to start status updater: %s", err)
 				return err
 			}
-			err := r.reconcile(ctx, model)
+			err := model.MaskSecrets(r.reconcile(ctx, model))
 			if err != nil {
 				r.logger.Warnf("Failing reconciliation of '%s' in version '%s' with profile '%s': %s",
 					model.Component, model.Version, model.Profile, err)
@@ -98,7 +98,7 @@ func (r *runner) reconcile(ctx context.Context, model *reconciler.Reconciliation
 	if r.preReconcileAction != nil {
 		if err := r.preReconcileAction.Run(model.Version, model.Profile, model.Configuration, actionHelper); err != nil {
 			r.logger.Warnf("Pre-reconciliation action of '%s' with version '%s' failed: %s",
-				model.Component, model.Version, err)
+				model.Component, model.Version, model.MaskSecrets(err))
 			return err
 		}
 	}
@@ -106,13 +106,13 @@ func (r *runner) reconcile(ctx context.Context, model *reconciler.Reconciliation
 	if r.reconcileAction == nil {
 		if err := r.install(ctx, chartProvider, model, kubeClient); err != nil {
 			r.logger.Warnf("Default-reconciliation of '%s' with version '%s' failed: %s",
-				model.Component, model.Version, err)
+				model.Component, model.Version, model.MaskSecrets(err))
 			return err
 		}
 	} else {
 		if err := r.reconcileAction.Run(model.Version, model.Profile, model.Configuration, actionHelper); err != nil {
 			r.logger.Warnf("Reconciliation action of '%s' with version '%s' failed: %s",
-				model.Component, model.Version, err)
+				model.Component, model.Version, model.MaskSecrets(err))
 			return err
 		}
 	}
@@ -120,7 +120,7 @@ func (r *runner) reconcile(ctx context.Context, model *reconciler.Reconciliation
 	if r.postReconcileAction != nil {
 		if err := r.postReconcileAction.Run(model.Version, model.Profile, model.Configuration, actionHelper); err != nil {
 			r.logger.Warnf("Post-reconciliation action of '%s' with version '%s' failed: %s",
-				model.Component, model.Version, err)
+				model.Component, model.Version, model.MaskSecrets(err))
 			return err
 		}
 	}
@@ -139,27 +139,43 @@ func (r *runner) install(ctx context.Context, chartProvider *chart.Provider, mod
 	if err == nil {
 		r.logger.Debugf("Deployment of manifest finished successfully: %d resources deployed", len(resources))
 	} else {
-		r.logger.Warnf("Failed to deploy manifests on target cluster: %s", err)
+		r.logger.Warnf("Failed to deploy manifests on target cluster: %s", model.MaskSecrets(err))
 	}
 
 	return err
 }
 
 func (r *runner) renderManifest(chartProvider *chart.Provider, model *reconciler.Reconciliation) (string, error) {
+	configuration := model.Configuration
+	if r.kubernetesSecrets {
+		configuration = plainConfiguration(model.Configuration)
+	}
+
 	component := chart.NewComponentBuilder(model.Version, model.Component).
 		WithProfile(model.Profile).
 		WithNamespace(model.Namespace).
-		WithConfiguration(model.Configuration).
+		WithConfiguration(configuration).
 		Build()
 
 	var manifests []*chart.Manifest
 
+	//get secret configuration entries as Kubernetes Secret
+	if r.kubernetesSecrets && len(configuration) < len(model.Configuration) {
+		secretManifest, err := chart.NewSecretManifest(fmt.Sprintf("%s-secrets", model.Component), model.Namespace, model.Configuration)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to render secret manifest for component '%s'", model.Component)
+			r.logger.Errorf("%s: %s", msg, err)
+			return "", errors.Wrap(err, msg)
+		}
+		manifests = append(manifests, secretManifest)
+	}
+
 	//get manifest of component
 	chartManifest, err := chartProvider.RenderManifest(component)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to get manifest for component '%s' in Kyma version '%s'",
 			model.Component, model.Version)
-		r.logger.Errorf("%s: %s", msg, err)
+		r.logger.Errorf("%s: %s", msg, model.MaskSecrets(err))
 		return "", errors.Wrap(err, msg)
 	}
 	manifests = append(manifests, chartManifest)
@@ -177,3 +193,14 @@ func (r *runner) renderManifest(chartProvider *chart.Provider, model *reconciler
 
 	return chart.MergeManifests(manifests...), nil
 }
+
+//plainConfiguration returns all configuration entries which are not flagged as secret
+func plainConfiguration(configuration []reconciler.Configuration) []reconciler.Configuration {
+	var result []reconciler.Configuration
+	for _, cfg := range configuration {
+		if !cfg.Secret {
+			result = append(result, cfg)
+		}
+	}
+	return result
+}
@@ -24,6 +24,7 @@ type record struct {
 type invalidate struct {
 	*cacheDependencyManager
 	selector map[string]interface{}
+	clusters []string
 }
 
 type get struct {
@@ -42,7 +43,15 @@ func newCacheDependencyManager(conn db.Connection, debug bool) (*cacheDependency
 	}, nil
 }
 
-func (cdm *cacheDependencyManager) transactional(desc string, dbOps func() error) error {
+//withConnection returns a cache dependency manager which issues all queries through the given connection
+func (cdm *cacheDependencyManager) withConnection(conn db.Connection) *cacheDependencyManager {
+	return &cacheDependencyManager{
+		conn:   conn,
+		logger: cdm.logger,
+	}
+}
+
+func (cdm *cacheDependencyManager) transactional(desc string, dbOps func(tx db.Connection) error) error {
 	if err := db.Transaction(cdm.conn, dbOps, cdm.logger); err != nil {
 		return fmt.Errorf("Failed to execute database transaction '%s': %s", desc, err)
 	}
@@ -61,10 +70,10 @@ func (r *record) Exec(newTx bool) error {
 	if r.cacheEntry.ID <= 0 {
 		return fmt.Errorf("Cache entry '%s' has no ID: indicates that cache entity is not persisted in database", r.cacheEntry)
 	}
-	dbOps := func() error {
+	dbOps := func(conn db.Connection) error {
 		//track deps in DB
 		for _, value := range r.cacheDeps {
-			q, err := db.NewQuery(r.conn, &model.CacheDependencyEntity{
+			q, err := db.NewQuery(conn, &model.CacheDependencyEntity{
 				Bucket:  value.Bucket,
 				Key:     value.Key,
 				Label:   r.cacheEntry.Label,
@@ -84,13 +93,14 @@ func (r *record) Exec(newTx bool) error {
 	if newTx { //start new DB transaction
 		return r.transactional("recording cache dependencies", dbOps)
 	}
-	return dbOps() //no new DB transaction requested
+	return dbOps(r.conn) //no new DB transaction requested
 }
 
 func (cdm *cacheDependencyManager) Invalidate() *invalidate {
 	return &invalidate{
 		cdm,
 		make(map[string]interface{}),
+		nil,
 	}
 }
 
@@ -120,9 +130,11 @@ func (i *invalidate) with(colName string, colValue interface{}) *invalidate {
 }
 
 func (i *invalidate) Exec(newTx bool) error {
-	dbOps := func() error {
+	dbOps := func(conn db.Connection) error {
+		i.clusters = nil
+
 		//get cache dependencies
-		depQuery, err := db.NewQuery(i.conn, &model.CacheDependencyEntity{})
+		depQuery, err := db.NewQuery(conn, &model.CacheDependencyEntity{})
 		if err != nil {
 			return err
 		}
@@ -141,12 +153,15 @@ func (i *invalidate) Exec(newTx bool) error {
 			return nil
 		}
 
+		//remember the clusters which were using the invalidated cache entries
+		i.clusters = i.uniqueClusters(deps)
+
 		//get cache-entry IDs to invalidate
 		cacheEntityIdsCSV, cntUniqueIds := i.cacheIDsCSV(deps)
 		i.logger.Debugf("Identified %d cache entities which match selector '%v': %s", cntUniqueIds, i.selector, cacheEntityIdsCSV)
 
 		//drop all cache entities
-		cacheQuery, err := db.NewQuery(i.conn, &model.CacheEntryEntity{})
+		cacheQuery, err := db.NewQuery(conn, &model.CacheEntryEntity{})
 		if err != nil {
 			return err
 		}
@@ -157,7 +172,7 @@ func (i *invalidate) Exec(newTx bool) error {
 		i.logger.Debugf("Deleted %d cache entries matching selector '%v'", deletedEntries, i.selector)
 
 		//drop all cache dependencies of the dropped cache entities
-		cacheDepQuery, err := db.NewQuery(i.conn, &model.CacheDependencyEntity{})
+		cacheDepQuery, err := db.NewQuery(conn, &model.CacheDependencyEntity{})
 		if err != nil {
 			return err
 		}
@@ -173,7 +188,26 @@ func (i *invalidate) Exec(newTx bool) error {
 	if newTx { //start new DB transaction
 		return i.transactional("invalidating cache entries", dbOps)
 	}
-	return dbOps() //no new DB transaction requested
+	return dbOps(i.conn) //no new DB transaction requested
+}
+
+//Clusters returns the clusters which were affected by the last execution of the invalidation
+func (i *invalidate) Clusters() []string {
+	return i.clusters
+}
+
+func (i *invalidate) uniqueClusters(deps []db.DatabaseEntity) []string {
+	deduplicate := make(map[string]interface{}, len(deps))
+	var clusters []string
+	for _, dep := range deps {
+		cluster := dep.(*model.CacheDependencyEntity).Cluster
+		if _, ok := deduplicate[cluster]; ok {
+			continue
+		}
+		deduplicate[cluster] = nil
+		clusters = append(clusters, cluster)
+	}
+	return clusters
 }
 
 func (i *invalidate) cacheIDsCSV(deps []db.DatabaseEntity) (string, int) {
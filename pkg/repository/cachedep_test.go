@@ -41,8 +41,10 @@ func TestCacheDependencyManager(t *testing.T) {
 
 	t.Run("Invalidate dependencies by non-existing key", func(t *testing.T) {
 		withTestData(t, func(t *testing.T, testEntries []*model.CacheEntryEntity, testDeps []*model.CacheDependencyEntity) {
-			err := cacheDep.Invalidate().WithKey("key1234").Exec(true)
+			invalidation := cacheDep.Invalidate().WithKey("key1234")
+			err := invalidation.Exec(true)
 			require.NoError(t, err)
+			require.Empty(t, invalidation.Clusters())
 
 			deps, err := cacheDep.Get().Exec()
 			require.NoError(t, err)
@@ -56,8 +58,10 @@ func TestCacheDependencyManager(t *testing.T) {
 
 	t.Run("Invalidate dependencies by key", func(t *testing.T) {
 		withTestData(t, func(t *testing.T, testEntries []*model.CacheEntryEntity, testDeps []*model.CacheDependencyEntity) {
-			err := cacheDep.Invalidate().WithKey("key4").Exec(true)
+			invalidation := cacheDep.Invalidate().WithKey("key4")
+			err := invalidation.Exec(true)
 			require.NoError(t, err)
+			require.Equal(t, []string{"testCluster2"}, invalidation.Clusters())
 
 			deps, err := cacheDep.Get().Exec()
 			require.NoError(t, err)
@@ -36,11 +36,23 @@ func NewRepository(dbFac db.ConnectionFactory, debug bool) (*Repository, error)
 	}, nil
 }
 
-func (r *Repository) TransactionalResult(dbOps func() (interface{}, error)) (interface{}, error) {
+//WithConnection returns a copy of the repository which issues all queries through the given
+//connection (e.g. the transaction handed to the callback of TransactionalResult)
+func (r *Repository) WithConnection(conn db.Connection) *Repository {
+	return &Repository{
+		Conn:     conn,
+		Logger:   r.Logger,
+		CacheDep: r.CacheDep.withConnection(conn),
+	}
+}
+
+//TransactionalResult executes the database operations within a transaction. The operations have
+//to use the passed transaction (e.g. by using WithConnection) to become part of it.
+func (r *Repository) TransactionalResult(dbOps func(tx db.Connection) (interface{}, error)) (interface{}, error) {
 	return db.TransactionResult(r.Conn, dbOps, r.Logger)
 }
 
-func (r *Repository) Transactional(dbOps func() error) error {
+func (r *Repository) Transactional(dbOps func(tx db.Connection) error) error {
 	return db.Transaction(r.Conn, dbOps, r.Logger)
 }
 
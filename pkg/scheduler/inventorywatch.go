@@ -7,6 +7,7 @@ import (
 
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +25,7 @@ type InventoryWatcher interface {
 type InventoryWatchConfig struct {
 	WatchInterval            time.Duration
 	ClusterReconcileInterval time.Duration
+	MaintenanceWindows       *MaintenanceWindows
 }
 
 func (wc *InventoryWatchConfig) validate() error {
@@ -39,7 +41,7 @@ func (wc *InventoryWatchConfig) validate() error {
 	if wc.ClusterReconcileInterval == 0 {
 		wc.ClusterReconcileInterval = defaultClusterReconcileInterval
 	}
-	return nil
+	return wc.MaintenanceWindows.validate()
 }
 
 func NewInventoryWatch(inventory cluster.Inventory, debug bool, config *InventoryWatchConfig) (InventoryWatcher, error) {
@@ -93,7 +95,41 @@ func (w *DefaultInventoryWatcher) processClustersToReconcile(queue InventoryQueu
 			w.logger.Debug("Nil cluster state when processing the list of clusters to reconcile")
 			continue
 		}
+		schedulable, err := w.isSchedulable(clusterState, time.Now())
+		if err != nil {
+			w.logger.Errorf("Failed to evaluate maintenance windows of cluster '%s': %s", clusterState.Cluster.Cluster, err)
+			continue
+		}
+		if !schedulable {
+			w.logger.Debugf("Cluster '%s' is outside of its maintenance windows: postponing reconciliation",
+				clusterState.Cluster.Cluster)
+			continue
+		}
 		w.logger.Debugf("Adding cluster '%s' to reconciliation queue", clusterState.Cluster.Cluster)
 		queue <- *clusterState
 	}
 }
+
+//isSchedulable verifies whether a reconciliation of the cluster is currently allowed: interval-based
+//reconciliations of ready clusters and non-urgent configuration changes are only schedulable within the
+//maintenance windows of the cluster, urgent configuration changes and retries of failed reconciliations always
+func (w *DefaultInventoryWatcher) isSchedulable(clusterState *cluster.State, now time.Time) (bool, error) {
+	if clusterState.Status == nil {
+		return true, nil
+	}
+	switch clusterState.Status.Status {
+	case model.ReconcilePending:
+		if clusterState.Configuration != nil && clusterState.Configuration.Urgent {
+			return true, nil
+		}
+	case model.Ready:
+		//interval-based reconciliation
+	default:
+		return true, nil
+	}
+	windows, err := w.config.MaintenanceWindows.forCluster(clusterState)
+	if err != nil {
+		return false, err
+	}
+	return inMaintenanceWindows(windows, now)
+}
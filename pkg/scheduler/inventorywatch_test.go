@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/stretchr/testify/require"
 )
@@ -45,6 +46,56 @@ func TestInventoryWatch_ShouldStopOnCtxClose(t *testing.T) {
 	require.WithinDuration(t, startTime, time.Now(), 2*time.Second)
 }
 
+func TestInventoryWatch_IsSchedulable(t *testing.T) {
+	//2021-08-14 is a Saturday
+	saturdayNoon := time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC)
+	watcher := &DefaultInventoryWatcher{config: &InventoryWatchConfig{
+		MaintenanceWindows: &MaintenanceWindows{
+			GlobalAccounts: map[string][]keb.MaintenanceWindow{"abc": {{Begin: "01:00", End: "02:00"}}},
+		},
+	}}
+	clusterWindow := `{"maintenanceWindows":[{"days":["sat"],"begin":"10:00","end":"14:00"}]}`
+	globalAccountWindow := `{"globalAccountID":"abc"}`
+
+	tests := []struct {
+		name        string
+		metadata    string
+		status      model.Status
+		urgent      bool
+		now         time.Time
+		schedulable bool
+	}{
+		{"Ready cluster without maintenance windows", `{}`, model.Ready, false, saturdayNoon, true},
+		{"Ready cluster within its maintenance window", clusterWindow, model.Ready, false, saturdayNoon, true},
+		{"Ready cluster outside of its maintenance window", clusterWindow, model.Ready, false, saturdayNoon.Add(3 * time.Hour), false},
+		{"Ready cluster outside of the global account window", globalAccountWindow, model.Ready, false, saturdayNoon, false},
+		{"Ready cluster with urgent configuration", clusterWindow, model.Ready, true, saturdayNoon.Add(3 * time.Hour), false},
+		{"Pending configuration change within maintenance window", clusterWindow, model.ReconcilePending, false, saturdayNoon, true},
+		{"Pending configuration change outside of maintenance window", clusterWindow, model.ReconcilePending, false, saturdayNoon.Add(3 * time.Hour), false},
+		{"Pending urgent configuration change", clusterWindow, model.ReconcilePending, true, saturdayNoon.Add(3 * time.Hour), true},
+		{"Failed reconciliation", globalAccountWindow, model.ReconcileFailed, false, saturdayNoon, true},
+		{"Failed reconciliation of urgent configuration", globalAccountWindow, model.ReconcileFailed, true, saturdayNoon, true},
+		{"Invalid metadata of failed cluster", `{`, model.ReconcileFailed, false, saturdayNoon, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := stateWithMetadata(test.metadata)
+			state.Status.Status = test.status
+			state.Configuration.Urgent = test.urgent
+			schedulable, err := watcher.isSchedulable(state, test.now)
+			require.NoError(t, err)
+			require.Equal(t, test.schedulable, schedulable)
+		})
+	}
+
+	t.Run("Invalid metadata of ready cluster", func(t *testing.T) {
+		state := stateWithMetadata(`{`)
+		state.Status.Status = model.Ready
+		_, err := watcher.isSchedulable(state, saturdayNoon)
+		require.Error(t, err)
+	})
+}
+
 func mockState() *cluster.State {
 	return &cluster.State{
 		Cluster:       &model.ClusterEntity{},
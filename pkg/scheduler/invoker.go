@@ -25,14 +25,24 @@ type InvokeParams struct {
 	InstallCRD           bool
 }
 
+//componentVersion returns the version override of the component or the Kyma version of the cluster
+func (p *InvokeParams) componentVersion() string {
+	if p.ComponentToReconcile.Version != "" {
+		return p.ComponentToReconcile.Version
+	}
+	return p.ClusterState.Configuration.KymaVersion
+}
+
 type ReconcilerInvoker interface {
 	Invoke(params *InvokeParams) error
 }
 
 type RemoteReconcilerInvoker struct {
-	logger         *zap.SugaredLogger
-	mothershipHost string
-	mothershipPort int
+	logger           *zap.SugaredLogger
+	mothershipScheme string
+	mothershipHost   string
+	mothershipPort   int
+	callbackToken    string //only sent if the callback URL uses https
 }
 
 func (rri *RemoteReconcilerInvoker) Invoke(params *InvokeParams) error {
@@ -42,13 +52,14 @@ func (rri *RemoteReconcilerInvoker) Invoke(params *InvokeParams) error {
 		ComponentsReady: params.ComponentsReady,
 		Component:       component,
 		Namespace:       params.ComponentToReconcile.Namespace,
-		Version:         params.ClusterState.Configuration.KymaVersion,
+		Version:         params.componentVersion(),
 		Profile:         params.ClusterState.Configuration.KymaProfile,
 		Configuration:   mapConfiguration(params.ComponentToReconcile.Configuration),
 		Kubeconfig:      params.ClusterState.Cluster.Kubeconfig,
-		CallbackURL:     fmt.Sprintf("http://%s:%d/v1/operations/%s/callback/%s", rri.mothershipHost, rri.mothershipPort, params.SchedulingID, params.CorrelationID), // TODO: parametrize the URL
+		CallbackURL:     fmt.Sprintf("%s://%s:%d/v1/operations/%s/callback/%s", rri.mothershipScheme, rri.mothershipHost, rri.mothershipPort, params.SchedulingID, params.CorrelationID), // TODO: parametrize the URL
 		InstallCRD:      params.InstallCRD,
 		CorrelationID:   params.CorrelationID,
+		CallbackToken:   rri.callbackToken,
 	}
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -116,7 +127,7 @@ func (lri *LocalReconcilerInvoker) Invoke(params *InvokeParams) error {
 		ComponentsReady: params.ComponentsReady,
 		Component:       component,
 		Namespace:       params.ComponentToReconcile.Namespace,
-		Version:         params.ClusterState.Configuration.KymaVersion,
+		Version:         params.componentVersion(),
 		Profile:         params.ClusterState.Configuration.KymaProfile,
 		Configuration:   mapConfiguration(params.ComponentToReconcile.Configuration),
 		Kubeconfig:      params.ClusterState.Cluster.Kubeconfig,
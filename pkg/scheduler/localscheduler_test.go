@@ -6,7 +6,6 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/workspace"
 	"testing"
 
-	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
@@ -65,14 +64,13 @@ func newLocalScheduler(t *testing.T) Scheduler {
 		},
 	}
 
-	ls, err := NewLocalScheduler(kebCluster, newWorkerFactory(t), true)
+	ls, err := NewLocalScheduler(kebCluster, newTestInventory(t), newWorkerFactory(t), true)
 	require.NoError(t, err)
 	return ls
 }
 
 func newWorkerFactory(t *testing.T) WorkerFactory {
 	workerFactory, err := NewLocalWorkerFactory(
-		&cluster.MockInventory{},
 		NewDefaultOperationsRegistry(),
 		func(component string, status reconciler.Status) {
 			t.Logf("Component %s has status %s", component, status)
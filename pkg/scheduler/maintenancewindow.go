@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+)
+
+const maintenanceWindowTimeLayout = "15:04"
+
+//MaintenanceWindows contains the maintenance windows which are used as fallback if a cluster
+//doesn't define its own windows in its metadata
+type MaintenanceWindows struct {
+	GlobalAccounts map[string][]keb.MaintenanceWindow //maintenance windows per global account ID
+}
+
+func (mw *MaintenanceWindows) validate() error {
+	if mw == nil {
+		return nil
+	}
+	for globalAccount, windows := range mw.GlobalAccounts {
+		for _, window := range windows {
+			if _, err := newMaintenanceWindow(window); err != nil {
+				return fmt.Errorf("maintenance window of global account '%s' is invalid: %s", globalAccount, err)
+			}
+		}
+	}
+	return nil
+}
+
+//forCluster returns the maintenance windows which apply to the cluster: windows defined in the
+//cluster metadata have precedence over the windows configured for its global account
+func (mw *MaintenanceWindows) forCluster(state *cluster.State) ([]keb.MaintenanceWindow, error) {
+	metadata, err := state.Cluster.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.MaintenanceWindows) > 0 {
+		return metadata.MaintenanceWindows, nil
+	}
+	if mw == nil || metadata.GlobalAccountID == "" {
+		return nil, nil
+	}
+	for globalAccount, windows := range mw.GlobalAccounts {
+		//viper converts map keys to lower case: compare global account IDs case-insensitive
+		if strings.EqualFold(globalAccount, metadata.GlobalAccountID) {
+			return windows, nil
+		}
+	}
+	return nil, nil
+}
+
+//inMaintenanceWindows returns true if no maintenance windows are defined or
+//if the given time lies within one of the maintenance windows
+func inMaintenanceWindows(windows []keb.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+	for _, window := range windows {
+		mw, err := newMaintenanceWindow(window)
+		if err != nil {
+			return false, err
+		}
+		if mw.contains(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type maintenanceWindow struct {
+	days     map[time.Weekday]bool
+	begin    time.Duration //offset since midnight
+	end      time.Duration //offset since midnight
+	location *time.Location
+}
+
+func newMaintenanceWindow(window keb.MaintenanceWindow) (*maintenanceWindow, error) {
+	begin, err := parseTimeOfDay(window.Begin)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTimeOfDay(window.End)
+	if err != nil {
+		return nil, err
+	}
+	if begin == end {
+		return nil, fmt.Errorf("begin and end of maintenance window cannot be equal (got '%s')", window.Begin)
+	}
+	location := time.UTC
+	if window.Timezone != "" {
+		location, err = time.LoadLocation(window.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("timezone '%s' of maintenance window is invalid: %s", window.Timezone, err)
+		}
+	}
+	days := make(map[time.Weekday]bool, len(window.Days))
+	for _, day := range window.Days {
+		weekday, err := parseWeekday(day)
+		if err != nil {
+			return nil, err
+		}
+		days[weekday] = true
+	}
+	return &maintenanceWindow{
+		days:     days,
+		begin:    begin,
+		end:      end,
+		location: location,
+	}, nil
+}
+
+func (mw *maintenanceWindow) contains(t time.Time) bool {
+	local := t.In(mw.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, mw.location)
+	offset := local.Sub(midnight)
+
+	if mw.begin < mw.end {
+		return mw.matchesDay(local.Weekday()) && offset >= mw.begin && offset < mw.end
+	}
+
+	//window spans midnight: it belongs to the day where it begins
+	if offset >= mw.begin {
+		return mw.matchesDay(local.Weekday())
+	}
+	if offset < mw.end {
+		return mw.matchesDay(midnight.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+func (mw *maintenanceWindow) matchesDay(weekday time.Weekday) bool {
+	return len(mw.days) == 0 || mw.days[weekday]
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse(maintenanceWindowTimeLayout, strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("time '%s' of maintenance window is invalid: expected format is 'HH:MM'", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	day := strings.ToLower(strings.TrimSpace(value))
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if day == name || day == name[:3] {
+			return weekday, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("weekday '%s' of maintenance window is invalid", value)
+}
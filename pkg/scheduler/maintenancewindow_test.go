@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	//2021-08-14 is a Saturday
+	saturdayNoon := time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC)
+
+	t.Run("No maintenance windows", func(t *testing.T) {
+		inWindow, err := inMaintenanceWindows(nil, saturdayNoon)
+		require.NoError(t, err)
+		require.True(t, inWindow)
+	})
+
+	t.Run("Time range within a day", func(t *testing.T) {
+		windows := []keb.MaintenanceWindow{{Days: []string{"sat"}, Begin: "10:00", End: "14:00"}}
+
+		inWindow, err := inMaintenanceWindows(windows, saturdayNoon)
+		require.NoError(t, err)
+		require.True(t, inWindow)
+
+		inWindow, err = inMaintenanceWindows(windows, saturdayNoon.Add(3*time.Hour))
+		require.NoError(t, err)
+		require.False(t, inWindow)
+
+		inWindow, err = inMaintenanceWindows(windows, saturdayNoon.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.False(t, inWindow)
+	})
+
+	t.Run("Time range spanning midnight", func(t *testing.T) {
+		windows := []keb.MaintenanceWindow{{Days: []string{"Friday"}, Begin: "22:00", End: "04:00"}}
+
+		inWindow, err := inMaintenanceWindows(windows, saturdayNoon.Add(-13*time.Hour)) //Friday 23:00
+		require.NoError(t, err)
+		require.True(t, inWindow)
+
+		inWindow, err = inMaintenanceWindows(windows, saturdayNoon.Add(-9*time.Hour)) //Saturday 03:00
+		require.NoError(t, err)
+		require.True(t, inWindow)
+
+		inWindow, err = inMaintenanceWindows(windows, saturdayNoon.Add(-8*time.Hour)) //Saturday 04:00
+		require.NoError(t, err)
+		require.False(t, inWindow)
+	})
+
+	t.Run("Timezone is considered", func(t *testing.T) {
+		windows := []keb.MaintenanceWindow{{Begin: "13:00", End: "15:00", Timezone: "Europe/Berlin"}} //UTC+2 in summer
+
+		inWindow, err := inMaintenanceWindows(windows, saturdayNoon)
+		require.NoError(t, err)
+		require.True(t, inWindow)
+
+		inWindow, err = inMaintenanceWindows(windows, saturdayNoon.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.False(t, inWindow)
+	})
+
+	t.Run("Invalid maintenance windows", func(t *testing.T) {
+		for _, window := range []keb.MaintenanceWindow{
+			{Begin: "25:00", End: "04:00"},
+			{Begin: "02:00", End: "02:00"},
+			{Begin: "02:00", End: "04:00", Days: []string{"someday"}},
+			{Begin: "02:00", End: "04:00", Timezone: "Mars/Olympus"},
+		} {
+			_, err := inMaintenanceWindows([]keb.MaintenanceWindow{window}, saturdayNoon)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("Cluster windows have precedence over global account windows", func(t *testing.T) {
+		globalAccountWindow := keb.MaintenanceWindow{Begin: "01:00", End: "02:00"}
+		clusterWindow := keb.MaintenanceWindow{Begin: "03:00", End: "04:00"}
+		mw := &MaintenanceWindows{
+			GlobalAccounts: map[string][]keb.MaintenanceWindow{"abc": {globalAccountWindow}},
+		}
+
+		windows, err := mw.forCluster(stateWithMetadata(`{"globalAccountID":"ABC"}`))
+		require.NoError(t, err)
+		require.Equal(t, []keb.MaintenanceWindow{globalAccountWindow}, windows)
+
+		windows, err = mw.forCluster(stateWithMetadata(
+			`{"globalAccountID":"abc","maintenanceWindows":[{"begin":"03:00","end":"04:00"}]}`))
+		require.NoError(t, err)
+		require.Equal(t, []keb.MaintenanceWindow{clusterWindow}, windows)
+
+		windows, err = mw.forCluster(stateWithMetadata(`{"globalAccountID":"xyz"}`))
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+}
+
+func stateWithMetadata(metadata string) *cluster.State {
+	state := mockState()
+	state.Cluster = &model.ClusterEntity{
+		Metadata: metadata,
+		Contract: 1,
+	}
+	return state
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const defaultRetentionInterval = 1 * time.Hour
+
+type RetentionConfig struct {
+	Interval time.Duration
+	Policy   *cluster.RetentionPolicy
+}
+
+func (rc *RetentionConfig) validate() error {
+	if rc.Interval < 0 {
+		return fmt.Errorf("Retention interval cannot be < 0")
+	}
+	if rc.Interval == 0 {
+		rc.Interval = defaultRetentionInterval
+	}
+	if rc.Policy == nil {
+		rc.Policy = &cluster.RetentionPolicy{}
+	}
+	return rc.Policy.Validate()
+}
+
+type purgeMetricsCollector interface {
+	OnInventoryPurge(result *cluster.PurgeResult)
+}
+
+//RetentionJob purges outdated entries from the cluster inventory in a regular interval
+type RetentionJob struct {
+	inventory cluster.Inventory
+	collector purgeMetricsCollector
+	config    *RetentionConfig
+	logger    *zap.SugaredLogger
+}
+
+func NewRetentionJob(inventory cluster.Inventory, collector purgeMetricsCollector, debug bool, config *RetentionConfig) (*RetentionJob, error) {
+	logger, err := logger.NewLogger(debug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return &RetentionJob{
+		inventory: inventory,
+		collector: collector,
+		config:    config,
+		logger:    logger}, nil
+}
+
+func (j *RetentionJob) Run(ctx context.Context) error {
+	ticker := time.NewTicker(j.config.Interval)
+	j.logger.Debugf("Start inventory retention job with an interval of %.1f secs", j.config.Interval.Seconds())
+	j.purge()
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Debug("Stopping inventory retention job because parent context got closed")
+			ticker.Stop()
+			return nil
+		case <-ticker.C:
+			j.purge()
+		}
+	}
+}
+
+func (j *RetentionJob) purge() {
+	result, err := j.inventory.Purge(j.config.Policy)
+	if err != nil {
+		j.logger.Errorf("Error while purging outdated entries from inventory: %s", err)
+		return
+	}
+	if result == nil {
+		return
+	}
+	j.logger.Infof("Inventory retention job removed %d clusters, %d configurations, %d statuses and %d rollbacks",
+		result.Clusters, result.Configurations, result.Statuses, result.Rollbacks)
+	if j.collector != nil {
+		j.collector.OnInventoryPurge(result)
+	}
+}
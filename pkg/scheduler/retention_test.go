@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePurgeCollector struct {
+	results chan *cluster.PurgeResult
+}
+
+func (c *fakePurgeCollector) OnInventoryPurge(result *cluster.PurgeResult) {
+	c.results <- result
+}
+
+func TestRetentionJob(t *testing.T) {
+	t.Run("Invalid retention policy", func(t *testing.T) {
+		_, err := NewRetentionJob(&cluster.MockInventory{}, nil, true, &RetentionConfig{
+			Policy: &cluster.RetentionPolicy{ConfigVersions: -1},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Purge results are reported to metrics collector", func(t *testing.T) {
+		inventory := &cluster.MockInventory{
+			PurgeResult: &cluster.PurgeResult{Configurations: 3, Statuses: 5},
+		}
+		collector := &fakePurgeCollector{results: make(chan *cluster.PurgeResult, 1)}
+		ctx, cancelFn := context.WithCancel(context.TODO())
+		defer cancelFn()
+
+		retentionJob, err := NewRetentionJob(inventory, collector, true, &RetentionConfig{Interval: 500 * time.Millisecond})
+		require.NoError(t, err)
+
+		go func(ctx context.Context) {
+			require.NoError(t, retentionJob.Run(ctx))
+		}(ctx)
+
+		require.Equal(t, inventory.PurgeResult, <-collector.results)
+	})
+}
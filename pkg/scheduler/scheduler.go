@@ -2,7 +2,6 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
@@ -14,6 +13,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"sync"
+	"sync/atomic"
 )
 
 type concurrency bool
@@ -30,15 +30,33 @@ type Scheduler interface {
 	Run(ctx context.Context) error
 }
 
+//TriggerExecutor runs the pending configuration triggers of a cluster
+type TriggerExecutor interface {
+	ExecTriggers(cluster string, phase model.TriggerPhase) error
+}
+
+//StatusUpdater updates the status of a cluster with compare-and-set semantics
+type StatusUpdater interface {
+	UpdateStatus(state *cluster.State, status model.Status) (*cluster.State, error)
+}
+
+//ConfigurationProvider returns the configuration entries which are merged from the configuration buckets of a cluster
+type ConfigurationProvider interface {
+	Configuration(state *cluster.State) ([]keb.Configuration, error)
+}
+
 type RemoteScheduler struct {
 	inventoryWatch InventoryWatcher
 	workerFactory  WorkerFactory
+	statusUpdater  StatusUpdater
+	triggers       TriggerExecutor
+	configProvider ConfigurationProvider
 	mothershipCfg  reconciler.MothershipReconcilerConfig
 	poolSize       int
 	logger         *zap.SugaredLogger
 }
 
-func NewRemoteScheduler(inventoryWatch InventoryWatcher, workerFactory WorkerFactory, mothershipCfg reconciler.MothershipReconcilerConfig, workers int, debug bool) (Scheduler, error) {
+func NewRemoteScheduler(inventoryWatch InventoryWatcher, workerFactory WorkerFactory, statusUpdater StatusUpdater, triggers TriggerExecutor, configProvider ConfigurationProvider, mothershipCfg reconciler.MothershipReconcilerConfig, workers int, debug bool) (Scheduler, error) {
 	l, err := logger.NewLogger(debug)
 	if err != nil {
 		return nil, err
@@ -46,6 +64,9 @@ func NewRemoteScheduler(inventoryWatch InventoryWatcher, workerFactory WorkerFac
 	return &RemoteScheduler{
 		inventoryWatch: inventoryWatch,
 		workerFactory:  workerFactory,
+		statusUpdater:  statusUpdater,
+		triggers:       triggers,
+		configProvider: configProvider,
 		mothershipCfg:  mothershipCfg,
 		poolSize:       workers,
 		logger:         l,
@@ -111,42 +132,108 @@ func (rs *RemoteScheduler) schedule(state cluster.State) {
 		return
 	}
 
+	components, err = rs.mergeConfiguration(state, components)
+	if err != nil {
+		rs.logger.Errorf("Failed to merge bucket configuration for cluster %s: %s", state.Cluster.Cluster, err)
+		return
+	}
+
+	//all component workers share the cluster status: it's updated once per scheduling
+	if !rs.updateStatus(&state, model.Reconciling) {
+		return
+	}
+
+	rs.execTriggers(state.Cluster.Cluster, model.TriggerPreReconciliation)
+
+	var wg sync.WaitGroup
+	var failures int32
+
 	//Reconcile CRD components first
 	for _, component := range components {
 		if rs.isCRDComponent(component.Component) {
-			rs.reconcile(component, state, schedulingID, doInstallCRD, concurrencyNotAllowed)
+			rs.reconcile(component, state, schedulingID, doInstallCRD, concurrencyNotAllowed, &wg, &failures)
 		}
 	}
 
 	//Reconcile pre components
 	for _, component := range components {
 		if rs.isPreComponent(component.Component) {
-			rs.reconcile(component, state, schedulingID, doNotInstallCRD, concurrencyNotAllowed)
+			rs.reconcile(component, state, schedulingID, doNotInstallCRD, concurrencyNotAllowed, &wg, &failures)
 		}
 	}
 
-	//Reconcile the rest
+	//Reconcile the rest: components are reconciled after the components they depend on
+	var rest []*keb.Components
 	for _, component := range components {
 		if rs.isPreComponent(component.Component) || rs.isCRDComponent(component.Component) {
 			continue
 		}
-		rs.reconcile(component, state, schedulingID, doNotInstallCRD, concurrencyAllowed)
+		rest = append(rest, component)
+	}
+	levels, err := keb.DependencyLevels(rest)
+	if err != nil {
+		rs.logger.Errorf("Failed to order components of cluster %s by their dependencies: %s", state.Cluster.Cluster, err)
+		atomic.AddInt32(&failures, 1)
+	}
+	for idx, level := range levels {
+		if idx > 0 {
+			wg.Wait()
+			if atomic.LoadInt32(&failures) > 0 {
+				rs.logger.Infof("Skipping remaining components of cluster %s because reconciliations failed", state.Cluster.Cluster)
+				break
+			}
+		}
+		for _, component := range level {
+			rs.reconcile(component, state, schedulingID, doNotInstallCRD, concurrencyAllowed, &wg, &failures)
+		}
 	}
+
+	//post-reconciliation triggers are kept until all components were successfully reconciled
+	wg.Wait()
+	if atomic.LoadInt32(&failures) > 0 {
+		//failed clusters are picked up again by the inventory watcher
+		rs.updateStatus(&state, model.ReconcileFailed)
+		return
+	}
+	rs.execTriggers(state.Cluster.Cluster, model.TriggerPostReconciliation)
+	rs.updateStatus(&state, model.Ready)
 }
 
-func (rs *RemoteScheduler) reconcile(component *keb.Components, state cluster.State, schedulingID string, installCRD bool, concurrent concurrency) {
+//updateStatus sets the status of the cluster and returns false if the update failed or was dropped because
+//the status was changed concurrently (e.g. a newer configuration is pending or another scheduler took over)
+func (rs *RemoteScheduler) updateStatus(state *cluster.State, status model.Status) bool {
+	if rs.statusUpdater == nil {
+		return true
+	}
+	_, err := rs.statusUpdater.UpdateStatus(state, status)
+	if cluster.IsStatusConflictError(err) {
+		rs.logger.Infof("Dropping outdated status update of cluster %s to '%s': %s", state.Cluster.Cluster, status, err)
+		return false
+	}
+	if err != nil {
+		rs.logger.Errorf("Failed to update status of cluster %s to '%s': %s", state.Cluster.Cluster, status, err)
+		return false
+	}
+	return true
+}
+
+func (rs *RemoteScheduler) reconcile(component *keb.Components, state cluster.State, schedulingID string, installCRD bool, concurrent concurrency, wg *sync.WaitGroup, failures *int32) {
 	fn := func(component *keb.Components, state cluster.State, schedulingID string) {
+		defer wg.Done()
 		worker, err := rs.workerFactory.ForComponent(component.Component)
 		if err != nil {
 			rs.logger.Errorf("Error creating worker for component: %s", err)
+			atomic.AddInt32(failures, 1)
 			return
 		}
 		err = worker.Reconcile(component, state, schedulingID, installCRD)
 		if err != nil {
 			rs.logger.Errorf("Error while reconciling component %s: %s", component.Component, err)
+			atomic.AddInt32(failures, 1)
 		}
 	}
 
+	wg.Add(1)
 	if bool(concurrent) {
 		go fn(component, state, schedulingID)
 	} else {
@@ -154,6 +241,34 @@ func (rs *RemoteScheduler) reconcile(component *keb.Components, state cluster.St
 	}
 }
 
+//mergeConfiguration adds the configuration of the cluster's buckets to the component configurations
+func (rs *RemoteScheduler) mergeConfiguration(state cluster.State, components []*keb.Components) ([]*keb.Components, error) {
+	if rs.configProvider == nil {
+		return components, nil
+	}
+	configuration, err := rs.configProvider.Configuration(&state)
+	if err != nil {
+		return nil, err
+	}
+	if len(configuration) == 0 {
+		return components, nil
+	}
+	result := make([]*keb.Components, 0, len(components))
+	for _, component := range components {
+		result = append(result, cluster.MergeConfiguration(component, configuration))
+	}
+	return result, nil
+}
+
+func (rs *RemoteScheduler) execTriggers(cluster string, phase model.TriggerPhase) {
+	if rs.triggers == nil {
+		return
+	}
+	if err := rs.triggers.ExecTriggers(cluster, phase); err != nil {
+		rs.logger.Errorf("Failed to execute %s-triggers of cluster %s: %s", phase, cluster, err)
+	}
+}
+
 func (rs *RemoteScheduler) isCRDComponent(component string) bool {
 	for _, c := range rs.mothershipCfg.CrdComponents {
 		if component == c {
@@ -172,19 +287,27 @@ func (rs *RemoteScheduler) isPreComponent(component string) bool {
 	return false
 }
 
+//localClusterName is used as runtime ID if the cluster of a local run has none
+const localClusterName = "local"
+
 type LocalScheduler struct {
 	cluster       keb.Cluster
+	inventory     cluster.Inventory
 	workerFactory WorkerFactory
 	logger        *zap.SugaredLogger
 }
 
-func NewLocalScheduler(cluster keb.Cluster, workerFactory WorkerFactory, debug bool) (Scheduler, error) {
+func NewLocalScheduler(cluster keb.Cluster, inventory cluster.Inventory, workerFactory WorkerFactory, debug bool) (Scheduler, error) {
 	log, err := logger.NewLogger(debug)
 	if err != nil {
 		return nil, err
 	}
+	if cluster.Cluster == "" {
+		cluster.Cluster = localClusterName
+	}
 	return &LocalScheduler{
 		cluster:       cluster,
+		inventory:     inventory,
 		workerFactory: workerFactory,
 		logger:        log,
 	}, nil
@@ -193,36 +316,56 @@ func NewLocalScheduler(cluster keb.Cluster, workerFactory WorkerFactory, debug b
 func (ls *LocalScheduler) Run(ctx context.Context) error {
 	schedulingID := uuid.NewString()
 
-	clusterState, err := localClusterState(&ls.cluster)
+	//the cluster and its statuses are tracked in the inventory like in the mothership
+	clusterState, err := ls.inventory.CreateOrUpdate(keb.LatestContract, &ls.cluster)
 	if err != nil {
-		return fmt.Errorf("failed to convert to cluster state: %s", err)
+		return fmt.Errorf("failed to add cluster to inventory: %s", err)
+	}
+	if clusterState, err = ls.inventory.UpdateStatus(clusterState, model.Reconciling); err != nil {
+		return fmt.Errorf("failed to update cluster status: %s", err)
 	}
 
+	err = ls.reconcile(*clusterState, schedulingID)
+	status := model.Ready
+	if err != nil {
+		status = model.ReconcileFailed
+	}
+	if _, statusErr := ls.inventory.UpdateStatus(clusterState, status); statusErr != nil {
+		ls.logger.Errorf("Failed to update status of cluster %s to '%s': %s", clusterState.Cluster.Cluster, status, statusErr)
+	}
+	return err
+}
+
+func (ls *LocalScheduler) reconcile(clusterState cluster.State, schedulingID string) error {
 	components, err := clusterState.Configuration.GetComponents()
 	if err != nil {
 		return fmt.Errorf("failed to get components: %s", err)
 	}
 
+	workers := make([]ReconciliationWorker, 0, len(components))
+	for _, component := range components {
+		worker, err := ls.workerFactory.ForComponent(component.Component)
+		if err != nil {
+			return fmt.Errorf("failed to create a worker for component %s: %s", component.Component, err)
+		}
+		workers = append(workers, worker)
+	}
+
 	results := make(chan error, len(components))
 
 	var wg sync.WaitGroup
 	wg.Add(len(components))
 
 	//trigger all component reconcilers
-	for _, component := range components {
-		worker, err := ls.workerFactory.ForComponent(component.Component)
-		if err != nil {
-			return fmt.Errorf("failed to create a: %s", err)
-		}
-
-		go func(component *keb.Components, state cluster.State, schedulingID string) {
+	for idx, component := range components {
+		go func(worker ReconciliationWorker, component *keb.Components, state cluster.State, schedulingID string) {
 			defer wg.Done()
 			err := worker.Reconcile(component, state, schedulingID, true)
 			if err != nil {
 				ls.logger.Errorf("Error while reconciling component %s: %s", component.Component, err)
 			}
 			results <- err
-		}(component, *clusterState, schedulingID)
+		}(workers[idx], component, clusterState, schedulingID)
 	}
 
 	wg.Wait()
@@ -237,46 +380,3 @@ func (ls *LocalScheduler) Run(ctx context.Context) error {
 
 	return nil
 }
-
-func localClusterState(c *keb.Cluster) (*cluster.State, error) {
-	var defaultContractVersion int64 = 1
-
-	metadata, err := json.Marshal(c.Metadata)
-	if err != nil {
-		return nil, err
-	}
-	runtime, err := json.Marshal(c.RuntimeInput)
-	if err != nil {
-		return nil, err
-	}
-	clusterEntity := &model.ClusterEntity{
-		Cluster:    c.Cluster,
-		Runtime:    string(runtime),
-		Metadata:   string(metadata),
-		Kubeconfig: c.Kubeconfig,
-		Contract:   defaultContractVersion,
-	}
-
-	components, err := json.Marshal(c.KymaConfig.Components)
-	if err != nil {
-		return nil, err
-	}
-	administrators, err := json.Marshal(c.KymaConfig.Administrators)
-	if err != nil {
-		return nil, err
-	}
-	configurationEntity := &model.ClusterConfigurationEntity{
-		Cluster:        c.Cluster,
-		KymaVersion:    c.KymaConfig.Version,
-		KymaProfile:    c.KymaConfig.Profile,
-		Components:     string(components),
-		Administrators: string(administrators),
-		Contract:       defaultContractVersion,
-	}
-
-	return &cluster.State{
-		Cluster:       clusterEntity,
-		Configuration: configurationEntity,
-		Status:        &model.ClusterStatusEntity{},
-	}, nil
-}
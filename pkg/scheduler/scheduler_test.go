@@ -3,10 +3,13 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"github.com/kyma-incubator/reconciler/pkg/db"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/kyma-incubator/reconciler/pkg/model"
@@ -64,31 +67,386 @@ func TestRemoteScheduler(t *testing.T) {
 	workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
 }
 
+func TestRemoteSchedulerTriggers(t *testing.T) {
+	components := []keb.Components{
+		{Component: "logging"},
+		{Component: "monitoring"},
+	}
+	componentsJSON, _ := json.Marshal(components)
+
+	state := cluster.State{
+		Cluster: &model.ClusterEntity{Cluster: "triggerCluster"},
+		Configuration: &model.ClusterConfigurationEntity{
+			Contract:   1,
+			Components: string(componentsJSON),
+		},
+	}
+
+	l, _ := logger.NewLogger(true)
+
+	t.Run("Run pre- and post-reconciliation triggers", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		triggersMock := &MockTriggerExecutor{}
+		triggersMock.On("ExecTriggers", "triggerCluster", mock.Anything).Return(nil)
+
+		sut := RemoteScheduler{
+			workerFactory: workerFactoryMock,
+			triggers:      triggersMock,
+			logger:        l,
+		}
+		sut.schedule(state)
+
+		workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
+		triggersMock.AssertCalled(t, "ExecTriggers", "triggerCluster", model.TriggerPreReconciliation)
+		triggersMock.AssertCalled(t, "ExecTriggers", "triggerCluster", model.TriggerPostReconciliation)
+	})
+
+	t.Run("Skip post-reconciliation triggers if a component failed", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("reconciliation failed"))
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		triggersMock := &MockTriggerExecutor{}
+		triggersMock.On("ExecTriggers", "triggerCluster", mock.Anything).Return(nil)
+
+		sut := RemoteScheduler{
+			workerFactory: workerFactoryMock,
+			triggers:      triggersMock,
+			logger:        l,
+		}
+		sut.schedule(state)
+
+		workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
+		triggersMock.AssertCalled(t, "ExecTriggers", "triggerCluster", model.TriggerPreReconciliation)
+		triggersMock.AssertNotCalled(t, "ExecTriggers", "triggerCluster", model.TriggerPostReconciliation)
+	})
+}
+
+func TestRemoteSchedulerDependencies(t *testing.T) {
+	components := []keb.Components{
+		{Component: "tracing", Dependencies: []string{"monitoring"}},
+		{Component: "monitoring", Dependencies: []string{"logging"}},
+		{Component: "logging"},
+	}
+	componentsJSON, _ := json.Marshal(components)
+
+	state := cluster.State{
+		Cluster: &model.ClusterEntity{Cluster: "dependencyCluster"},
+		Configuration: &model.ClusterConfigurationEntity{
+			Contract:   keb.ContractV2,
+			Components: string(componentsJSON),
+		},
+	}
+
+	l, _ := logger.NewLogger(true)
+
+	newScheduler := func(failing string) (*RemoteScheduler, func() []string) {
+		var m sync.Mutex
+		var reconciled []string
+		workerFactoryMock := &MockWorkerFactory{}
+		for _, component := range components {
+			var err error
+			if component.Component == failing {
+				err = fmt.Errorf("reconciliation failed")
+			}
+			name := component.Component
+			workerMock := &MockReconciliationWorker{}
+			workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(err).
+				Run(func(args mock.Arguments) {
+					m.Lock()
+					defer m.Unlock()
+					reconciled = append(reconciled, name)
+				})
+			workerFactoryMock.On("ForComponent", name).Return(workerMock, nil)
+		}
+		return &RemoteScheduler{workerFactory: workerFactoryMock, logger: l}, func() []string {
+			m.Lock()
+			defer m.Unlock()
+			return reconciled
+		}
+	}
+
+	t.Run("Reconcile components after their dependencies", func(t *testing.T) {
+		sut, reconciled := newScheduler("")
+		sut.schedule(state)
+		require.Equal(t, []string{"logging", "monitoring", "tracing"}, reconciled())
+	})
+
+	t.Run("Skip components if a dependency failed", func(t *testing.T) {
+		sut, reconciled := newScheduler("monitoring")
+		sut.schedule(state)
+		require.Equal(t, []string{"logging", "monitoring"}, reconciled())
+	})
+}
+
+func TestRemoteSchedulerStatus(t *testing.T) {
+	components := []keb.Components{
+		{Component: "logging"},
+		{Component: "monitoring"},
+	}
+	componentsJSON, _ := json.Marshal(components)
+
+	state := cluster.State{
+		Cluster: &model.ClusterEntity{Cluster: "statusCluster"},
+		Configuration: &model.ClusterConfigurationEntity{
+			Contract:   1,
+			Components: string(componentsJSON),
+		},
+		Status: &model.ClusterStatusEntity{ID: 1},
+	}
+
+	l, _ := logger.NewLogger(true)
+
+	t.Run("Update status once per scheduling", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		statusUpdaterMock := &MockStatusUpdater{}
+		statusUpdaterMock.On("UpdateStatus", mock.Anything, mock.Anything).Return(&state, nil)
+
+		sut := RemoteScheduler{
+			workerFactory: workerFactoryMock,
+			statusUpdater: statusUpdaterMock,
+			logger:        l,
+		}
+		sut.schedule(state)
+
+		workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
+		statusUpdaterMock.AssertNumberOfCalls(t, "UpdateStatus", 2)
+		statusUpdaterMock.AssertCalled(t, "UpdateStatus", mock.Anything, model.Reconciling)
+		statusUpdaterMock.AssertCalled(t, "UpdateStatus", mock.Anything, model.Ready)
+	})
+
+	t.Run("Set failed status if a component failed", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("reconciliation failed"))
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		statusUpdaterMock := &MockStatusUpdater{}
+		statusUpdaterMock.On("UpdateStatus", mock.Anything, mock.Anything).Return(&state, nil)
+
+		sut := RemoteScheduler{
+			workerFactory: workerFactoryMock,
+			statusUpdater: statusUpdaterMock,
+			logger:        l,
+		}
+		sut.schedule(state)
+
+		statusUpdaterMock.AssertNumberOfCalls(t, "UpdateStatus", 2)
+		statusUpdaterMock.AssertCalled(t, "UpdateStatus", mock.Anything, model.Reconciling)
+		statusUpdaterMock.AssertCalled(t, "UpdateStatus", mock.Anything, model.ReconcileFailed)
+		statusUpdaterMock.AssertNotCalled(t, "UpdateStatus", mock.Anything, model.Ready)
+	})
+
+	t.Run("Drop scheduling if status was changed concurrently", func(t *testing.T) {
+		workerFactoryMock := &MockWorkerFactory{}
+
+		statusUpdaterMock := &MockStatusUpdater{}
+		statusUpdaterMock.On("UpdateStatus", mock.Anything, model.Reconciling).Return(&state, &cluster.StatusConflictError{
+			Cluster:          "statusCluster",
+			ExpectedStatusID: 1,
+			CurrentStatus:    &model.ClusterStatusEntity{ID: 2, Status: model.Reconciling},
+		})
+
+		sut := RemoteScheduler{
+			workerFactory: workerFactoryMock,
+			statusUpdater: statusUpdaterMock,
+			logger:        l,
+		}
+		sut.schedule(state)
+
+		workerFactoryMock.AssertNotCalled(t, "ForComponent", mock.Anything)
+	})
+}
+
+func TestRemoteSchedulerBucketConfiguration(t *testing.T) {
+	components := []keb.Components{
+		{Component: "logging", Configuration: []keb.Configuration{{Key: "a", Value: "component"}}},
+	}
+	componentsJSON, _ := json.Marshal(components)
+
+	state := cluster.State{
+		Cluster: &model.ClusterEntity{Cluster: "bucketCluster"},
+		Configuration: &model.ClusterConfigurationEntity{
+			Contract:   1,
+			Components: string(componentsJSON),
+		},
+	}
+
+	l, _ := logger.NewLogger(true)
+
+	t.Run("Merge bucket configuration into components", func(t *testing.T) {
+		expectedComponent := &keb.Components{
+			Component: "logging",
+			Configuration: []keb.Configuration{
+				{Key: "a", Value: "component"},
+				{Key: "b", Value: "bucket"},
+			},
+		}
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", expectedComponent, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", "logging").Return(workerMock, nil)
+
+		configProviderMock := &MockConfigurationProvider{}
+		configProviderMock.On("Configuration", mock.Anything).Return([]keb.Configuration{
+			{Key: "a", Value: "bucket"},
+			{Key: "b", Value: "bucket"},
+		}, nil)
+
+		sut := RemoteScheduler{
+			workerFactory:  workerFactoryMock,
+			configProvider: configProviderMock,
+			logger:         l,
+		}
+		sut.schedule(state)
+
+		workerMock.AssertNumberOfCalls(t, "Reconcile", 1)
+	})
+
+	t.Run("Keep fields of contract version 2 when merging bucket configuration", func(t *testing.T) {
+		componentsV2 := []keb.Components{
+			{Component: "logging", Version: "1.2.3", URL: "https://example.com/logging.tgz"},
+			{Component: "monitoring", Dependencies: []string{"logging"}},
+		}
+		componentsV2JSON, _ := json.Marshal(componentsV2)
+		stateV2 := cluster.State{
+			Cluster: &model.ClusterEntity{Cluster: "bucketCluster"},
+			Configuration: &model.ClusterConfigurationEntity{
+				Contract:   keb.ContractV2,
+				Components: string(componentsV2JSON),
+			},
+		}
+		bucketCfg := []keb.Configuration{{Key: "b", Value: "bucket"}}
+
+		var m sync.Mutex
+		var reconciled []*keb.Components
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				m.Lock()
+				defer m.Unlock()
+				reconciled = append(reconciled, args.Get(0).(*keb.Components))
+			})
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		configProviderMock := &MockConfigurationProvider{}
+		configProviderMock.On("Configuration", mock.Anything).Return(bucketCfg, nil)
+
+		sut := RemoteScheduler{
+			workerFactory:  workerFactoryMock,
+			configProvider: configProviderMock,
+			logger:         l,
+		}
+		sut.schedule(stateV2)
+
+		//dependencies are respected: logging is reconciled before monitoring
+		require.Equal(t, []*keb.Components{
+			{Component: "logging", Version: "1.2.3", URL: "https://example.com/logging.tgz", Configuration: bucketCfg},
+			{Component: "monitoring", Dependencies: []string{"logging"}, Configuration: bucketCfg},
+		}, reconciled)
+	})
+
+	t.Run("Skip reconciliation if bucket configuration is not available", func(t *testing.T) {
+		workerFactoryMock := &MockWorkerFactory{}
+
+		configProviderMock := &MockConfigurationProvider{}
+		configProviderMock.On("Configuration", mock.Anything).Return(nil, fmt.Errorf("database not reachable"))
+
+		sut := RemoteScheduler{
+			workerFactory:  workerFactoryMock,
+			configProvider: configProviderMock,
+			logger:         l,
+		}
+		sut.schedule(state)
+
+		workerFactoryMock.AssertNotCalled(t, "ForComponent", mock.Anything)
+	})
+}
+
 func TestLocalScheduler(t *testing.T) {
-	cluster := keb.Cluster{
+	kebCluster := keb.Cluster{
 		KymaConfig: keb.KymaConfig{
+			Version: "2.0.0",
+			Profile: "evaluation",
 			Components: []keb.Components{
 				{Component: "logging"},
 				{Component: "monitoring"},
 			},
 		},
+		Kubeconfig: "fake kubeconfig",
 	}
 
-	workerMock := &MockReconciliationWorker{}
-	workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	t.Run("Track successful reconciliation in inventory", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	workerFactoryMock := &MockWorkerFactory{}
-	workerFactoryMock.On("ForComponent", "logging").Return(workerMock, nil)
-	workerFactoryMock.On("ForComponent", "monitoring").Return(workerMock, nil)
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", "logging").Return(workerMock, nil)
+		workerFactoryMock.On("ForComponent", "monitoring").Return(workerMock, nil)
 
-	sut := LocalScheduler{
-		cluster:       cluster,
-		workerFactory: workerFactoryMock,
-	}
+		inventory := newTestInventory(t)
+		sut, err := NewLocalScheduler(kebCluster, inventory, workerFactoryMock, true)
+		require.NoError(t, err)
+
+		err = sut.Run(context.Background())
+		require.NoError(t, err)
+
+		workerFactoryMock.AssertNumberOfCalls(t, "ForComponent", 2)
+		workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
+
+		state, err := inventory.GetLatest(localClusterName)
+		require.NoError(t, err)
+		require.Equal(t, model.Ready, state.Status.Status)
+	})
+
+	t.Run("Track failed reconciliation in inventory", func(t *testing.T) {
+		workerMock := &MockReconciliationWorker{}
+		workerMock.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("reconciliation failed"))
+
+		workerFactoryMock := &MockWorkerFactory{}
+		workerFactoryMock.On("ForComponent", mock.Anything).Return(workerMock, nil)
+
+		inventory := newTestInventory(t)
+		sut, err := NewLocalScheduler(kebCluster, inventory, workerFactoryMock, true)
+		require.NoError(t, err)
+
+		err = sut.Run(context.Background())
+		require.Error(t, err)
 
-	err := sut.Run(context.Background())
+		state, err := inventory.GetLatest(localClusterName)
+		require.NoError(t, err)
+		require.Equal(t, model.ReconcileFailed, state.Status.Status)
+	})
+}
+
+func newTestInventory(t *testing.T) cluster.Inventory {
+	connFact, err := db.NewTestConnectionFactory()
 	require.NoError(t, err)
+	inventory, err := cluster.NewInventory(connFact, true, noopMetricsCollector{})
+	require.NoError(t, err)
+	return inventory
+}
 
-	workerFactoryMock.AssertNumberOfCalls(t, "ForComponent", 2)
-	workerMock.AssertNumberOfCalls(t, "Reconcile", 2)
+type noopMetricsCollector struct{}
+
+func (noopMetricsCollector) OnClusterStateUpdate(state *cluster.State) error {
+	return nil
 }
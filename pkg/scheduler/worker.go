@@ -8,9 +8,7 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/cluster"
 	"github.com/kyma-incubator/reconciler/pkg/keb"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
-	"github.com/kyma-incubator/reconciler/pkg/model"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -27,7 +25,6 @@ type ReconciliationWorker interface {
 type Worker struct {
 	correlationID string
 	config        *reconciler.ComponentReconciler
-	inventory     cluster.Inventory
 	operationsReg OperationsRegistry
 	invoker       ReconcilerInvoker
 	logger        *zap.SugaredLogger
@@ -36,7 +33,6 @@ type Worker struct {
 
 func NewWorker(
 	config *reconciler.ComponentReconciler,
-	inventory cluster.Inventory,
 	operationsReg OperationsRegistry,
 	invoker ReconcilerInvoker,
 	debug bool) (*Worker, error) {
@@ -47,7 +43,6 @@ func NewWorker(
 	return &Worker{
 		correlationID: uuid.NewString(),
 		config:        config,
-		inventory:     inventory,
 		operationsReg: operationsReg,
 		invoker:       invoker,
 		logger:        log,
@@ -57,10 +52,6 @@ func NewWorker(
 
 func (w *Worker) Reconcile(component *keb.Components, state cluster.State, schedulingID string, installCRD bool) error {
 	ticker := time.NewTicker(10 * time.Second)
-	_, err := w.inventory.UpdateStatus(&state, model.Reconciling)
-	if err != nil {
-		return errors.Wrap(err, "while updating cluster as reconciling")
-	}
 	for {
 		select {
 		case <-time.After(MaxDuration):
@@ -72,10 +63,6 @@ func (w *Worker) Reconcile(component *keb.Components, state cluster.State, sched
 				return err
 			}
 			if done {
-				_, err = w.inventory.UpdateStatus(&state, model.Ready)
-				if err != nil {
-					return errors.Wrap(err, "while updating cluster as ready")
-				}
 				return nil
 			}
 		}
@@ -174,11 +161,13 @@ func (w *Worker) getDoneComponents(schedulingID string) ([]string, error) {
 }
 
 func mapConfiguration(kebCfg []keb.Configuration) []reconciler.Configuration {
-	reconcilerCfg := make([]reconciler.Configuration, len(kebCfg))
+	reconcilerCfg := make([]reconciler.Configuration, 0, len(kebCfg))
 	for _, k := range kebCfg {
 		reconcilerCfg = append(reconcilerCfg, reconciler.Configuration{
-			Key:   k.Key,
-			Value: k.Value,
+			Key:      k.Key,
+			Value:    k.Value,
+			Secret:   k.Secret,
+			DataType: k.DataType,
 		})
 	}
 	return reconcilerCfg
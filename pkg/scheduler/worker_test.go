@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/keb"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapConfiguration(t *testing.T) {
+	result := mapConfiguration([]keb.Configuration{
+		{Key: "user", Value: "admin"},
+		{Key: "password", Value: "topSecret", Secret: true},
+		{Key: "hosts", Value: `["a", "b"]`, DataType: "list"},
+	})
+	require.Equal(t, []reconciler.Configuration{
+		{Key: "user", Value: "admin"},
+		{Key: "password", Value: "topSecret", Secret: true},
+		{Key: "hosts", Value: `["a", "b"]`, DataType: "list"},
+	}, result)
+}
@@ -1,7 +1,8 @@
 package scheduler
 
 import (
-	"github.com/kyma-incubator/reconciler/pkg/cluster"
+	"fmt"
+
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"go.uber.org/zap"
@@ -12,7 +13,6 @@ type WorkerFactory interface {
 }
 
 type baseWorkerFactory struct {
-	inventory     cluster.Inventory
 	operationsReg OperationsRegistry
 	invoker       ReconcilerInvoker
 	logger        *zap.SugaredLogger
@@ -26,7 +26,6 @@ type remoteWorkerFactory struct {
 }
 
 func NewRemoteWorkerFactory(
-	inventory cluster.Inventory,
 	reconcilersCfg reconciler.ComponentReconcilersConfig,
 	mothershipCfg reconciler.MothershipReconcilerConfig,
 	operationsReg OperationsRegistry,
@@ -37,14 +36,27 @@ func NewRemoteWorkerFactory(
 		return nil, err
 	}
 
+	scheme := mothershipCfg.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("scheme '%s' of the mothership callback URL is not supported (use http or https)", scheme)
+	}
+	if mothershipCfg.CallbackToken != "" && scheme != "https" {
+		return nil, fmt.Errorf("callback token requires the scheme https for the mothership callback URL " +
+			"(otherwise the token would be sent unencrypted)")
+	}
+
 	return &remoteWorkerFactory{
 		&baseWorkerFactory{
-			inventory:     inventory,
 			operationsReg: operationsReg,
 			invoker: &RemoteReconcilerInvoker{
-				logger:         log,
-				mothershipHost: mothershipCfg.Host,
-				mothershipPort: mothershipCfg.Port,
+				logger:           log,
+				mothershipScheme: scheme,
+				mothershipHost:   mothershipCfg.Host,
+				mothershipPort:   mothershipCfg.Port,
+				callbackToken:    mothershipCfg.CallbackToken,
 			},
 			logger: log,
 			debug:  debug,
@@ -66,7 +78,7 @@ func (rwf *remoteWorkerFactory) ForComponent(component string) (ReconciliationWo
 		}
 	}
 
-	return NewWorker(reconcilerCfg, rwf.inventory, rwf.operationsReg, rwf.invoker, rwf.debug)
+	return NewWorker(reconcilerCfg, rwf.operationsReg, rwf.invoker, rwf.debug)
 }
 
 type localWorkerFactory struct {
@@ -74,7 +86,6 @@ type localWorkerFactory struct {
 }
 
 func NewLocalWorkerFactory(
-	inventory cluster.Inventory,
 	operationsReg OperationsRegistry,
 	statusFunc ReconcilerStatusFunc,
 	debug bool) (WorkerFactory, error) {
@@ -86,7 +97,6 @@ func NewLocalWorkerFactory(
 
 	return &localWorkerFactory{
 		&baseWorkerFactory{
-			inventory:     inventory,
 			operationsReg: operationsReg,
 			invoker: &LocalReconcilerInvoker{
 				logger:        log,
@@ -100,5 +110,5 @@ func NewLocalWorkerFactory(
 }
 
 func (lwf *localWorkerFactory) ForComponent(component string) (ReconciliationWorker, error) {
-	return NewWorker(&reconciler.ComponentReconciler{}, lwf.inventory, lwf.operationsReg, lwf.invoker, lwf.debug)
+	return NewWorker(&reconciler.ComponentReconciler{}, lwf.operationsReg, lwf.invoker, lwf.debug)
 }
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteWorkerFactory(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        reconciler.MothershipReconcilerConfig
+		wantScheme string
+		wantErr    bool
+	}{
+		{
+			name:       "Default scheme",
+			cfg:        reconciler.MothershipReconcilerConfig{},
+			wantScheme: "http",
+		},
+		{
+			name:       "Callback token with https",
+			cfg:        reconciler.MothershipReconcilerConfig{Scheme: "https", CallbackToken: "token"},
+			wantScheme: "https",
+		},
+		{
+			name:    "Callback token without https",
+			cfg:     reconciler.MothershipReconcilerConfig{CallbackToken: "token"},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported scheme",
+			cfg:     reconciler.MothershipReconcilerConfig{Scheme: "ftp"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			factory, err := NewRemoteWorkerFactory(reconciler.ComponentReconcilersConfig{}, tc.cfg, nil, true)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			invoker := factory.(*remoteWorkerFactory).invoker.(*RemoteReconcilerInvoker)
+			require.Equal(t, tc.wantScheme, invoker.mothershipScheme)
+		})
+	}
+}
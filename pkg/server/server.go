@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"time"
 
@@ -15,8 +18,10 @@ type Webserver struct {
 	Port       int
 	SSLCrtFile string
 	SSLKeyFile string
-	Router     *mux.Router
-	server     *http.Server
+	//ClientCAFile contains the CA certificates used to verify TLS client certificates (optional, requires TLS)
+	ClientCAFile string
+	Router       *mux.Router
+	server       *http.Server
 }
 
 func (s *Webserver) logger() *zap.SugaredLogger {
@@ -28,15 +33,22 @@ func (s *Webserver) logger() *zap.SugaredLogger {
 
 func (s *Webserver) Start(ctx context.Context) error {
 	s.logger().Infof("Webserver starting and listening on port %d", s.Port)
-	s.startServer(s.Router)
+	if err := s.startServer(s.Router); err != nil {
+		return err
+	}
 	<-ctx.Done()
 	s.logger().Info("Webserver stopping (context got closed)")
 	return s.stopServer()
 }
 
-func (s *Webserver) startServer(router *mux.Router) {
+func (s *Webserver) startServer(router *mux.Router) error {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+
 	//start server
-	s.server = &http.Server{Addr: fmt.Sprintf(":%d", s.Port), Handler: router}
+	s.server = &http.Server{Addr: fmt.Sprintf(":%d", s.Port), Handler: router, TLSConfig: tlsConfig}
 	go func() {
 		var err error
 		if s.SSLCrtFile != "" && s.SSLKeyFile != "" {
@@ -48,6 +60,30 @@ func (s *Webserver) startServer(router *mux.Router) {
 			s.logger().Errorf("Webserver startup failed: %s", err)
 		}
 	}()
+	return nil
+}
+
+//tlsConfig returns the TLS configuration which requests client certificates if a client CA file was defined
+func (s *Webserver) tlsConfig() (*tls.Config, error) {
+	if s.ClientCAFile == "" {
+		return nil, nil
+	}
+	if s.SSLCrtFile == "" || s.SSLKeyFile == "" {
+		return nil, fmt.Errorf("verification of client certificates requires TLS: SSL certificate and key file are missing")
+	}
+	caCerts, err := ioutil.ReadFile(s.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCerts) {
+		return nil, fmt.Errorf("client CA file '%s' contains no PEM encoded certificates", s.ClientCAFile)
+	}
+	return &tls.Config{
+		ClientCAs: clientCAs,
+		//clients without certificate can still authenticate with other methods
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
 }
 
 func (s *Webserver) stopServer() error {